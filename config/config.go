@@ -1,28 +1,79 @@
 package config
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/jinzhu/configor"
 )
 
 type Config struct {
-	AppConfig  AppConfig  `env:"APPCONFIG"`
-	IRCConfig  IRCConfig  `env:"IRCCONFIG"`
-	DBConfig   DBConfig   `env:"DBCONFIG"`
-	GameConfig GameConfig `env:"GAMECONFIG"`
+	AppConfig       AppConfig       `env:"APPCONFIG"`
+	IRCConfig       IRCConfig       `env:"IRCCONFIG"`
+	DBConfig        DBConfig        `env:"DBCONFIG"`
+	GameConfig      GameConfig      `env:"GAMECONFIG"`
+	ThrottleConfig  ThrottleConfig  `env:"THROTTLECONFIG"`
+	LogConfig       LogConfig       `env:"LOGCONFIG"`
+	APIConfig       APIConfig       `env:"APICONFIG"`
+	DashboardConfig DashboardConfig `env:"DASHBOARDCONFIG"`
+
+	// JSON list of networks, see Networks. "" runs the single IRCConfig network.
+	NetworksFile string `env:"NETWORKS_FILE"`
 }
 
 type GameConfig struct {
 	SpawnWindowMinutes int `default:"30" env:"SPAWN_WINDOW_MINUTES"`
 	MinRespawnMinutes  int `default:"30" env:"MIN_RESPAWN_MINUTES"`
 	MaxRespawnMinutes  int `default:"30" env:"MAX_RESPAWN_MINUTES"`
+
+	// IANA timezone the game day (streaks, BondPoints, decay) follows,
+	// channels can override it with !purrito set timezone
+	Timezone string `default:"America/New_York" env:"GAME_TIMEZONE"`
+
+	// local time ("HH:MM") of the game day when love decays
+	DecayAt string `default:"00:00" env:"GAME_DECAY_AT"`
+
+	// fixed random seed to replay the same rolls, 0 = random
+	Seed int64 `env:"GAME_SEED"`
+}
+
+// ThrottleConfig limits how often commands can be used.
+// Limits are "count/duration", e.g. "5/30s"; "0" disables a limit.
+type ThrottleConfig struct {
+	Nick          string `default:"5/30s" env:"THROTTLE_NICK"`                        // per nick, per command
+	Host          string `default:"8/30s" env:"THROTTLE_HOST"`                        // per host, per command
+	Channel       string `default:"20/30s" env:"THROTTLE_CHANNEL"`                    // per channel, per command
+	Commands      string `default:"status=3/1m,toplove=2/1m" env:"THROTTLE_COMMANDS"` // command[:scope]=count/duration,...
+	Silent        bool   `default:"false" env:"THROTTLE_SILENT"`                      // drop silently instead of one "slow down" notice
+	IgnoreAfter   int    `default:"10" env:"THROTTLE_IGNORE_AFTER"`                   // violations per minute before a host is ignored
+	IgnoreMinutes int    `default:"10" env:"THROTTLE_IGNORE_MINUTES"`
+}
+
+// LogConfig configures the structured log on stderr.
+type LogConfig struct {
+	Level  string `default:"info" env:"LOG_LEVEL"`  // debug | info | warn | error
+	Format string `default:"text" env:"LOG_FORMAT"` // text | json
+}
+
+// APIConfig configures the read-only JSON API served next to the healthcheck.
+type APIConfig struct {
+	Disabled    bool   `env:"API_DISABLED"`
+	CORSOrigins string `env:"API_CORS_ORIGINS"` // comma separated, "*" = any site, "" = none
+	MaxPageSize int    `default:"100" env:"API_MAX_PAGE_SIZE"`
+}
+
+// DashboardConfig configures the web dashboard served at /dashboard/.
+type DashboardConfig struct {
+	Disabled bool `env:"DASHBOARD_DISABLED"`
 }
 
 type AppConfig struct {
 	APPName string `default:"purrito"`
 	Version string `default:"x.x.x" env:"VERSION"`
 	Port    int    `default:"8080" env:"APP_PORT"`
+
+	ShutdownTimeoutSeconds int `default:"10" env:"SHUTDOWN_TIMEOUT_SECONDS"`  // drain + QUIT on SIGINT/SIGTERM
+	LivenessTimeoutSeconds int `default:"900" env:"LIVENESS_TIMEOUT_SECONDS"` // /livez fails once a network is down this long, 0 = never
 }
 
 type IRCConfig struct {
@@ -34,12 +85,61 @@ type IRCConfig struct {
 	ChannelsString   string `env:"CHANNELS"`
 	Channels         []string
 	Network          string `env:"NETWORK"`
-	NickservCommand  string `env:"NICKSERV_COMMAND" default:"PRIVMSG NickServ IDENTIFY %s"`
+	NickservCommand  string `env:"NICKSERV_COMMAND" default:"PRIVMSG NickServ :IDENTIFY %s"`
 	NickservPassword string `env:"NICKSERV_PASSWORD" default:""`
 	Password         string `env:"PASSWORD" default:""`
+	PrefixesString   string `env:"COMMAND_PREFIXES" default:"!"` // comma separated, e.g. "!,."
+	Prefixes         []string
+	HelpDelivery     string  `env:"HELP_DELIVERY" default:"notice"` // notice | privmsg | channel
+	HelpPageSize     int     `env:"HELP_PAGE_SIZE" default:"10"`
+	Language         string  `env:"LANGUAGE" default:"en"`    // en | th
+	FloodRate        float64 `env:"FLOOD_RATE" default:"0.5"` // messages per second per target
+	FloodBurst       int     `env:"FLOOD_BURST" default:"4"`
+	FloodGlobalRate  float64 `env:"FLOOD_GLOBAL_RATE" default:"1"` // messages per second overall
+	MaxLineBytes     int     `env:"MAX_LINE_BYTES" default:"400"`
+	QuitMessage      string  `env:"QUIT_MESSAGE" default:"Purrito curls up for a nap 💤"`
+
+	// NickServ: channels are joined once services confirm IDENTIFY; a nick
+	// held by a ghost is taken back with regain | ghost | none
+	NickservRegain         string `env:"NICKSERV_REGAIN" default:"regain"`
+	NickservTimeoutSeconds int    `env:"NICKSERV_TIMEOUT_SECONDS" default:"15"`
+	NickservRetries        int    `env:"NICKSERV_RETRIES" default:"2"`
+
+	// SASL during registration: plain | external ("" = off). PLAIN falls back
+	// to Nick / NickservPassword, EXTERNAL needs the client certificate below
+	SaslMechanism string `env:"SASL_MECHANISM" default:""`
+	SaslUser      string `env:"SASL_USER" default:""`
+	SaslPassword  string `env:"SASL_PASSWORD" default:""`
+
+	// TLS (with SSL): the server certificate is verified unless TLSInsecure;
+	// pins are SHA-256 fingerprints of the server certificate, comma separated
+	TLSInsecure   bool   `env:"TLS_INSECURE" default:"false"`
+	TLSCAFile     string `env:"TLS_CA_FILE" default:""`
+	TLSServerName string `env:"TLS_SERVER_NAME" default:""` // SNI / name to verify, default Host
+	TLSPinsString string `env:"TLS_PINS" default:""`
+	TLSPins       []string
+	TLSCertFile   string `env:"TLS_CERT_FILE" default:""` // client certificate (CertFP, SASL EXTERNAL)
+	TLSKeyFile    string `env:"TLS_KEY_FILE" default:""`
+
+	// reconnect after a lost connection: exponential backoff with jitter
+	ReconnectMinSeconds int `env:"RECONNECT_MIN_SECONDS" default:"5"`
+	ReconnectMaxSeconds int `env:"RECONNECT_MAX_SECONDS" default:"300"`
+
+	// admin commands: comma separated hostmasks (nick!user@host, * and ? wildcards)
+	// and services accounts (needs the IRCv3 account-tag capability)
+	AdminMasksString    string `env:"ADMIN_MASKS" default:""`
+	AdminMasks          []string
+	AdminAccountsString string `env:"ADMIN_ACCOUNTS" default:""`
+	AdminAccounts       []string
+	AdminRequireOp      bool   `env:"ADMIN_REQUIRE_OP" default:"false"` // admins must also be op in the channel
+	BanMasksString      string `env:"BAN_MASKS" default:""`             // hostmasks the bot ignores
+	BanMasks            []string
+	AuditLog            string `env:"AUDIT_LOG" default:""` // file admin actions are appended to, "" = stdout
 }
 
 type DBConfig struct {
+	Driver   string `default:"postgres" env:"DBDRIVER"` // postgres | sqlite
+	Path     string `default:"catbot.db" env:"DBPATH"`  // sqlite database file
 	Host     string `default:"localhost" env:"DBHOST"`
 	DataBase string `default:"catbot" env:"DBNAME"`
 	User     string `default:"postgres" env:"DBUSERNAME"`
@@ -48,11 +148,44 @@ type DBConfig struct {
 	SSLMode  string `default:"disable" env:"DBSSL"`
 }
 
-func LoadConfigOrPanic() Config {
+// LoadConfig reads the config file and the environment, failing on a
+// malformed file or value.
+func LoadConfig() (Config, error) {
 	var config = Config{}
-	configor.Load(&config, "config/config.dev.json")
+	if err := configor.Load(&config, "config/config.dev.json"); err != nil {
+		return Config{}, err
+	}
 
-	config.IRCConfig.Channels = strings.Split(config.IRCConfig.ChannelsString, ",")
+	config.IRCConfig.splitLists()
 
+	return config, nil
+}
+
+func LoadConfigOrPanic() Config {
+	config, err := LoadConfig()
+	if err != nil {
+		panic(fmt.Sprintf("load config: %v", err))
+	}
 	return config
 }
+
+// splitLists fills the list fields from their comma separated strings.
+func (c *IRCConfig) splitLists() {
+	c.Channels = strings.Split(c.ChannelsString, ",")
+	c.Prefixes = strings.Split(c.PrefixesString, ",")
+	c.AdminMasks = splitList(c.AdminMasksString)
+	c.AdminAccounts = splitList(c.AdminAccountsString)
+	c.BanMasks = splitList(c.BanMasksString)
+	c.TLSPins = splitList(c.TLSPinsString)
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
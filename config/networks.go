@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NetworkConfig is one IRC network Purrito connects to.
+type NetworkConfig struct {
+	IRC  IRCConfig
+	Game GameConfig
+}
+
+/*
+NETWORKS
+NETWORKS_FILE points at a JSON list of networks. Every entry starts as a copy
+of IRCConfig / GameConfig (env and config file) and overrides what it sets,
+with the same keys as config.dev.json, plus "game" for the game settings:
+
+	[
+	  {"network": "libera", "host": "irc.libera.chat", "port": 6697, "ssl": true,
+	   "channelsString": "#purrito", "nickservPassword": "..."},
+	  {"network": "darkworld", "host": "us.darkworld.network", "port": 6697, "ssl": true,
+	   "channelsString": "#cats,#lounge", "game": {"spawnWindowMinutes": 60}}
+	]
+
+Players and channel state are scoped by "network", so it must be unique.
+*/
+
+// Networks returns the networks to run: the ones in NetworksFile, or just
+// IRCConfig when there is no file.
+func (c Config) Networks() ([]NetworkConfig, error) {
+	if c.NetworksFile == "" {
+		return []NetworkConfig{{IRC: c.IRCConfig, Game: c.GameConfig}}, nil
+	}
+
+	data, err := os.ReadFile(c.NetworksFile)
+	if err != nil {
+		return nil, fmt.Errorf("read networks: %w", err)
+	}
+	return c.parseNetworks(data)
+}
+
+func (c Config) parseNetworks(data []byte) ([]NetworkConfig, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse networks: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("networks: the list is empty")
+	}
+
+	seen := make(map[string]bool)
+	out := make([]NetworkConfig, 0, len(raw))
+	for i, r := range raw {
+		n := NetworkConfig{IRC: c.IRCConfig, Game: c.GameConfig}
+		overlay := struct {
+			*IRCConfig
+			Game *GameConfig `json:"game"`
+		}{&n.IRC, &n.Game}
+		if err := json.Unmarshal(r, &overlay); err != nil {
+			return nil, fmt.Errorf("network %d: %w", i, err)
+		}
+		n.IRC.splitLists()
+
+		key := strings.ToLower(n.IRC.Network)
+		switch {
+		case key == "":
+			return nil, fmt.Errorf("network %d: \"network\" is required", i)
+		case n.IRC.Host == "":
+			return nil, fmt.Errorf("network %s: \"host\" is required", n.IRC.Network)
+		case seen[key]:
+			return nil, fmt.Errorf("network %s is listed twice", n.IRC.Network)
+		}
+		seen[key] = true
+		out = append(out, n)
+	}
+	return out, nil
+}
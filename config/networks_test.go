@@ -0,0 +1,58 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNetworks_SingleWithoutFile(t *testing.T) {
+	c := Config{IRCConfig: IRCConfig{Network: "darkworld", Host: "irc.example"}}
+	nets, err := c.Networks()
+	if err != nil || len(nets) != 1 || nets[0].IRC.Network != "darkworld" {
+		t.Fatalf("got %+v, %v", nets, err)
+	}
+}
+
+func TestNetworks_OverlayOnBase(t *testing.T) {
+	base := Config{
+		IRCConfig:  IRCConfig{Nick: "Purrito", Port: 6667, PrefixesString: "!", AdminMasksString: "*!*@staff"},
+		GameConfig: GameConfig{SpawnWindowMinutes: 30, MinRespawnMinutes: 30},
+	}
+	base.IRCConfig.splitLists()
+
+	nets, err := base.parseNetworks([]byte(`[
+		{"network": "libera", "host": "irc.libera.chat", "port": 6697, "channelsString": "#a,#b"},
+		{"network": "darkworld", "host": "dw", "nick": "Purr", "adminMasksString": "", "game": {"spawnWindowMinutes": 60}}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	libera, dw := nets[0], nets[1]
+	if libera.IRC.Port != 6697 || libera.IRC.Nick != "Purrito" || strings.Join(libera.IRC.Channels, " ") != "#a #b" {
+		t.Errorf("libera: %+v", libera.IRC)
+	}
+	if len(libera.IRC.AdminMasks) != 1 || libera.Game.SpawnWindowMinutes != 30 {
+		t.Errorf("libera should inherit admins and game settings: %+v %+v", libera.IRC.AdminMasks, libera.Game)
+	}
+	if dw.IRC.Nick != "Purr" || dw.IRC.Port != 6667 || len(dw.IRC.AdminMasks) != 0 {
+		t.Errorf("darkworld: %+v", dw.IRC)
+	}
+	if dw.Game.SpawnWindowMinutes != 60 || dw.Game.MinRespawnMinutes != 30 {
+		t.Errorf("darkworld game: %+v", dw.Game)
+	}
+}
+
+func TestNetworks_Validation(t *testing.T) {
+	for _, in := range []string{
+		`[]`,
+		`[{"host": "h"}]`,
+		`[{"network": "n"}]`,
+		`[{"network": "n", "host": "h"}, {"network": "N", "host": "h2"}]`,
+		`{"network": "n"}`,
+	} {
+		if _, err := (Config{}).parseNetworks([]byte(in)); err == nil {
+			t.Errorf("%s: expected an error", in)
+		}
+	}
+}
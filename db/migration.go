@@ -2,61 +2,91 @@ package db
 
 import (
 	"embed"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+
 	"github.com/MyelinBots/catbot-go/config"
 	"github.com/MyelinBots/catbot-go/internal/db"
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
 	"github.com/golang-migrate/migrate/v4/source"
 	"github.com/golang-migrate/migrate/v4/source/httpfs"
 	_ "github.com/golang-migrate/migrate/v4/source/httpfs"
-	"log"
-	"net/http"
 )
 
 var (
-	//go:embed migrations/*.sql
+	//go:embed migrations/*.sql migrations/sqlite/*.sql
 	migrations embed.FS
+
+	registerOnce sync.Once
 )
 
 type driver struct {
 	httpfs.PartialDriver
 }
 
+// Open reads "embed://" (postgres) or "embed://sqlite" (sqlite flavour).
 func (d *driver) Open(rawURL string) (source.Driver, error) {
-	err := d.PartialDriver.Init(http.FS(migrations), "migrations")
+	u, err := url.Parse(rawURL)
 	if err != nil {
 		return nil, err
 	}
 
-	return d, nil
+	path := "migrations"
+	if u.Host != "" {
+		path = "migrations/" + u.Host
+	}
+
+	nd := &driver{}
+	if err := nd.PartialDriver.Init(http.FS(migrations), path); err != nil {
+		return nil, err
+	}
+
+	return nd, nil
 }
+
 func getMigration() (*migrate.Migrate, error) {
 	cfg := config.LoadConfigOrPanic()
 	database := db.NewDatabase(cfg.DBConfig)
+	return newMigration(database, cfg.DBConfig.DataBase)
+}
+
+func newMigration(database *db.DB, dbName string) (*migrate.Migrate, error) {
 	sqldb, err := database.DB.DB()
 	if err != nil {
 		return nil, err
 	}
-	dbdriver, err := postgres.WithInstance(sqldb, &postgres.Config{})
-	if err != nil {
-		return nil, err
+
+	var (
+		dbdriver  migratedb.Driver
+		sourceURL string
+	)
+	switch database.Driver {
+	case db.DriverSQLite:
+		dbdriver, err = sqlite.WithInstance(sqldb, &sqlite.Config{})
+		sourceURL = "embed://sqlite"
+	case db.DriverPostgres:
+		dbdriver, err = postgres.WithInstance(sqldb, &postgres.Config{})
+		sourceURL = "embed://"
+	default:
+		return nil, fmt.Errorf("unsupported database driver %q", database.Driver)
 	}
-	// log files in migrations folder
-	files, err := migrations.ReadDir("migrations")
 	if err != nil {
 		return nil, err
 	}
-	for _, file := range files {
-		println(file.Name())
-	}
 
-	source.Register("embed", &driver{})
+	registerOnce.Do(func() { source.Register("embed", &driver{}) })
 
-	return migrate.NewWithDatabaseInstance("embed://", cfg.DBConfig.DataBase, dbdriver)
+	return migrate.NewWithDatabaseInstance(sourceURL, dbName, dbdriver)
 }
 
 func MigrateUp() error {
-	log.Println("Migrating up")
+	slog.Info("migrating up")
 	m, err := getMigration()
 	if err != nil {
 		return err
@@ -70,6 +100,25 @@ func MigrateUp() error {
 	return nil
 }
 
+// migrationDBName labels an already opened database in golang-migrate (the
+// name only shows up in its errors).
+const migrationDBName = "catbot"
+
+// MigrateDatabaseUp applies all embedded migrations to an already opened database.
+func MigrateDatabaseUp(database *db.DB) error {
+	m, err := newMigration(database, migrationDBName)
+	if err != nil {
+		return err
+	}
+
+	err = m.Up()
+	if err != nil && err != migrate.ErrNoChange {
+		return err
+	}
+
+	return nil
+}
+
 func MigrateDown() error {
 	m, err := getMigration()
 	if err != nil {
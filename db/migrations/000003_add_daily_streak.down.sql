@@ -0,0 +1,7 @@
+-- Remove daily bonding streak columns
+DROP INDEX IF EXISTS idx_cat_player_last_streak_at;
+
+ALTER TABLE cat_player
+    DROP COLUMN IF EXISTS current_streak,
+    DROP COLUMN IF EXISTS highest_streak,
+    DROP COLUMN IF EXISTS last_streak_at;
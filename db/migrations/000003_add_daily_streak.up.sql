@@ -0,0 +1,7 @@
+-- Add daily bonding streak columns
+ALTER TABLE cat_player
+    ADD COLUMN IF NOT EXISTS current_streak INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS highest_streak INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_streak_at TIMESTAMP NULL;
+
+CREATE INDEX IF NOT EXISTS idx_cat_player_last_streak_at ON cat_player (last_streak_at);
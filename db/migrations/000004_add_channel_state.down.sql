@@ -0,0 +1,3 @@
+-- Remove per-channel game state
+DROP TABLE IF EXISTS channel_player_state;
+DROP TABLE IF EXISTS channel_state;
@@ -0,0 +1,2 @@
+-- Remove per-channel setting overrides
+DROP TABLE IF EXISTS channel_settings;
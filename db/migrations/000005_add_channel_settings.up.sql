@@ -0,0 +1,10 @@
+-- Per-channel game setting overrides (!purrito set), one row per key
+CREATE TABLE IF NOT EXISTS channel_settings (
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    value TEXT NOT NULL,
+    updated_by TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (network, channel, name)
+);
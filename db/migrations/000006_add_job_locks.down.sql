@@ -0,0 +1,2 @@
+-- Remove job leases
+DROP TABLE IF EXISTS job_locks;
@@ -0,0 +1,6 @@
+-- Leases for jobs that must run on one replica at a time (daily decay)
+CREATE TABLE IF NOT EXISTS job_locks (
+    name TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    locked_until TIMESTAMP NOT NULL
+);
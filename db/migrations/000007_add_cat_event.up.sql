@@ -0,0 +1,16 @@
+-- Append-only log of interactions and daily decay, for history and disputes
+CREATE TABLE IF NOT EXISTS cat_event (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    player TEXT NOT NULL,
+    action TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    love_before INT NOT NULL,
+    love_after INT NOT NULL,
+    bond_points INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_cat_event_player ON cat_event (network, channel, player, created_at);
+CREATE INDEX IF NOT EXISTS idx_cat_event_channel ON cat_event (network, channel, created_at);
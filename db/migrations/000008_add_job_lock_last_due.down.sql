@@ -0,0 +1,2 @@
+-- Forget completed due times
+ALTER TABLE job_locks DROP COLUMN last_due;
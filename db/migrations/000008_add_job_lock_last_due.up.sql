@@ -0,0 +1,2 @@
+-- Remember the last completed due time, so a job runs once per due time across replicas
+ALTER TABLE job_locks ADD COLUMN last_due TIMESTAMP NULL;
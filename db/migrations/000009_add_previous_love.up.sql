@@ -0,0 +1,2 @@
+-- Keep the love before the last change, so AddLove can return it from its single upsert
+ALTER TABLE cat_player ADD COLUMN IF NOT EXISTS previous_love INT NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cat_player;
\ No newline at end of file
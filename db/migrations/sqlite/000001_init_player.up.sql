@@ -0,0 +1,16 @@
+CREATE TABLE cat_player (
+    id TEXT PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    name TEXT NOT NULL,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    love_meter INTEGER NOT NULL DEFAULT 0,
+    count INTEGER NOT NULL DEFAULT 0,
+    last_interacted_at TIMESTAMP NULL,
+    last_decay_at TIMESTAMP NULL,
+    perfect_drop_warned BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX idx_player_scope ON cat_player (name, network, channel);
+CREATE UNIQUE INDEX idx_player_unique ON cat_player (name, network, channel);
@@ -0,0 +1,6 @@
+-- Remove bond points system columns
+ALTER TABLE cat_player DROP COLUMN bond_points;
+ALTER TABLE cat_player DROP COLUMN bond_point_streak;
+ALTER TABLE cat_player DROP COLUMN highest_bond_streak;
+ALTER TABLE cat_player DROP COLUMN last_bond_points_at;
+ALTER TABLE cat_player DROP COLUMN gifts_unlocked;
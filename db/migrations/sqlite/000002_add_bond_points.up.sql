@@ -0,0 +1,6 @@
+-- Add bond points system columns (SQLite adds one column per statement)
+ALTER TABLE cat_player ADD COLUMN bond_points INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cat_player ADD COLUMN bond_point_streak INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cat_player ADD COLUMN highest_bond_streak INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cat_player ADD COLUMN last_bond_points_at TIMESTAMP NULL;
+ALTER TABLE cat_player ADD COLUMN gifts_unlocked INTEGER NOT NULL DEFAULT 0;
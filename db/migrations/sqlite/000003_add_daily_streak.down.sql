@@ -0,0 +1,6 @@
+-- Remove daily bonding streak columns
+DROP INDEX IF EXISTS idx_cat_player_last_streak_at;
+
+ALTER TABLE cat_player DROP COLUMN current_streak;
+ALTER TABLE cat_player DROP COLUMN highest_streak;
+ALTER TABLE cat_player DROP COLUMN last_streak_at;
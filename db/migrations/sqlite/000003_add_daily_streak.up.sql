@@ -0,0 +1,6 @@
+-- Add daily bonding streak columns
+ALTER TABLE cat_player ADD COLUMN current_streak INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cat_player ADD COLUMN highest_streak INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE cat_player ADD COLUMN last_streak_at TIMESTAMP NULL;
+
+CREATE INDEX idx_cat_player_last_streak_at ON cat_player (last_streak_at);
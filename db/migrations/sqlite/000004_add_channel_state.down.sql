@@ -0,0 +1,3 @@
+-- Remove per-channel game state
+DROP TABLE channel_player_state;
+DROP TABLE channel_state;
@@ -0,0 +1,19 @@
+-- Per-channel game state (presence timers, catnip cooldowns, slap warnings)
+CREATE TABLE channel_state (
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    present_until TIMESTAMP NULL,
+    next_spawn_at TIMESTAMP NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (network, channel)
+);
+
+CREATE TABLE channel_player_state (
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    name TEXT NOT NULL,
+    catnip_used_at TIMESTAMP NULL,
+    slap_warned BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (network, channel, name)
+);
@@ -0,0 +1,2 @@
+-- Remove per-channel setting overrides
+DROP TABLE channel_settings;
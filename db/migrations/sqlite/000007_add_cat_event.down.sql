@@ -0,0 +1,2 @@
+-- Remove the interaction event log
+DROP TABLE IF EXISTS cat_event;
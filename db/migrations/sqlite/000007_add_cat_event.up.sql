@@ -0,0 +1,16 @@
+-- Append-only log of interactions and daily decay, for history and disputes
+CREATE TABLE cat_event (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    network TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    player TEXT NOT NULL,
+    action TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    love_before INTEGER NOT NULL,
+    love_after INTEGER NOT NULL,
+    bond_points INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_cat_event_player ON cat_event (network, channel, player, created_at);
+CREATE INDEX idx_cat_event_channel ON cat_event (network, channel, created_at);
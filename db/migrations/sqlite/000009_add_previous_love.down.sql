@@ -0,0 +1,2 @@
+-- Forget the love before the last change
+ALTER TABLE cat_player DROP COLUMN previous_love;
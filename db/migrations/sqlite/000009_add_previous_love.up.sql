@@ -0,0 +1,2 @@
+-- Keep the love before the last change, so AddLove can return it from its single upsert
+ALTER TABLE cat_player ADD COLUMN previous_love INTEGER NOT NULL DEFAULT 0;
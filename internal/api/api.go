@@ -0,0 +1,202 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/healthcheck"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+)
+
+/*
+API
+Read-only JSON for the community site, served on the healthcheck port:
+  GET /api/v1/channels
+  GET /api/v1/{network}/{channel}/leaderboard?page=1&per_page=25
+  GET /api/v1/{network}/{channel}/players/{nick}
+  GET /api/v1/{network}/{channel}/events?limit=20
+The channel may be given with its "#" escaped (%23cats) or without it
+(cats). Responses carry an ETag and answer If-None-Match with 304; CORS is
+limited to the configured origins.
+*/
+
+const (
+	defaultPageSize = 25
+	defaultMaxPage  = 100
+
+	// the deepest leaderboard row a page may start at, so (page-1)*per_page
+	// can't overflow
+	maxOffset = 1_000_000
+
+	// seconds browsers and proxies may cache a response; live data
+	// (presence, events) is polled by the dashboard so it isn't cached
+	cacheTables = 30
+	cacheLive   = 0
+)
+
+type Server struct {
+	players  cat_player.CatPlayerRepository
+	networks func() []healthcheck.NetworkStatus // nil = no channels
+	eventLog cat_event.CatEventRepository       // nil = no events
+
+	origins   map[string]bool
+	anyOrigin bool
+	maxPage   int
+
+	log *slog.Logger
+}
+
+// Option configures the API.
+type Option func(*Server)
+
+// WithCORSOrigins lets these sites call the API from a browser; "*" allows
+// any site (default: none).
+func WithCORSOrigins(origins ...string) Option {
+	return func(s *Server) {
+		for _, o := range origins {
+			o = strings.TrimRight(strings.TrimSpace(o), "/")
+			switch o {
+			case "":
+			case "*":
+				s.anyOrigin = true
+			default:
+				s.origins[strings.ToLower(o)] = true
+			}
+		}
+	}
+}
+
+// WithMaxPageSize caps per_page (default 100).
+func WithMaxPageSize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxPage = n
+		}
+	}
+}
+
+// WithNetworks lists the networks' channels and Purrito's presence,
+// usually healthcheck.Health.Networks.
+func WithNetworks(networks func() []healthcheck.NetworkStatus) Option {
+	return func(s *Server) { s.networks = networks }
+}
+
+// WithEventLog serves the channels' recent events from repo.
+func WithEventLog(repo cat_event.CatEventRepository) Option {
+	return func(s *Server) { s.eventLog = repo }
+}
+
+// WithLogger sets the logger (default: slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(s *Server) { s.log = l }
+}
+
+func New(players cat_player.CatPlayerRepository, opts ...Option) *Server {
+	s := &Server{
+		players: players,
+		origins: make(map[string]bool),
+		maxPage: defaultMaxPage,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.log = logging.Or(s.log)
+	return s
+}
+
+// Handler serves the API under /api/v1/.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/v1/channels", s.channels)
+	mux.HandleFunc("GET /api/v1/{network}/{channel}/leaderboard", s.leaderboard)
+	mux.HandleFunc("GET /api/v1/{network}/{channel}/players/{nick}", s.player)
+	mux.HandleFunc("GET /api/v1/{network}/{channel}/events", s.recentEvents)
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "not found")
+	})
+	return s.cors(mux)
+}
+
+/* CORS */
+
+func (s *Server) cors(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		if !s.anyOrigin {
+			h.Add("Vary", "Origin")
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && s.allowed(origin) {
+			if s.anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			h.Set("Access-Control-Expose-Headers", "ETag")
+		}
+
+		if r.Method == http.MethodOptions {
+			h.Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+			h.Set("Access-Control-Allow-Headers", "If-None-Match")
+			h.Set("Access-Control-Max-Age", "86400")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) allowed(origin string) bool {
+	return s.anyOrigin || s.origins[strings.ToLower(strings.TrimRight(origin, "/"))]
+}
+
+/* RESPONSES */
+
+// writeJSON sends v with an ETag, or 304 when the client already has it.
+// maxAge is how many seconds it may be cached (0 = revalidate every time).
+func writeJSON(w http.ResponseWriter, r *http.Request, maxAge int, v any) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "encoding failed")
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:12]) + `"`
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	if maxAge > 0 {
+		h.Set("Cache-Control", "public, max-age="+strconv.Itoa(maxAge))
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	w.Write(append(body, '\n'))
+}
+
+// etagMatches implements If-None-Match (weak comparison, "*" matches anything).
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
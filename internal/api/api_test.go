@@ -0,0 +1,248 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/healthcheck"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/lovemeter"
+)
+
+func newTestServer(t *testing.T, opts ...Option) http.Handler {
+	t.Helper()
+	repo := cat_player.NewMemoryPlayerRepository()
+	for i, love := range []int{10, 100, 40, 75, 0} {
+		p := &cat_player.CatPlayer{Name: "p" + strconv.Itoa(i), Network: "libera", Channel: "#cats", LoveMeter: love}
+		if err := repo.UpsertPlayer(context.Background(), p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bonded := &cat_player.CatPlayer{
+		Name: "alice", Network: "libera", Channel: "#cats", LoveMeter: 100,
+		CurrentStreak: 3, HighestStreak: 21, BondPoints: 12,
+		GiftsUnlocked: bondrewards.Gift7 | bondrewards.Gift21,
+	}
+	if err := repo.UpsertPlayer(context.Background(), bonded); err != nil {
+		t.Fatal(err)
+	}
+	return New(repo, append([]Option{WithLogger(logging.Discard())}, opts...)...).Handler()
+}
+
+func do(h http.Handler, method, path string, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestLeaderboard_Pagination(t *testing.T) {
+	h := newTestServer(t)
+
+	rec := do(h, http.MethodGet, "/api/v1/libera/%23cats/leaderboard?page=2&per_page=4", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var lb Leaderboard
+	if err := json.Unmarshal(rec.Body.Bytes(), &lb); err != nil {
+		t.Fatal(err)
+	}
+	if lb.Total != 6 || lb.Pages != 2 || lb.Page != 2 || len(lb.Players) != 2 {
+		t.Fatalf("unexpected page %+v", lb)
+	}
+	if lb.Players[0].Rank != 5 || lb.Players[0].LoveMeter != 10 || lb.Players[1].Rank != 6 || lb.Players[1].LoveMeter != 0 {
+		t.Errorf("unexpected players %+v", lb.Players)
+	}
+
+	// the channel prefix is optional
+	rec = do(h, http.MethodGet, "/api/v1/Libera/cats/leaderboard", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &lb); err != nil || lb.Channel != "#cats" || lb.Total != 6 || lb.PerPage != defaultPageSize {
+		t.Errorf("unprefixed channel: %v %+v", err, lb)
+	}
+
+	for _, q := range []string{"page=0", "page=x", "per_page=0", "per_page=101", "page=9223372036854775807", "page=1000002&per_page=1", "page=40002"} {
+		if rec := do(h, http.MethodGet, "/api/v1/libera/cats/leaderboard?"+q, nil); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", q, rec.Code)
+		}
+	}
+	// the last page in range is just empty
+	rec = do(h, http.MethodGet, "/api/v1/libera/cats/leaderboard?page=1000001&per_page=1", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &lb); err != nil || rec.Code != http.StatusOK || len(lb.Players) != 0 || lb.Total != 6 {
+		t.Errorf("last page: %d %v %+v", rec.Code, err, lb)
+	}
+}
+
+func TestPlayer_Profile(t *testing.T) {
+	h := newTestServer(t)
+
+	rec := do(h, http.MethodGet, "/api/v1/libera/cats/players/Alice", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	var p Player
+	if err := json.Unmarshal(rec.Body.Bytes(), &p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "alice" || !p.Bonded || p.Mood != "loves you 😻" || p.Title != "Warm Purr Companion 🐾" || p.LoveBar != lovemeter.RenderLoveBar(100) {
+		t.Errorf("unexpected profile %+v", p)
+	}
+	if p.Streak.Current != 3 || p.Streak.Highest != 21 || p.BondPoints.Total != 12 {
+		t.Errorf("unexpected progress %+v %+v", p.Streak, p.BondPoints)
+	}
+	if len(p.Gifts) != 2 || p.Gifts[0].ID != "tiny_guinea_pig" || p.Gifts[1].ID != "noisy_parrot" {
+		t.Errorf("unexpected gifts %+v", p.Gifts)
+	}
+
+	if rec := do(h, http.MethodGet, "/api/v1/libera/cats/players/nobody", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown player: status %d", rec.Code)
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/nope", nil); rec.Code != http.StatusNotFound {
+		t.Errorf("unknown route: status %d", rec.Code)
+	}
+}
+
+func TestETag(t *testing.T) {
+	h := newTestServer(t)
+
+	first := do(h, http.MethodGet, "/api/v1/libera/cats/players/alice", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag")
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/libera/cats/players/alice", map[string]string{"If-None-Match": "W/" + etag}); rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("matching ETag: status %d, body %q", rec.Code, rec.Body)
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/libera/cats/players/alice", map[string]string{"If-None-Match": `"stale"`}); rec.Code != http.StatusOK {
+		t.Errorf("stale ETag: status %d", rec.Code)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	h := newTestServer(t, WithCORSOrigins("https://purrito.example/", " https://cats.example"))
+
+	rec := do(h, http.MethodGet, "/api/v1/libera/cats/leaderboard", map[string]string{"Origin": "https://purrito.example"})
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://purrito.example" {
+		t.Errorf("allowed origin: got %q", got)
+	}
+	rec = do(h, http.MethodGet, "/api/v1/libera/cats/leaderboard", map[string]string{"Origin": "https://evil.example"})
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("other origin: got %q", got)
+	}
+
+	rec = do(h, http.MethodOptions, "/api/v1/libera/cats/leaderboard", map[string]string{
+		"Origin": "https://cats.example", "Access-Control-Request-Method": "GET",
+	})
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("preflight: status %d, headers %v", rec.Code, rec.Header())
+	}
+
+	any := newTestServer(t, WithCORSOrigins("*"))
+	rec = do(any, http.MethodGet, "/api/v1/libera/cats/leaderboard", map[string]string{"Origin": "https://anyone.example"})
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("any origin: got %q", got)
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	for in, want := range map[string]string{
+		"\x0304hostile 😾\x0F":       "hostile 😾",
+		"\x0304,01red on black\x0F": "red on black",
+		"\x02bold\x02 \x1Fu\x1F":    "bold u",
+		"\x03plain":                 "plain",
+		"100%":                      "100%",
+	} {
+		if got := plainText(in); got != want {
+			t.Errorf("plainText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestChannels(t *testing.T) {
+	until := time.Date(2024, 1, 15, 12, 5, 0, 0, time.UTC)
+	h := New(cat_player.NewMemoryPlayerRepository(), WithLogger(logging.Discard()),
+		WithNetworks(func() []healthcheck.NetworkStatus {
+			return []healthcheck.NetworkStatus{{Name: "Libera", Registered: true, Channels: []healthcheck.ChannelStatus{
+				{Name: "#Cats", Configured: true, Joined: true, Playing: true, Present: true, PresentUntil: &until},
+				{Name: "#dogs", Configured: true},
+			}}}
+		})).Handler()
+
+	rec := do(h, http.MethodGet, "/api/v1/channels", nil)
+	var out Channels
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Networks) != 1 || out.Networks[0].Name != "libera" || !out.Networks[0].Connected {
+		t.Fatalf("unexpected networks %+v", out.Networks)
+	}
+	chans := out.Networks[0].Channels
+	if len(chans) != 1 || chans[0].Name != "#cats" || !chans[0].Present || !chans[0].PresentUntil.Equal(until) {
+		t.Errorf("only joined channels with their presence, got %+v", chans)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("live data cached: %q", cc)
+	}
+}
+
+func TestRecentEvents(t *testing.T) {
+	log := cat_event.NewMemoryCatEventRepository()
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, e := range []cat_event.CatEvent{
+		{CreatedAt: at, Player: "alice", Action: "pet", Outcome: cat_event.OutcomeAccepted, LoveBefore: 99, LoveAfter: 100, BondPoints: 2},
+		{CreatedAt: at.Add(time.Minute), Player: "bob", Action: "feed", Outcome: cat_event.OutcomeRejected, LoveBefore: 4, LoveAfter: 3},
+		{CreatedAt: at.Add(2 * time.Minute), Player: "carol", Action: cat_event.ActionDecay, Outcome: cat_event.OutcomeDecayed, LoveBefore: 100, LoveAfter: 95},
+	} {
+		e.Network, e.Channel = "libera", "#cats"
+		if err := log.Append(context.Background(), &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := New(cat_player.NewMemoryPlayerRepository(), WithLogger(logging.Discard()), WithEventLog(log)).Handler()
+
+	rec := do(h, http.MethodGet, "/api/v1/libera/cats/events?limit=2", nil)
+	var out Events
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	want := []Event{
+		{At: at.Add(2 * time.Minute), Player: "carol", Action: cat_event.ActionDecay, Result: cat_event.OutcomeDecayed, LoveBefore: 100, LoveMeter: 95},
+		{At: at.Add(time.Minute), Player: "bob", Action: "feed", Result: cat_event.OutcomeRejected, LoveBefore: 4, LoveMeter: 3},
+	}
+	if len(out.Events) != len(want) {
+		t.Fatalf("got %+v, want %+v", out.Events, want)
+	}
+	for i := range want {
+		if got := out.Events[i]; !got.At.Equal(want[i].At) || got.Player != want[i].Player || got.Action != want[i].Action ||
+			got.Result != want[i].Result || got.LoveBefore != want[i].LoveBefore || got.LoveMeter != want[i].LoveMeter || got.BondPoints != 0 {
+			t.Errorf("event %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+	rec = do(h, http.MethodGet, "/api/v1/libera/cats/events", nil)
+	if err := json.Unmarshal(rec.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Events) != 3 || out.Events[2].BondPoints != 2 {
+		t.Errorf("unexpected events %+v", out.Events)
+	}
+	if rec := do(h, http.MethodGet, "/api/v1/libera/cats/events?limit=0", nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("limit=0: status %d", rec.Code)
+	}
+
+	// without an event log the list is just empty
+	none := newTestServer(t)
+	if rec := do(none, http.MethodGet, "/api/v1/libera/cats/events", nil); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"events":[]`) {
+		t.Errorf("no event log: %d %s", rec.Code, rec.Body)
+	}
+}
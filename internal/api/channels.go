@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+)
+
+const defaultEvents = 20
+
+// Channels lists where Purrito plays, for the dashboard's front page.
+type Channels struct {
+	Networks []Network `json:"networks"`
+}
+
+type Network struct {
+	Name      string    `json:"name"`
+	Connected bool      `json:"connected"`
+	Channels  []Channel `json:"channels"`
+}
+
+// Channel is one joined channel with Purrito's live presence.
+type Channel struct {
+	Name         string     `json:"name"`
+	Present      bool       `json:"present"`
+	PresentUntil *time.Time `json:"present_until,omitempty"`
+	NextSpawnAt  *time.Time `json:"next_spawn_at,omitempty"`
+}
+
+// Events is a channel's recent events from the event log, newest first.
+type Events struct {
+	Network string  `json:"network"`
+	Channel string  `json:"channel"`
+	Events  []Event `json:"events"`
+}
+
+type Event struct {
+	At         time.Time `json:"at"`
+	Player     string    `json:"player"`
+	Action     string    `json:"action"` // an interaction, decay, bond, setlove or setbp
+	Result     string    `json:"result"` // accepted | rejected | warned | punished | decayed | awarded | set
+	LoveBefore int       `json:"love_before"`
+	LoveMeter  int       `json:"love_meter"` // after the event
+	BondPoints int       `json:"bond_points"`
+}
+
+func (s *Server) channels(w http.ResponseWriter, r *http.Request) {
+	out := Channels{Networks: []Network{}}
+	if s.networks == nil {
+		writeJSON(w, r, cacheLive, out)
+		return
+	}
+	for _, n := range s.networks() {
+		network := Network{Name: strings.ToLower(n.Name), Connected: n.Registered, Channels: []Channel{}}
+		for _, ch := range n.Channels {
+			// configured but not (yet) joined: nothing to show
+			if !ch.Joined && !ch.Playing {
+				continue
+			}
+			network.Channels = append(network.Channels, Channel{
+				Name:         strings.ToLower(ch.Name),
+				Present:      ch.Present,
+				PresentUntil: ch.PresentUntil,
+				NextSpawnAt:  ch.NextSpawnAt,
+			})
+		}
+		out.Networks = append(out.Networks, network)
+	}
+	writeJSON(w, r, cacheLive, out)
+}
+
+func (s *Server) recentEvents(w http.ResponseWriter, r *http.Request) {
+	network, channel := scope(r)
+	limit, ok := queryInt(r, "limit", defaultEvents)
+	if !ok || limit < 1 || limit > s.maxPage {
+		writeError(w, http.StatusBadRequest, "limit must be a number from 1 to "+strconv.Itoa(s.maxPage))
+		return
+	}
+
+	out := Events{Network: network, Channel: channel, Events: []Event{}}
+	if s.eventLog == nil {
+		writeJSON(w, r, cacheLive, out)
+		return
+	}
+	history, err := s.eventLog.History(r.Context(), network, channel, cat_event.Query{Limit: limit})
+	if err != nil {
+		s.log.Error("failed to load events", "network", network, "channel", channel, "error", err)
+		writeError(w, http.StatusServiceUnavailable, "storage unavailable")
+		return
+	}
+	for _, e := range history {
+		out.Events = append(out.Events, Event{
+			At:         e.CreatedAt,
+			Player:     e.Player,
+			Action:     e.Action,
+			Result:     e.Outcome,
+			LoveBefore: e.LoveBefore,
+			LoveMeter:  e.LoveAfter,
+			BondPoints: e.BondPoints,
+		})
+	}
+	writeJSON(w, r, cacheLive, out)
+}
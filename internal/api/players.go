@@ -0,0 +1,195 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/lovemeter"
+)
+
+// Player is a player's profile in one channel.
+type Player struct {
+	Rank             int        `json:"rank,omitempty"` // leaderboard only
+	Name             string     `json:"name"`
+	LoveMeter        int        `json:"love_meter"`
+	LoveBar          string     `json:"love_bar"`
+	Mood             string     `json:"mood"`
+	Bonded           bool       `json:"bonded"`
+	Title            string     `json:"title"`
+	Streak           Streak     `json:"streak"`
+	BondPoints       BondPoints `json:"bond_points"`
+	Gifts            []Gift     `json:"gifts"`
+	FirstSeenAt      time.Time  `json:"first_seen_at"`
+	LastInteractedAt *time.Time `json:"last_interacted_at,omitempty"`
+}
+
+// Streak is the daily bonding streak behind titles and gifts.
+type Streak struct {
+	Current int        `json:"current"`
+	Highest int        `json:"highest"`
+	LastAt  *time.Time `json:"last_at,omitempty"`
+}
+
+// BondPoints are earned by Forever Humans once a day.
+type BondPoints struct {
+	Total         int        `json:"total"`
+	Streak        int        `json:"streak"`
+	HighestStreak int        `json:"highest_streak"`
+	LastAt        *time.Time `json:"last_at,omitempty"`
+}
+
+// Gift is a streak reward the player unlocked.
+type Gift struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Leaderboard is one page of a channel's players by love.
+type Leaderboard struct {
+	Network string   `json:"network"`
+	Channel string   `json:"channel"`
+	Page    int      `json:"page"`
+	PerPage int      `json:"per_page"`
+	Total   int      `json:"total"`
+	Pages   int      `json:"pages"`
+	Players []Player `json:"players"`
+}
+
+func (s *Server) leaderboard(w http.ResponseWriter, r *http.Request) {
+	network, channel := scope(r)
+	perPage, ok := queryInt(r, "per_page", defaultPageSize)
+	if !ok || perPage < 1 || perPage > s.maxPage {
+		writeError(w, http.StatusBadRequest, "per_page must be a number from 1 to "+strconv.Itoa(s.maxPage))
+		return
+	}
+	lastPage := maxOffset/perPage + 1
+	page, ok := queryInt(r, "page", 1)
+	if !ok || page < 1 || page > lastPage {
+		writeError(w, http.StatusBadRequest, "page must be a number from 1 to "+strconv.Itoa(lastPage))
+		return
+	}
+
+	offset := (page - 1) * perPage
+	players, total, err := s.players.PageByLove(r.Context(), network, channel, offset, perPage)
+	if err != nil {
+		s.log.Error("failed to load leaderboard", "network", network, "channel", channel, "error", err)
+		writeError(w, http.StatusServiceUnavailable, "storage unavailable")
+		return
+	}
+
+	out := Leaderboard{
+		Network: network,
+		Channel: channel,
+		Page:    page,
+		PerPage: perPage,
+		Total:   total,
+		Pages:   (total + perPage - 1) / perPage,
+		Players: make([]Player, 0, len(players)),
+	}
+	for i, p := range players {
+		profile := toPlayer(p)
+		profile.Rank = offset + i + 1
+		out.Players = append(out.Players, profile)
+	}
+	writeJSON(w, r, cacheTables, out)
+}
+
+func (s *Server) player(w http.ResponseWriter, r *http.Request) {
+	network, channel := scope(r)
+	nick := strings.TrimSpace(r.PathValue("nick"))
+
+	p, err := s.players.GetPlayerByName(r.Context(), nick, network, channel)
+	if err != nil {
+		s.log.Error("failed to load player", "network", network, "channel", channel, "nick", nick, "error", err)
+		writeError(w, http.StatusServiceUnavailable, "storage unavailable")
+		return
+	}
+	if p == nil {
+		writeError(w, http.StatusNotFound, "player not found")
+		return
+	}
+	writeJSON(w, r, cacheTables, toPlayer(p))
+}
+
+func toPlayer(p *cat_player.CatPlayer) Player {
+	love := lovemeter.ClampLove(p.LoveMeter)
+	out := Player{
+		Name:      p.Name,
+		LoveMeter: love,
+		LoveBar:   lovemeter.RenderLoveBar(love),
+		Mood:      plainText(lovemeter.MoodFor(love)),
+		Bonded:    lovemeter.IsBonded(love),
+		Title:     plainText(bondrewards.TitleForHighestStreak(p.HighestStreak)),
+		Streak: Streak{
+			Current: p.CurrentStreak,
+			Highest: p.HighestStreak,
+			LastAt:  p.LastStreakAt,
+		},
+		BondPoints: BondPoints{
+			Total:         p.BondPoints,
+			Streak:        p.BondPointStreak,
+			HighestStreak: p.HighestBondStreak,
+			LastAt:        p.LastBondPointsAt,
+		},
+		Gifts:            []Gift{},
+		FirstSeenAt:      p.CreatedAt,
+		LastInteractedAt: p.LastInteractedAt,
+	}
+	for _, g := range bondrewards.GiftsFromMask(p.GiftsUnlocked) {
+		out.Gifts = append(out.Gifts, Gift{ID: g.Key, Name: g.Name})
+	}
+	return out
+}
+
+// scope reads the network and channel from the path; a channel without its
+// prefix gets "#".
+func scope(r *http.Request) (network, channel string) {
+	network = strings.ToLower(strings.TrimSpace(r.PathValue("network")))
+	channel = strings.ToLower(strings.TrimSpace(r.PathValue("channel")))
+	if channel != "" && !strings.ContainsAny(channel[:1], "#&+!") {
+		channel = "#" + channel
+	}
+	return network, channel
+}
+
+func queryInt(r *http.Request, key string, def int) (int, bool) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return def, true
+	}
+	n, err := strconv.Atoi(v)
+	return n, err == nil
+}
+
+// plainText drops mIRC colour and formatting codes.
+func plainText(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 0x02, 0x0F, 0x11, 0x16, 0x1D, 0x1E, 0x1F:
+			continue
+		case 0x03:
+			// \x03[fg[,bg]], up to two digits each
+			i += digits(s, i+1)
+			if i+2 < len(s) && s[i+1] == ',' && digits(s, i+2) > 0 {
+				i += 1 + digits(s, i+2)
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// digits counts the (at most two) ASCII digits at s[i:].
+func digits(s string, i int) int {
+	n := 0
+	for n < 2 && i+n < len(s) && s[i+n] >= '0' && s[i+n] <= '9' {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"math/rand"
+	"time"
+)
+
+// backoff gives reconnect delays: exponential from min up to max, with jitter so
+// several bots (or networks) don't hammer the server in lock step.
+type backoff struct {
+	min, max time.Duration
+	attempt  int
+	rand     func() float64
+}
+
+func newBackoff(min, max time.Duration) *backoff {
+	if min <= 0 {
+		min = time.Second
+	}
+	if max < min {
+		max = min
+	}
+	return &backoff{min: min, max: max, rand: rand.Float64}
+}
+
+// Next returns the delay before the next attempt: a random value in [d/2, d]
+// where d = min * 2^attempt, capped at max.
+func (b *backoff) Next() time.Duration {
+	d := b.min
+	for i := 0; i < b.attempt && d < b.max; i++ {
+		d *= 2
+	}
+	if d > b.max {
+		d = b.max
+	}
+	b.attempt++
+
+	half := d / 2
+	return half + time.Duration(b.rand()*float64(d-half))
+}
+
+// Reset starts over from min, after a connection that stayed up.
+func (b *backoff) Reset() { b.attempt = 0 }
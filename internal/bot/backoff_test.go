@@ -0,0 +1,23 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoff_ExponentialCappedWithJitter(t *testing.T) {
+	b := newBackoff(time.Second, 10*time.Second)
+
+	b.rand = func() float64 { return 1 }
+	for i, want := range []time.Duration{1, 2, 4, 8, 10, 10} {
+		if got := b.Next(); got != want*time.Second {
+			t.Errorf("attempt %d: got %v, want %v", i, got, want*time.Second)
+		}
+	}
+
+	b.Reset()
+	b.rand = func() float64 { return 0 }
+	if got := b.Next(); got != 500*time.Millisecond {
+		t.Errorf("lowest jitter is half the delay, got %v", got)
+	}
+}
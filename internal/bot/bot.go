@@ -2,30 +2,85 @@ package bot
 
 import (
 	"context"
-	"crypto/tls"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/api"
+	"github.com/MyelinBots/catbot-go/internal/dashboard"
 	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_settings"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/job_lock"
 	"github.com/MyelinBots/catbot-go/internal/healthcheck"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
+	"github.com/MyelinBots/catbot-go/internal/services/auth"
 	"github.com/MyelinBots/catbot-go/internal/services/catbot"
 	"github.com/MyelinBots/catbot-go/internal/services/commands"
+	"github.com/MyelinBots/catbot-go/internal/services/throttle"
 	irc "github.com/fluffle/goirc/client"
 )
 
-type Identified struct {
-	sync.Mutex
-	identified bool
-}
-
 type GameInstances struct {
 	sync.Mutex
 	GameStarted      map[string]bool
 	games            map[string]*catbot.CatBot
 	commandInstances map[string]commands.CommandController
+	joined           map[string]bool // channels we are in, re-joined after a reconnect
+	wg               sync.WaitGroup  // running game loops
+}
+
+// start runs channel's game loop unless it is already running. Caller holds the lock.
+func (g *GameInstances) start(ctx context.Context, channel string) {
+	if g.GameStarted[channel] {
+		return
+	}
+	game, ok := g.games[channel]
+	if !ok {
+		return
+	}
+	g.GameStarted[channel] = true
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		game.Start(ctx)
+	}()
+}
+
+// channels lists what to join on (re)connect: configured channels first, then
+// channels joined later through !invite or !join.
+func (g *GameInstances) channels(configured []string) []string {
+	g.Lock()
+	defer g.Unlock()
+
+	seen := make(map[string]bool)
+	var out []string
+	for _, ch := range configured {
+		seen[strings.ToLower(ch)] = true
+		out = append(out, ch)
+	}
+	for ch := range g.joined {
+		if !seen[strings.ToLower(ch)] {
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// Options controls how StartBot wires its dependencies.
+type Options struct {
+	// Memory keeps all game state in memory (no database, nothing persisted).
+	Memory bool
 }
 
 // adaptVarArgs converts: func(ctx, ...string) -> func(ctx, message string)
@@ -37,219 +92,244 @@ func adaptVarArgs(h func(context.Context, ...string) error) func(context.Context
 	}
 }
 
-func StartBot() error {
-	cfg := config.LoadConfigOrPanic()
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
-
-	identified := &Identified{}
-
-	fmt.Printf("Starting bot with config: %+v\n", cfg)
-	healthcheck.StartHealthcheck(ctx, cfg.AppConfig)
-
-	// ---- IRC config (with PASS) ----
-	ircConfig := irc.NewConfig(cfg.IRCConfig.Nick)
-	ircConfig.SSL = cfg.IRCConfig.SSL
-	ircConfig.SSLConfig = &tls.Config{InsecureSkipVerify: true}
-	ircConfig.Server = fmt.Sprintf("%s:%d", cfg.IRCConfig.Host, cfg.IRCConfig.Port)
-	ircConfig.Me.Ident = cfg.IRCConfig.User
-	ircConfig.Pass = cfg.IRCConfig.Password
+// storage is shared by every network; rows are scoped by network name.
+type storage struct {
+	players  cat_player.CatPlayerRepository
+	state    channel_state.ChannelStateRepository
+	settings channel_settings.ChannelSettingsRepository
+	locks    job_lock.JobLockRepository
+	eventLog cat_event.CatEventRepository
 
-	conn := irc.Client(ircConfig)
+	// replica identifies this process in job leases (host:pid)
+	replica string
+}
 
-	// ---- DB: open ONCE and migrate ----
-	database := db.NewDatabase(cfg.DBConfig)
-	if database == nil || database.DB == nil {
-		return fmt.Errorf("db init failed")
-	}
-	if err := database.DB.AutoMigrate(&cat_player.CatPlayer{}); err != nil {
-		return fmt.Errorf("migrate cat_player failed: %w", err)
-	}
+// StartBot runs every configured network until ctx is cancelled. The networks
+// share the database, the audit log and the HTTP server (health and API); each one has its own
+// connection, channels, credentials and game settings. A network that fails
+// to start is logged and the others keep running.
+func StartBot(ctx context.Context, opts Options) error {
+	cfg := config.LoadConfigOrPanic()
 
-	gameInstances := &GameInstances{
-		games:            make(map[string]*catbot.CatBot),
-		commandInstances: make(map[string]commands.CommandController),
-		GameStarted:      make(map[string]bool),
+	logger, err := logging.New(cfg.LogConfig, os.Stderr)
+	if err != nil {
+		return err
 	}
+	// anything still using the log package goes through the same handler
+	slog.SetDefault(logger)
 
-	// Convert game config to durations
-	spawnWindow := time.Duration(cfg.GameConfig.SpawnWindowMinutes) * time.Minute
-	minRespawn := time.Duration(cfg.GameConfig.MinRespawnMinutes) * time.Minute
-	maxRespawn := time.Duration(cfg.GameConfig.MaxRespawnMinutes) * time.Minute
-
-	// helper: init a channel's game+commands in one place (reuse database)
-	initChannel := func(channel string) error {
-		repo := cat_player.NewPlayerRepository(database)
-		game := catbot.NewCatBot(conn, repo, cfg.IRCConfig.Network, channel, spawnWindow, minRespawn, maxRespawn)
-
-		// cast once เพื่อเรียก handler methods ได้ตรงๆ
-		cmdController := commands.NewCommandController(game)
-		cmds, ok := cmdController.(*commands.CommandControllerImpl)
-		if !ok {
-			return fmt.Errorf("failed to cast command controller")
-		}
-
-		// basic purrito commands -> game.HandleCatCommand (varargs) ต้อง adapt
-		cmds.AddCommand("!pet", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!love", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!slap", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!feed", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!status", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!catnip", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!kick", adaptVarArgs(game.HandleCatCommand))
-		cmds.AddCommand("!laser", cmds.PurritoLaserHandler())
-
-		// extra commands
-		// InviteHandler เป็น varargs -> adapt
-		cmds.AddCommand("!invite", adaptVarArgs(commands.InviteHandler(conn)))
-
-		// 3 อันนี้ signature เป็น (ctx, message string) อยู่แล้ว -> ไม่ต้อง adapt
-		cmds.AddCommand("!toplove", adaptVarArgs(cmds.TopLove10Handler()))
-		cmds.AddCommand("!purrito", adaptVarArgs(cmds.PurritoHandler()))
-
-		gameInstances.games[channel] = game
-		gameInstances.commandInstances[channel] = cmds
-		gameInstances.GameStarted[channel] = false
-		return nil
+	logger.Info("starting bot", "config", logging.Redact(cfg))
+	networks, err := cfg.Networks()
+	if err != nil {
+		return err
 	}
+	health := healthcheck.New(cfg.AppConfig, healthcheck.WithLogger(logger.With("component", "http")))
 
-	// Preload configured channels
-	for _, ch := range cfg.IRCConfig.Channels {
-		gameInstances.Lock()
-		err := initChannel(ch)
-		gameInstances.Unlock()
-		if err != nil {
-			return err
-		}
+	audit, err := admin.OpenAuditor(cfg.IRCConfig.AuditLog)
+	if err != nil {
+		return err
 	}
 
-	// Connected → join configured channels
-	conn.HandleFunc(irc.CONNECTED, func(c *irc.Conn, _ *irc.Line) {
-		fmt.Printf("Connected to %s\n", cfg.IRCConfig.Host)
-		for _, ch := range cfg.IRCConfig.Channels {
-			fmt.Printf("Joining channel %s\n", ch)
-			c.Join(ch)
+	// ---- Storage: in-memory (dry-run) or DB opened ONCE and migrated ----
+	var (
+		store    storage
+		database *db.DB
+	)
+	if opts.Memory {
+		logger.Warn("using in-memory storage: nothing will be persisted")
+		store.players = cat_player.NewMemoryPlayerRepository()
+		store.state = channel_state.NewMemoryChannelStateRepository()
+		store.settings = channel_settings.NewMemoryChannelSettingsRepository()
+		store.locks = job_lock.NewMemoryJobLockRepository()
+		store.eventLog = cat_event.NewMemoryCatEventRepository()
+	} else {
+		database = db.NewDatabase(cfg.DBConfig, db.WithLogger(logger.With("component", "db")))
+		if database == nil || database.DB == nil {
+			return fmt.Errorf("db init failed")
 		}
-	})
-
-	// Also join on MOTD end / no MOTD
-	conn.HandleFunc("422", func(c *irc.Conn, _ *irc.Line) {
-		for _, ch := range cfg.IRCConfig.Channels {
-			c.Join(ch)
+		// the embedded SQL migrations, the same ones "migrate up" applies
+		if err := migrations.MigrateDatabaseUp(database); err != nil {
+			return fmt.Errorf("migrate database failed: %w", err)
 		}
-	})
-	conn.HandleFunc("376", func(c *irc.Conn, _ *irc.Line) {
-		for _, ch := range cfg.IRCConfig.Channels {
-			c.Join(ch)
-		}
-	})
-
-	// JOIN events: start the game loop for that channel (once)
-	conn.HandleFunc(irc.JOIN, func(c *irc.Conn, line *irc.Line) {
-		channel := line.Args[0]
-		fmt.Printf("Joined %s\n", channel)
-
-		handleNickserv(cfg.IRCConfig, identified, c)
-
-		gameInstances.Lock()
-		defer gameInstances.Unlock()
-
-		if _, ok := gameInstances.games[channel]; !ok {
-			if err := initChannel(channel); err != nil {
-				fmt.Printf("Error init channel %s: %v\n", channel, err)
-				return
+		defer func() {
+			if err := database.Close(); err != nil {
+				logger.Error("failed to close the database", "error", err)
 			}
+		}()
+		store.players = cat_player.NewPlayerRepository(database)
+		store.state = channel_state.NewChannelStateRepository(database)
+		store.settings = channel_settings.NewChannelSettingsRepository(database)
+		store.locks = job_lock.NewJobLockRepository(database)
+		store.eventLog = cat_event.NewCatEventRepository(database)
+		health.SetDatabase(database.Ping)
+	}
+	host, _ := os.Hostname()
+	store.replica = fmt.Sprintf("%s:%d", host, os.Getpid())
+
+	// ---- HTTP: probes, /status, /metrics, the read-only API and the dashboard ----
+	mux := http.NewServeMux()
+	if !cfg.APIConfig.Disabled {
+		mux.Handle("/api/", api.New(store.players,
+			api.WithNetworks(health.Networks),
+			api.WithEventLog(store.eventLog),
+			api.WithCORSOrigins(strings.Split(cfg.APIConfig.CORSOrigins, ",")...),
+			api.WithMaxPageSize(cfg.APIConfig.MaxPageSize),
+			api.WithLogger(logger.With("component", "api")),
+		).Handler())
+	}
+	if !cfg.DashboardConfig.Disabled {
+		if cfg.APIConfig.Disabled {
+			logger.Warn("the dashboard needs the API, which is disabled")
 		}
-		if !gameInstances.GameStarted[channel] {
-			gameInstances.GameStarted[channel] = true
-			go gameInstances.games[channel].Start(ctx)
-		}
-	})
-
-	// INVITE handler
-	conn.HandleFunc(irc.INVITE, func(c *irc.Conn, line *irc.Line) {
-		channel := line.Args[1]
-		fmt.Printf("Invited to %s\n", channel)
-		c.Join(channel)
-
-		gameInstances.Lock()
-		defer gameInstances.Unlock()
-
-		if err := initChannel(channel); err != nil {
-			fmt.Printf("Error init invited channel %s: %v\n", channel, err)
-			return
-		}
-		go gameInstances.games[channel].Start(ctx)
-		gameInstances.GameStarted[channel] = true
-	})
-
-	// Command dispatcher
-	conn.HandleFunc(irc.PRIVMSG, func(c *irc.Conn, line *irc.Line) {
-		channel := line.Args[0]
-		msg := line.Args[1]
-
-		// manual start (optional)
-		if msg == "!start" {
-			gameInstances.Lock()
-			defer gameInstances.Unlock()
-
-			game, ok := gameInstances.games[channel]
-			if !ok {
-				if err := initChannel(channel); err != nil {
-					fmt.Printf("Error init channel %s: %v\n", channel, err)
-					return
-				}
-				game = gameInstances.games[channel]
+		mux.Handle(dashboard.Prefix, dashboard.Handler())
+	}
+	mux.Handle("/", health.Handler())
+	healthcheck.StartHealthcheck(ctx, cfg.AppConfig, mux, logger.With("component", "http"))
+
+	// ---- Supervisor: one goroutine per network ----
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, n := range networks {
+		wg.Add(1)
+		go func(n config.NetworkConfig) {
+			defer wg.Done()
+			log := logger.With(logging.KeyNetwork, n.IRC.Network)
+			if err := runNetwork(ctx, cfg, n, store, audit, health, log); err != nil {
+				log.Error("network stopped", "error", err)
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", n.IRC.Network, err))
+				mu.Unlock()
 			}
+		}(n)
+	}
+	wg.Wait()
 
-			if gameInstances.GameStarted[channel] {
-				fmt.Printf("Game already started for %s\n", channel)
-				return
-			}
+	if len(errs) == len(networks) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
 
-			fmt.Printf("Starting gameInstance for %s\n", channel)
-			go game.Start(ctx)
-			gameInstances.GameStarted[channel] = true
-			return
+// findNetwork picks the named network out of cfg.
+func findNetwork(cfg config.Config, name string) (config.NetworkConfig, error) {
+	networks, err := cfg.Networks()
+	if err != nil {
+		return config.NetworkConfig{}, err
+	}
+	for _, n := range networks {
+		if strings.EqualFold(n.IRC.Network, name) {
+			return n, nil
 		}
+	}
+	return config.NetworkConfig{}, fmt.Errorf("network %s is no longer configured", name)
+}
 
-		// get cmds for this channel
-		gameInstances.Lock()
-		cmds, ok := gameInstances.commandInstances[channel]
-		if !ok {
-			if err := initChannel(channel); err != nil {
-				gameInstances.Unlock()
-				fmt.Printf("Error lazy init channel %s: %v\n", channel, err)
-				return
-			}
-			cmds = gameInstances.commandInstances[channel]
+// newThrottler builds the command throttle from config.
+func newThrottler(cfg config.ThrottleConfig) (*throttle.Throttler, error) {
+	def := throttle.Rule{}
+	for scope, raw := range map[throttle.Scope]string{
+		throttle.ScopeNick:    cfg.Nick,
+		throttle.ScopeHost:    cfg.Host,
+		throttle.ScopeChannel: cfg.Channel,
+	} {
+		l, err := throttle.ParseLimit(raw)
+		if err != nil {
+			return nil, err
 		}
-		gameInstances.Unlock()
+		def[scope] = l
+	}
 
-		if err := cmds.HandleCommand(ctx, line); err != nil {
-			fmt.Printf("Error handling command: %s\n", err.Error())
-			return
-		}
-	})
+	rules, err := throttle.ParseRules(cfg.Commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return throttle.New(throttle.Config{
+		Default:     def,
+		Commands:    rules,
+		Notify:      !cfg.Silent,
+		IgnoreAfter: cfg.IgnoreAfter,
+		IgnoreFor:   time.Duration(cfg.IgnoreMinutes) * time.Minute,
+	}), nil
+}
 
-	quit := make(chan bool, 1)
-	conn.HandleFunc(irc.DISCONNECTED, func(_ *irc.Conn, _ *irc.Line) { quit <- true })
+// adminConfig picks the admin ACL settings out of the IRC config.
+func adminConfig(cfg config.IRCConfig) admin.Config {
+	return admin.Config{
+		Masks:     cfg.AdminMasks,
+		Accounts:  cfg.AdminAccounts,
+		Bans:      cfg.BanMasks,
+		RequireOp: cfg.AdminRequireOp,
+	}
+}
 
-	if err := conn.Connect(); err != nil {
-		fmt.Printf("Connection error: %s\n", err.Error())
+// reloadAdmins re-reads network's admins and bans from a fresh config. A
+// config that fails to load, or lists no admins at all (nobody could
+// !reload it back), leaves the ACL as it is.
+func reloadAdmins(acl *admin.ACL, network string, load func() (config.Config, error)) error {
+	cfg, err := load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	n, err := findNetwork(cfg, network)
+	if err != nil {
 		return err
 	}
-	<-quit
+	next := adminConfig(n.IRC)
+	if !next.HasAdmins() {
+		return fmt.Errorf("network %s: %w, keeping the current admins", network, admin.ErrNoAdmins)
+	}
+	acl.Reload(next)
 	return nil
 }
 
-func handleNickserv(cfg config.IRCConfig, identified *Identified, c *irc.Conn) {
-	identified.Lock()
-	defer identified.Unlock()
+// channelPrivilege reads the sender's channel modes from the state tracker.
+func channelPrivilege(conn *irc.Conn) commands.PrivilegeChecker {
+	return func(_ context.Context, line *irc.Line) commands.Privilege {
+		st := conn.StateTracker()
+		if st == nil || len(line.Args) == 0 {
+			return commands.PrivilegeNone
+		}
+		privs, ok := st.IsOn(line.Args[0], line.Nick)
+		if !ok || privs == nil {
+			return commands.PrivilegeNone
+		}
+		switch {
+		case privs.Owner, privs.Admin, privs.Op:
+			return commands.PrivilegeOp
+		case privs.HalfOp:
+			return commands.PrivilegeHalfOp
+		case privs.Voice:
+			return commands.PrivilegeVoice
+		}
+		return commands.PrivilegeNone
+	}
+}
 
-	if !identified.identified && cfg.NickservPassword != "" {
-		command := fmt.Sprintf("PRIVMSG NickServ :IDENTIFY %s", cfg.NickservPassword)
-		c.Raw(command)
-		identified.identified = true
+// configureSASL sets up SASL. EXTERNAL relies on the client certificate
+// already loaded into ircConfig.SSLConfig.
+func configureSASL(ircConfig *irc.Config, cfg config.IRCConfig) error {
+	user, password := cfg.SaslUser, cfg.SaslPassword
+	if user == "" {
+		user = cfg.Nick
+	}
+	if password == "" {
+		password = cfg.NickservPassword
+	}
+	client, err := auth.NewSASL(cfg.SaslMechanism, user, password)
+	if err != nil {
+		return err
 	}
+	if client == nil {
+		return nil
+	}
+	if strings.EqualFold(cfg.SaslMechanism, auth.SASLExternal) && len(ircConfig.SSLConfig.Certificates) == 0 {
+		return fmt.Errorf("sasl external needs a client certificate (TLS_CERT_FILE / TLS_KEY_FILE)")
+	}
+	ircConfig.Sasl = client
+	ircConfig.EnableCapabilityNegotiation = true
+	return nil
 }
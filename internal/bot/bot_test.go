@@ -0,0 +1,34 @@
+package bot
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
+)
+
+func TestReloadAdmins(t *testing.T) {
+	acl := admin.NewACL(admin.Config{Masks: []string{"*!*@staff.example.org"}})
+	loaded := func(masks ...string) func() (config.Config, error) {
+		return func() (config.Config, error) {
+			return config.Config{IRCConfig: config.IRCConfig{Network: "libera", AdminMasks: masks}}, nil
+		}
+	}
+	stillAdmin := func() bool { return acl.IsAdmin("alice!a@staff.example.org", "") }
+
+	broken := func() (config.Config, error) { return config.Config{}, errors.New("invalid character '}'") }
+	if err := reloadAdmins(acl, "libera", broken); err == nil || !stillAdmin() {
+		t.Errorf("a config that fails to load must keep the admins, err=%v", err)
+	}
+	if err := reloadAdmins(acl, "libera", loaded()); !errors.Is(err, admin.ErrNoAdmins) || !stillAdmin() {
+		t.Errorf("an empty admin list must be refused, err=%v", err)
+	}
+
+	if err := reloadAdmins(acl, "libera", loaded("*!*@ops.example.org")); err != nil {
+		t.Fatal(err)
+	}
+	if stillAdmin() || !acl.IsAdmin("bob!b@ops.example.org", "") {
+		t.Error("reload should replace the admins")
+	}
+}
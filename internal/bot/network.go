@@ -0,0 +1,404 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/config"
+	"github.com/MyelinBots/catbot-go/internal/healthcheck"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
+	"github.com/MyelinBots/catbot-go/internal/services/auth"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	"github.com/MyelinBots/catbot-go/internal/services/commands"
+	"github.com/MyelinBots/catbot-go/internal/services/outbound"
+	"github.com/MyelinBots/catbot-go/internal/services/random"
+	"github.com/MyelinBots/catbot-go/internal/services/scheduler"
+	"github.com/MyelinBots/catbot-go/internal/services/settings"
+	irc "github.com/fluffle/goirc/client"
+)
+
+// runNetwork runs one IRC network until ctx is cancelled, reconnecting with
+// backoff whenever the connection drops. On shutdown it stops the network's
+// game loops, flushes its outbound queue and sends QUIT.
+func runNetwork(ctx context.Context, cfg config.Config, net config.NetworkConfig, store storage, audit *admin.Auditor, health *healthcheck.Health, log *slog.Logger) error {
+	ircCfg := net.IRC
+
+	// ---- IRC config (with PASS) ----
+	ircConfig := irc.NewConfig(ircCfg.Nick)
+	ircConfig.SSL = ircCfg.SSL
+	tlsConf, err := tlsConfig(ircCfg)
+	if err != nil {
+		return err
+	}
+	ircConfig.SSLConfig = tlsConf
+	ircConfig.Server = fmt.Sprintf("%s:%d", ircCfg.Host, ircCfg.Port)
+	ircConfig.Me.Ident = ircCfg.User
+	ircConfig.Pass = ircCfg.Password
+	ircConfig.QuitMessage = ircCfg.QuitMessage
+	if err := configureSASL(ircConfig, ircCfg); err != nil {
+		return err
+	}
+	if len(ircCfg.AdminAccounts) > 0 {
+		// services account names arrive as a message tag
+		ircConfig.EnableCapabilityNegotiation = true
+		ircConfig.Capabilites = append(ircConfig.Capabilites, "account-tag")
+	}
+
+	conn := irc.Client(ircConfig)
+	conn.EnableStateTracking() // channel modes for privileged commands
+
+	// ---- Outbound queue: flood control, line splitting, priorities ----
+	queue := outbound.New(conn, outbound.Config{
+		Rate:         ircCfg.FloodRate,
+		Burst:        ircCfg.FloodBurst,
+		GlobalRate:   ircCfg.FloodGlobalRate,
+		MaxLineBytes: ircCfg.MaxLineBytes,
+	})
+	// the queue outlives ctx so shutdown can still flush it; it is held while
+	// disconnected because sends would block on a dead connection
+	queueCtx, stopQueue := context.WithCancel(context.Background())
+	defer stopQueue()
+	queue.Pause()
+	go queue.Run(queueCtx)
+	metrics.Outbound.Watch(ircCfg.Network, func() metrics.QueueStats { return queueStats(queue) })
+
+	// ---- Command throttling, shared by every channel of this network ----
+	throttler, err := newThrottler(cfg.ThrottleConfig)
+	if err != nil {
+		return fmt.Errorf("throttle config: %w", err)
+	}
+
+	// ---- Admin ACL, per network (the audit log is shared) ----
+	acl := admin.NewACL(adminConfig(ircCfg))
+	reloadACL := func() error { return reloadAdmins(acl, ircCfg.Network, config.LoadConfig) }
+
+	gameInstances := &GameInstances{
+		games:            make(map[string]*catbot.CatBot),
+		commandInstances: make(map[string]commands.CommandController),
+		GameStarted:      make(map[string]bool),
+		joined:           make(map[string]bool),
+	}
+
+	// Convert game config to durations
+	spawnWindow := time.Duration(net.Game.SpawnWindowMinutes) * time.Minute
+	minRespawn := time.Duration(net.Game.MinRespawnMinutes) * time.Minute
+	maxRespawn := time.Duration(net.Game.MaxRespawnMinutes) * time.Minute
+
+	// per-channel settings (!purrito set) default to the network's game config
+	defaults := settings.Defaults()
+	defaults.SpawnWindow, defaults.MinRespawn, defaults.MaxRespawn = spawnWindow, minRespawn, maxRespawn
+	if net.Game.Timezone != "" {
+		if defaults, err = defaults.With(settings.KeyTimezone, net.Game.Timezone); err != nil {
+			return fmt.Errorf("game timezone: %w", err)
+		}
+	}
+	channelSettings := settings.NewStore(store.settings, defaults)
+	if _, _, err := scheduler.ParseTimeOfDay(net.Game.DecayAt); err != nil {
+		return fmt.Errorf("game decay time: %w", err)
+	}
+
+	// helper: init a channel's game+commands in one place (reuse repo)
+	// presence, catnip cooldowns and slap warnings are restored from store.state
+	initChannel := func(channel string) error {
+		chLog := log.With(logging.KeyChannel, channel)
+		chSettings, err := channelSettings.Load(logging.WithLogger(ctx, chLog), ircCfg.Network, channel)
+		if err != nil {
+			chLog.Error("failed to load settings, using defaults", "error", err)
+		}
+		game := catbot.NewCatBot(queue.At(outbound.PriorityHigh), store.players, ircCfg.Network, channel, spawnWindow, minRespawn, maxRespawn,
+			cat_actions.WithStateRepository(store.state), cat_actions.WithSettings(chSettings),
+			cat_actions.WithRand(random.New(net.Game.Seed)), cat_actions.WithLogger(log),
+			cat_actions.WithEventLog(store.eventLog))
+		// daily decay at a fixed time of the channel's game day, once across replicas
+		if err := game.ScheduleDecay(net.Game.DecayAt, scheduler.WithLocker(store.locks, store.replica)); err != nil {
+			return err
+		}
+
+		// cast once เพื่อเรียก handler methods ได้ตรงๆ
+		cmdController := commands.NewCommandController(game,
+			commands.WithPrefixes(ircCfg.Prefixes...),
+			commands.WithNames(ircCfg.Nick, "purrito"),
+			commands.WithPrivilegeChecker(channelPrivilege(conn)),
+			commands.WithHelp(ircCfg.HelpDelivery, ircCfg.Language, ircCfg.HelpPageSize),
+			commands.WithHelpClient(queue.At(outbound.PriorityLow)),
+			commands.WithThrottle(throttler),
+			commands.WithAdmin(acl, audit),
+		)
+		cmds, ok := cmdController.(*commands.CommandControllerImpl)
+		if !ok {
+			return fmt.Errorf("failed to cast command controller")
+		}
+
+		// basic purrito commands -> game.HandleCatCommand (varargs) ต้อง adapt
+		catCommand := commands.MessageHandler(adaptVarArgs(game.HandleCatCommand))
+		purrito := []string{"purrito"}
+		cmds.Register(commands.Command{Name: "pet", Usage: "!pet purrito", Description: "Pet me, maybe I will purr... or scratch! 🐾", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "love", Usage: "!love purrito", Description: "Show me some love... more love, more purrs 💗", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "feed", Usage: "!feed purrito", Description: "Feed me some tasty treats 🍣 🍗 🍤 🍉", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "slap", Usage: "!slap purrito", Description: "Tease me... but be careful 👋😼", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "kick", Usage: "!kick purrito", Description: "Don't you dare... I will remember it 😾", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "catnip", Usage: "!catnip purrito", Description: "Give me some catnip to boost my mood 🌿😸", DefaultArgs: purrito, Handler: catCommand})
+		cmds.Register(commands.Command{Name: "laser", Usage: "!laser purrito", Description: "Find out when I was last seen chasing lasers 🔦⚡️", DefaultArgs: purrito, Handler: commands.MessageHandler(cmds.PurritoLaserHandler())})
+		cmds.Register(commands.Command{Name: "status", Usage: "!status purrito", Description: "Check your love, mood, bond & gifts ❤️😽", DefaultArgs: purrito, Handler: catCommand})
+
+		// extra commands
+		cmds.Register(commands.Command{Name: "toplove", Aliases: []string{"top"}, Usage: "!toplove", Description: "See who I love the most 💖", Handler: commands.MessageHandler(adaptVarArgs(cmds.TopLove10Handler()))})
+		cmds.RegisterHistory(store.eventLog)
+		cmds.Register(commands.Command{Name: "invite", Usage: "!invite purrito #channel", Description: "Invite me to your own channel 📨", Params: commands.InviteParams, Handler: commands.InviteHandler(conn, queue.At(outbound.PriorityNormal))})
+		cmds.Register(commands.Command{Name: "purrito", Aliases: []string{"help"}, Usage: "!purrito [page] | !help <command>", Description: "This help, add a page number or a command name 📖", Handler: cmds.HelpHandler()})
+
+		// admin commands (ACL, hidden from help, audit-logged)
+		cmds.RegisterAdmin(commands.AdminDeps{Conn: conn, Out: queue.At(outbound.PriorityNormal), ACL: acl, Reload: reloadACL})
+
+		// channel settings (ops)
+		cmds.RegisterSettings(channelSettings)
+
+		gameInstances.games[channel] = game
+		gameInstances.commandInstances[channel] = cmds
+		gameInstances.GameStarted[channel] = false
+		return nil
+	}
+
+	// /readyz and /status: registration, joined channels and presence
+	state := newConnState()
+	health.AddNetwork(ircCfg.Network, func() healthcheck.NetworkStatus {
+		return networkStatus(ircCfg.Network, ircCfg.Channels, state, gameInstances)
+	})
+	defer health.RemoveNetwork(ircCfg.Network)
+
+	// Preload configured channels
+	for _, ch := range ircCfg.Channels {
+		gameInstances.Lock()
+		err := initChannel(ch)
+		gameInstances.Unlock()
+		if err != nil {
+			return err
+		}
+	}
+
+	// (Re)join configured channels and the ones we were in before a reconnect.
+	// The games are kept across reconnects, only their IRC side comes back.
+	joinAll := func(c *irc.Conn) {
+		for _, ch := range gameInstances.channels(ircCfg.Channels) {
+			log.Info("joining channel", logging.KeyChannel, ch)
+			c.Join(ch)
+		}
+	}
+
+	// Channels are joined once identified: right away after a SASL login,
+	// otherwise when NickServ confirms IDENTIFY (or gives up after retries)
+	identifier := auth.NewIdentifier(auth.Config{
+		Nick:     ircCfg.Nick,
+		Password: ircCfg.NickservPassword,
+		Command:  ircCfg.NickservCommand,
+		Regain:   ircCfg.NickservRegain,
+		Timeout:  time.Duration(ircCfg.NickservTimeoutSeconds) * time.Second,
+		Retries:  ircCfg.NickservRetries,
+		Logger:   log.With("component", "auth"),
+	}, conn, func() { joinAll(conn) })
+
+	// Connected → release the queue and identify
+	conn.HandleFunc(irc.CONNECTED, func(_ *irc.Conn, _ *irc.Line) {
+		log.Info("connected", "host", ircCfg.Host)
+		state.set(true)
+		queue.Resume()
+		identifier.Connected()
+	})
+
+	// RPL_LOGGEDIN / RPL_SASLSUCCESS and services replies
+	conn.HandleFunc("900", func(_ *irc.Conn, _ *irc.Line) { identifier.LoggedIn() })
+	conn.HandleFunc("903", func(_ *irc.Conn, _ *irc.Line) { identifier.LoggedIn() })
+	conn.HandleFunc("904", func(_ *irc.Conn, _ *irc.Line) {
+		log.Warn("SASL authentication failed, falling back to NickServ")
+	})
+	conn.HandleFunc(irc.NOTICE, func(_ *irc.Conn, line *irc.Line) {
+		identifier.Notice(line.Nick, line.Text())
+	})
+
+	// JOIN events: start the game loop for that channel (once)
+	conn.HandleFunc(irc.JOIN, func(c *irc.Conn, line *irc.Line) {
+		if line.Nick != c.Me().Nick {
+			return
+		}
+		channel := line.Args[0]
+		log.Info("joined", logging.KeyChannel, channel)
+
+		gameInstances.Lock()
+		defer gameInstances.Unlock()
+
+		gameInstances.joined[channel] = true
+		if _, ok := gameInstances.games[channel]; !ok {
+			if err := initChannel(channel); err != nil {
+				log.Error("failed to init channel", logging.KeyChannel, channel, "error", err)
+				return
+			}
+		}
+		gameInstances.start(ctx, channel)
+	})
+
+	// PART / KICK: don't re-join channels we were asked to leave
+	leave := func(channel string) {
+		gameInstances.Lock()
+		delete(gameInstances.joined, channel)
+		gameInstances.Unlock()
+	}
+	conn.HandleFunc(irc.PART, func(c *irc.Conn, line *irc.Line) {
+		if line.Nick == c.Me().Nick && len(line.Args) > 0 {
+			leave(line.Args[0])
+		}
+	})
+	conn.HandleFunc(irc.KICK, func(c *irc.Conn, line *irc.Line) {
+		if len(line.Args) > 1 && line.Args[1] == c.Me().Nick {
+			leave(line.Args[0])
+		}
+	})
+
+	// INVITE handler: the JOIN handler sets the game up
+	conn.HandleFunc(irc.INVITE, func(c *irc.Conn, line *irc.Line) {
+		channel := line.Args[1]
+		log.Info("invited", logging.KeyChannel, channel, logging.KeyNick, line.Nick)
+		c.Join(channel)
+	})
+
+	// Command dispatcher
+	conn.HandleFunc(irc.PRIVMSG, func(c *irc.Conn, line *irc.Line) {
+		channel := line.Args[0]
+		msg := line.Args[1]
+
+		// manual start (optional)
+		if msg == "!start" {
+			gameInstances.Lock()
+			defer gameInstances.Unlock()
+
+			if _, ok := gameInstances.games[channel]; !ok {
+				if err := initChannel(channel); err != nil {
+					log.Error("failed to init channel", logging.KeyChannel, channel, "error", err)
+					return
+				}
+			}
+
+			if gameInstances.GameStarted[channel] {
+				log.Debug("game already started", logging.KeyChannel, channel)
+				return
+			}
+
+			log.Info("starting game", logging.KeyChannel, channel)
+			gameInstances.start(ctx, channel)
+			return
+		}
+
+		// get cmds for this channel
+		gameInstances.Lock()
+		cmds, ok := gameInstances.commandInstances[channel]
+		if !ok {
+			if err := initChannel(channel); err != nil {
+				gameInstances.Unlock()
+				log.Error("failed to init channel", logging.KeyChannel, channel, "error", err)
+				return
+			}
+			cmds = gameInstances.commandInstances[channel]
+		}
+		gameInstances.Unlock()
+
+		// failures are answered in the channel and logged by the controller
+		_ = cmds.HandleCommand(ctx, line)
+	})
+
+	disconnected := make(chan struct{}, 1)
+	conn.HandleFunc(irc.DISCONNECTED, func(_ *irc.Conn, _ *irc.Line) {
+		log.Warn("disconnected", "host", ircCfg.Host)
+		state.set(false)
+		queue.Pause()
+		identifier.Reset()
+		select {
+		case disconnected <- struct{}{}:
+		default:
+		}
+	})
+
+	// ---- Connect, and reconnect with backoff until ctx is cancelled ----
+	retry := newBackoff(
+		time.Duration(ircCfg.ReconnectMinSeconds)*time.Second,
+		time.Duration(ircCfg.ReconnectMaxSeconds)*time.Second,
+	)
+	for ctx.Err() == nil {
+		// not ConnectContext: goirc tears the connection down with its ctx,
+		// and on shutdown we still want to flush the queue and QUIT
+		if err := conn.Connect(); err != nil {
+			log.Error("connection failed", "host", ircCfg.Host, "error", explainTLSError(err, ircCfg))
+		} else {
+			connectedAt := time.Now()
+			select {
+			case <-disconnected:
+				if time.Since(connectedAt) > time.Minute {
+					retry.Reset()
+				}
+			case <-ctx.Done():
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wait := retry.Next()
+		log.Info("reconnecting", "in", wait.Round(time.Second))
+		metrics.IRCReconnects.WithLabelValues(ircCfg.Network).Inc()
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+		}
+	}
+
+	shutdown(log, conn, queue, gameInstances, disconnected, time.Duration(cfg.AppConfig.ShutdownTimeoutSeconds)*time.Second)
+	return nil
+}
+
+// shutdown waits for the game loops (already stopping on the cancelled ctx),
+// flushes the outbound queue, sends QUIT and waits for the server to close the
+// link. Each step gives up after timeout.
+func shutdown(log *slog.Logger, conn *irc.Conn, queue *outbound.Queue, games *GameInstances, disconnected <-chan struct{}, timeout time.Duration) {
+	log.Info("shutting down")
+
+	done := make(chan struct{})
+	go func() {
+		games.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		log.Warn("timed out waiting for game loops")
+	}
+
+	if !conn.Connected() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := queue.Drain(ctx); err != nil {
+		log.Warn("outbound queue not drained", "error", err)
+	}
+
+	conn.Quit()
+	select {
+	case <-disconnected:
+	case <-ctx.Done():
+		conn.Close()
+	}
+}
+
+// queueStats converts the queue's counters for the metrics collector.
+func queueStats(q *outbound.Queue) metrics.QueueStats {
+	s := q.Stats()
+	out := metrics.QueueStats{Depth: make(map[string]int), Sent: s.Sent, Dropped: s.Dropped}
+	for p, n := range s.Depth {
+		out.Depth[outbound.Priority(p).String()] = n
+	}
+	return out
+}
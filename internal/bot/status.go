@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/healthcheck"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+)
+
+// connState tracks whether a network's connection is registered, for the
+// health probes.
+type connState struct {
+	mu         sync.Mutex
+	registered bool
+	since      time.Time // last change
+}
+
+func newConnState() *connState { return &connState{since: time.Now()} }
+
+func (s *connState) set(registered bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.registered != registered {
+		s.registered, s.since = registered, time.Now()
+	}
+}
+
+func (s *connState) get() (registered bool, since time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.registered, s.since
+}
+
+// networkStatus reports a network for /readyz and /status: the configured
+// channels first, then the ones joined later, each with Purrito's presence.
+func networkStatus(name string, configured []string, conn *connState, games *GameInstances) healthcheck.NetworkStatus {
+	registered, since := conn.get()
+	out := healthcheck.NetworkStatus{Name: name, Registered: registered, Since: since}
+
+	games.Lock()
+	defer games.Unlock()
+
+	// channel names are case-insensitive, the maps keep them as the server sent them
+	joined := make(map[string]bool)
+	for ch := range games.joined {
+		joined[strings.ToLower(ch)] = true
+	}
+	byKey := make(map[string]string) // lowercased => key of games.games
+	for ch := range games.games {
+		byKey[strings.ToLower(ch)] = ch
+	}
+	seen := make(map[string]bool)
+	add := func(channel string, configured bool) {
+		key := strings.ToLower(channel)
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		ch := healthcheck.ChannelStatus{
+			Name:       channel,
+			Configured: configured,
+			Joined:     joined[key],
+			Playing:    games.GameStarted[byKey[key]],
+		}
+		if game, ok := games.games[byKey[key]]; ok {
+			if ca, ok := game.CatActions.(*cat_actions.CatActions); ok {
+				p := ca.Presence()
+				ch.Present = p.Here
+				ch.PresentUntil = timePtr(p.PresentUntil)
+				ch.NextSpawnAt = timePtr(p.NextSpawnAt)
+			}
+		}
+		out.Channels = append(out.Channels, ch)
+	}
+
+	for _, ch := range configured {
+		add(ch, true)
+	}
+	var others []string
+	for ch := range games.games {
+		others = append(others, ch)
+	}
+	for ch := range games.joined {
+		others = append(others, ch)
+	}
+	sort.Strings(others)
+	for _, ch := range others {
+		add(ch, false)
+	}
+	return out
+}
+
+// timePtr leaves zero times out of the JSON.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	"github.com/MyelinBots/catbot-go/internal/services/commands"
+)
+
+type nopClient struct{}
+
+func (nopClient) Privmsg(string, string) {}
+func (nopClient) Notice(string, string)  {}
+
+func TestNetworkStatus(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	games := &GameInstances{
+		games: map[string]*catbot.CatBot{
+			"#Cats":    catbot.NewCatBot(nopClient{}, repo, "libera", "#Cats", time.Hour, time.Hour, time.Hour),
+			"#invited": catbot.NewCatBot(nopClient{}, repo, "libera", "#invited", time.Hour, time.Hour, time.Hour),
+		},
+		commandInstances: make(map[string]commands.CommandController),
+		GameStarted:      map[string]bool{"#Cats": true},
+		joined:           map[string]bool{"#Cats": true, "#invited": true},
+	}
+	state := newConnState()
+	state.set(true)
+
+	st := networkStatus("libera", []string{"#cats", "#dogs"}, state, games)
+	if !st.Registered || len(st.Channels) != 3 {
+		t.Fatalf("unexpected status %+v", st)
+	}
+	cats, dogs, invited := st.Channels[0], st.Channels[1], st.Channels[2]
+	// configured as #cats, joined as #Cats: the same channel
+	if cats.Name != "#cats" || !cats.Configured || !cats.Joined || !cats.Playing || !cats.Present || cats.PresentUntil == nil {
+		t.Errorf("#cats: %+v", cats)
+	}
+	if dogs.Name != "#dogs" || !dogs.Configured || dogs.Joined {
+		t.Errorf("#dogs: %+v", dogs)
+	}
+	if invited.Name != "#invited" || invited.Configured || !invited.Joined || invited.Playing || !invited.Present {
+		t.Errorf("#invited: %+v", invited)
+	}
+}
@@ -0,0 +1,120 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MyelinBots/catbot-go/config"
+)
+
+// PinError means the server certificate matched none of the pinned fingerprints.
+type PinError struct {
+	Fingerprint string // SHA-256 of the certificate the server sent
+}
+
+func (e *PinError) Error() string {
+	return fmt.Sprintf("certificate fingerprint %s is not pinned", e.Fingerprint)
+}
+
+// tlsConfig builds the TLS settings from config. The server certificate is
+// verified against the system roots (or TLSCAFile) unless TLSInsecure is set;
+// with TLSPins the leaf certificate must also match one of the fingerprints.
+func tlsConfig(cfg config.IRCConfig) (*tls.Config, error) {
+	tc := &tls.Config{
+		ServerName: cfg.TLSServerName,
+		MinVersion: tls.VersionTLS12,
+	}
+	if tc.ServerName == "" {
+		tc.ServerName = cfg.Host
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA bundle %s: no certificates found", cfg.TLSCAFile)
+		}
+		tc.RootCAs = pool
+	}
+
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tc.Certificates = []tls.Certificate{cert}
+	}
+
+	pins := make(map[string]bool)
+	for _, p := range cfg.TLSPins {
+		fp := normalizeFingerprint(p)
+		if len(fp) != sha256.Size*2 {
+			return nil, fmt.Errorf("tls pin %q: want a SHA-256 fingerprint (64 hex digits)", p)
+		}
+		pins[fp] = true
+	}
+
+	if len(pins) == 0 {
+		tc.InsecureSkipVerify = cfg.TLSInsecure
+		return tc, nil
+	}
+
+	// pinning needs its own check, so the chain is verified here too
+	tc.InsecureSkipVerify = true
+	tc.VerifyConnection = func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return errors.New("server sent no certificate")
+		}
+		leaf := cs.PeerCertificates[0]
+		if !cfg.TLSInsecure {
+			opts := x509.VerifyOptions{Roots: tc.RootCAs, DNSName: tc.ServerName, Intermediates: x509.NewCertPool()}
+			for _, c := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(c)
+			}
+			if _, err := leaf.Verify(opts); err != nil {
+				return err
+			}
+		}
+		sum := sha256.Sum256(leaf.Raw)
+		if fp := hex.EncodeToString(sum[:]); !pins[fp] {
+			return &PinError{Fingerprint: fp}
+		}
+		return nil
+	}
+	return tc, nil
+}
+
+// normalizeFingerprint accepts "AB:CD:..." or "abcd..." style fingerprints.
+func normalizeFingerprint(s string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(strings.TrimSpace(s)))
+}
+
+// explainTLSError turns certificate failures into something actionable.
+func explainTLSError(err error, cfg config.IRCConfig) error {
+	var (
+		unknown  x509.UnknownAuthorityError
+		hostname x509.HostnameError
+		invalid  x509.CertificateInvalidError
+		pin      *PinError
+	)
+	switch {
+	case errors.As(err, &unknown):
+		return fmt.Errorf("TLS: %s's certificate is signed by an unknown authority, set TLS_CA_FILE or pin it with TLS_PINS: %w", cfg.Host, err)
+	case errors.As(err, &hostname):
+		return fmt.Errorf("TLS: certificate is not valid for %q, check TLS_SERVER_NAME: %w", hostname.Host, err)
+	case errors.As(err, &invalid):
+		return fmt.Errorf("TLS: %s's certificate is invalid (expired or not yet valid?): %w", cfg.Host, err)
+	case errors.As(err, &pin):
+		return fmt.Errorf("TLS: %s's certificate (sha256 %s) does not match TLS_PINS", cfg.Host, pin.Fingerprint)
+	}
+	return err
+}
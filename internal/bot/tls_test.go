@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+)
+
+// dial handshakes with srv using the TLS settings built from cfg.
+func dial(t *testing.T, srv *httptest.Server, cfg config.IRCConfig) error {
+	t.Helper()
+	tc, err := tlsConfig(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := tls.Dial("tcp", srv.Listener.Addr().String(), tc)
+	if err == nil {
+		conn.Close()
+	}
+	return err
+}
+
+func TestTLSConfig_Verification(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	cert := srv.Certificate()
+
+	cfg := config.IRCConfig{Host: "irc.example.net", TLSServerName: "example.com"}
+	err := dial(t, srv, cfg)
+	if err == nil {
+		t.Fatal("self-signed certificate should be rejected by default")
+	}
+	if msg := explainTLSError(err, cfg).Error(); !strings.Contains(msg, "TLS_CA_FILE") {
+		t.Errorf("error should point at the fix, got %q", msg)
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600)
+	cfg.TLSCAFile = ca
+	if err := dial(t, srv, cfg); err != nil {
+		t.Errorf("custom CA should be trusted: %v", err)
+	}
+
+	cfg.TLSServerName = "irc.example.net"
+	err = dial(t, srv, cfg)
+	if err == nil || !strings.Contains(explainTLSError(err, cfg).Error(), "TLS_SERVER_NAME") {
+		t.Errorf("wrong server name should fail clearly, got %v", err)
+	}
+
+	cfg.TLSInsecure = true
+	if err := dial(t, srv, cfg); err != nil {
+		t.Errorf("insecure should skip verification: %v", err)
+	}
+}
+
+func TestTLSConfig_Pins(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	sum := sha256.Sum256(srv.Certificate().Raw)
+	fp := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	cfg := config.IRCConfig{Host: "example.com", TLSInsecure: true, TLSPins: []string{fp}}
+	if err := dial(t, srv, cfg); err != nil {
+		t.Errorf("pinned certificate should be accepted: %v", err)
+	}
+
+	cfg.TLSPins = []string{strings.Repeat("ab", sha256.Size)}
+	err := dial(t, srv, cfg)
+	var pin *PinError
+	if !errors.As(err, &pin) || pin.Fingerprint != strings.ToLower(fp) {
+		t.Errorf("expected a pin mismatch, got %v", err)
+	}
+
+	// pins don't replace chain verification unless TLSInsecure
+	cfg = config.IRCConfig{Host: "example.com", TLSPins: []string{fp}}
+	if err := dial(t, srv, cfg); err == nil {
+		t.Error("untrusted chain should fail even when pinned")
+	}
+
+	cfg.TLSPins = []string{"not-a-fingerprint"}
+	if _, err := tlsConfig(cfg); err == nil {
+		t.Error("malformed pin should be rejected")
+	}
+}
@@ -4,6 +4,11 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package commands
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/MyelinBots/catbot-go/internal/bot"
 	"github.com/spf13/cobra"
 )
@@ -19,7 +24,14 @@ Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return bot.StartBot()
+		memory, err := cmd.Flags().GetBool("memory")
+		if err != nil {
+			return err
+		}
+		// SIGINT/SIGTERM → QUIT, flush, stop the games and close the DB
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return bot.StartBot(ctx, bot.Options{Memory: memory})
 	},
 }
 
@@ -35,4 +47,5 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// serveCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+	serveCmd.Flags().Bool("memory", false, "Keep all game state in memory (no database, dry-run)")
 }
@@ -0,0 +1,41 @@
+package dashboard
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+/*
+DASHBOARD
+A static page for community members who aren't on IRC: leaderboards, player
+profiles, Purrito's presence and the recent interactions. It is embedded in
+the binary and only talks to the read-only API (/api/v1), so it needs no
+configuration of its own.
+*/
+
+// Prefix is where the dashboard is mounted.
+const Prefix = "/dashboard/"
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the dashboard under Prefix.
+func Handler() http.Handler {
+	files, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embedded tree is fixed at build time
+	}
+	fileServer := http.StripPrefix(strings.TrimSuffix(Prefix, "/"), http.FileServer(http.FS(files)))
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		// the files change only with the binary; revalidate so an upgrade shows up
+		w.Header().Set("Cache-Control", "no-cache")
+		fileServer.ServeHTTP(w, r)
+	})
+}
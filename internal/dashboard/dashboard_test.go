@@ -0,0 +1,45 @@
+package dashboard
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle(Prefix, Handler())
+
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		contentType  string
+		contains     string
+	}{
+		{http.MethodGet, "/dashboard/", http.StatusOK, "text/html", `<script src="app.js">`},
+		{http.MethodGet, "/dashboard/app.js", http.StatusOK, "javascript", `const API = "../api/v1"`},
+		{http.MethodGet, "/dashboard/style.css", http.StatusOK, "text/css", "--accent"},
+		{http.MethodGet, "/dashboard/missing.js", http.StatusNotFound, "", ""},
+		{http.MethodPost, "/dashboard/", http.StatusMethodNotAllowed, "", ""},
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+		if rec.Code != tc.status {
+			t.Errorf("%s %s: status %d, want %d", tc.method, tc.path, rec.Code, tc.status)
+			continue
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, tc.contentType) {
+			t.Errorf("%s: content type %q, want %q", tc.path, ct, tc.contentType)
+		}
+		if !strings.Contains(rec.Body.String(), tc.contains) {
+			t.Errorf("%s: body doesn't contain %q", tc.path, tc.contains)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/dashboard", nil))
+	if loc := rec.Header().Get("Location"); rec.Code/100 != 3 || loc != "/dashboard/" {
+		t.Errorf("/dashboard: status %d, location %q, want a redirect to /dashboard/", rec.Code, loc)
+	}
+}
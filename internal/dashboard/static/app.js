@@ -0,0 +1,207 @@
+// Purrito dashboard: a tiny hash-routed page on top of the read-only API.
+//   #/                        channels and Purrito's presence
+//   #/{network}/{channel}     leaderboard and recent interactions
+//   #/{network}/{channel}/{nick}  player profile
+// Everything from IRC (nicks, channels) is inserted as text, never as HTML.
+"use strict";
+
+const API = "../api/v1";
+const REFRESH_MS = 15000;
+const PER_PAGE = 25;
+
+const ACTIONS = {
+  pet: "🤚 pet", love: "💕 loved", feed: "🍣 fed", laser: "🔴 laser", catnip: "🌿 catnip",
+  slap: "👋 slapped", kick: "🦶 kicked", decay: "🍂 missed a day", bond: "💞 bonded",
+  setlove: "🛠️ love set", setbp: "🛠️ BondPoints set",
+};
+const RESULTS = { accepted: "😻", rejected: "😾", warned: "⚠️", punished: "😿", decayed: "💔", awarded: "✨", set: "🔧" };
+
+let timer = null;
+
+/* HELPERS */
+
+function el(tag, attrs, ...children) {
+  const node = document.createElement(tag);
+  for (const [k, v] of Object.entries(attrs || {})) {
+    if (v !== undefined && v !== null) node.setAttribute(k, v);
+  }
+  for (const child of children.flat()) {
+    if (child === null || child === undefined) continue;
+    node.append(child instanceof Node ? child : String(child));
+  }
+  return node;
+}
+
+function link(hash, ...children) {
+  return el("a", { href: hash }, ...children);
+}
+
+// channels travel without their "#"; the API adds it back
+function bare(channel) {
+  return channel.replace(/^#/, "");
+}
+
+function path(...parts) {
+  return parts.map((p) => encodeURIComponent(p)).join("/");
+}
+
+async function get(url) {
+  const res = await fetch(url, { headers: { Accept: "application/json" } });
+  const body = await res.json().catch(() => ({}));
+  if (!res.ok) {
+    const err = new Error(body.error || res.statusText);
+    err.status = res.status;
+    throw err;
+  }
+  return body;
+}
+
+// BondPoints an event awarded; !setbp sets the total instead
+function bondPoints(e) {
+  if (e.action === "setbp") return ", " + e.bond_points + " BP";
+  return e.bond_points > 0 ? ", +" + e.bond_points + " BP" : "";
+}
+
+function clock(iso) {
+  return new Date(iso).toLocaleTimeString([], { hour: "2-digit", minute: "2-digit" });
+}
+
+function ago(iso) {
+  const s = Math.max(0, Math.round((Date.now() - new Date(iso)) / 1000));
+  if (s < 60) return "just now";
+  if (s < 3600) return Math.floor(s / 60) + "m ago";
+  if (s < 86400) return Math.floor(s / 3600) + "h ago";
+  return Math.floor(s / 86400) + "d ago";
+}
+
+function presence(ch) {
+  if (ch.present) {
+    return ch.present_until ? "😺 Purrito is here until " + clock(ch.present_until) : "😺 Purrito is here";
+  }
+  return ch.next_spawn_at ? "💤 Back around " + clock(ch.next_spawn_at) : "💤 Purrito is away";
+}
+
+function render(crumbs, ...content) {
+  document.getElementById("crumbs").replaceChildren(...crumbs);
+  document.getElementById("app").replaceChildren(...content);
+}
+
+function failed(err) {
+  return el("p", { class: "error" }, err.status === 404 ? "Not found 😿" : "Could not load: " + err.message);
+}
+
+/* PAGES */
+
+async function channelsPage() {
+  const data = await get(API + "/channels");
+  const sections = data.networks.map((n) =>
+    el("section", {},
+      el("h2", {}, n.name, " ", el("span", { class: "muted" }, n.connected ? "" : "(reconnecting)")),
+      n.channels.length === 0
+        ? el("p", { class: "muted" }, "No channels joined.")
+        : el("div", { class: "grid" }, n.channels.map((ch) =>
+          el("div", { class: "card" },
+            el("h3", {}, link("#/" + path(n.name, bare(ch.name)), ch.name)),
+            el("p", {}, presence(ch))))),
+    ));
+  render([], sections.length ? sections : el("p", { class: "muted" }, "Purrito isn't on any network yet."));
+}
+
+async function channelPage(network, channel, page) {
+  const base = API + "/" + path(network, channel);
+  const [board, recent, channels] = await Promise.all([
+    get(base + "/leaderboard?page=" + page + "&per_page=" + PER_PAGE),
+    get(base + "/events?limit=20"),
+    get(API + "/channels"),
+  ]);
+  const here = channels.networks
+    .filter((n) => n.name === board.network)
+    .flatMap((n) => n.channels)
+    .find((ch) => ch.name === board.channel);
+
+  const rows = board.players.map((p) =>
+    el("tr", {},
+      el("td", { class: "num" }, p.rank),
+      el("td", {}, link("#/" + path(network, channel, p.name), p.name)),
+      el("td", { class: "bar" }, p.love_bar),
+      el("td", { class: "num" }, p.love_meter + "%"),
+      el("td", {}, p.mood)));
+
+  const pager = el("div", { class: "pager" },
+    page > 1 ? link("#/" + path(network, channel) + "?page=" + (page - 1), "← Previous") : el("span"),
+    el("span", { class: "muted" }, "Page " + board.page + " of " + Math.max(board.pages, 1) + " · " + board.total + " players"),
+    page < board.pages ? link("#/" + path(network, channel) + "?page=" + (page + 1), "Next →") : el("span"));
+
+  const events = recent.events.map((e) =>
+    el("li", {},
+      el("time", { datetime: e.at, title: new Date(e.at).toLocaleString() }, ago(e.at)),
+      link("#/" + path(network, channel, e.player), e.player), " ",
+      ACTIONS[e.action] || e.action, " ",
+      RESULTS[e.result] || e.result,
+      el("span", { class: "muted" }, " → " + e.love_meter + "%" + bondPoints(e))));
+
+  render(
+    [link("#/" + path(network), board.network), el("span", {}, board.channel)],
+    el("p", {}, here ? presence(here) : "💤 Purrito isn't in this channel right now"),
+    el("div", { class: "split" },
+      el("section", {},
+        el("h2", {}, "Leaderboard"),
+        rows.length
+          ? el("table", {},
+            el("thead", {}, el("tr", {},
+              el("th", { class: "num" }, "#"), el("th", {}, "Player"), el("th", {}, "Love"),
+              el("th", { class: "num" }, ""), el("th", {}, "Mood"))),
+            el("tbody", {}, rows))
+          : el("p", { class: "muted" }, "Nobody has met Purrito here yet."),
+        pager),
+      el("section", {},
+        el("h2", {}, "Recent"),
+        events.length ? el("ul", { class: "events" }, events) : el("p", { class: "muted" }, "Quiet so far."))));
+}
+
+async function playerPage(network, channel, nick) {
+  const p = await get(API + "/" + path(network, channel, "players", nick));
+  const stat = (label, value) => [el("dt", {}, label), el("dd", {}, value)];
+
+  render(
+    [link("#/", network), link("#/" + path(network, channel), "#" + channel), el("span", {}, p.name)],
+    el("div", { class: "card" },
+      el("h2", {}, p.name, p.bonded ? " 💞" : ""),
+      el("p", { class: "bar" }, p.love_bar, " ", p.love_meter + "%"),
+      el("dl", { class: "stats" },
+        stat("Mood", p.mood),
+        stat("Title", p.title),
+        stat("Daily streak", p.streak.current + " day(s), best " + p.streak.highest),
+        stat("BondPoints", p.bond_points.total + " (streak " + p.bond_points.streak + ", best " + p.bond_points.highest_streak + ")"),
+        stat("First seen", new Date(p.first_seen_at).toLocaleDateString()),
+        stat("Last interaction", p.last_interacted_at ? ago(p.last_interacted_at) : "never")),
+      el("h3", {}, "🎁 Gifts"),
+      p.gifts.length
+        ? el("ul", { class: "gifts" }, p.gifts.map((g) => el("li", {}, g.name)))
+        : el("p", { class: "muted" }, "No gifts yet, keep the streak going!")));
+}
+
+/* ROUTER */
+
+async function route() {
+  clearTimeout(timer);
+  const [hashPath, query] = location.hash.replace(/^#\/?/, "").split("?");
+  const parts = hashPath.split("/").filter(Boolean).map(decodeURIComponent);
+  const page = Math.max(1, parseInt(new URLSearchParams(query).get("page"), 10) || 1);
+
+  try {
+    if (parts.length === 0 || parts.length === 1) {
+      await channelsPage();
+    } else if (parts.length === 2) {
+      await channelPage(parts[0], parts[1], page);
+    } else {
+      await playerPage(parts[0], parts[1], parts.slice(2).join("/"));
+    }
+  } catch (err) {
+    render([], failed(err));
+  }
+  timer = setTimeout(route, REFRESH_MS);
+}
+
+window.addEventListener("hashchange", route);
+route();
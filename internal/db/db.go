@@ -1,24 +1,112 @@
 package db
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
+	"strings"
+
 	"github.com/MyelinBots/catbot-go/config"
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+
+	// pure Go SQLite driver (registers "sqlite"), shared with golang-migrate
+	_ "modernc.org/sqlite"
+)
+
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 type DB struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Driver string
+}
+
+// Option configures NewDatabase.
+type Option func(*gorm.Config)
+
+// WithLogger sends GORM's query log to l (default: GORM's own logger).
+func WithLogger(l *slog.Logger) Option {
+	return func(c *gorm.Config) { c.Logger = slogLogger{log: l} }
 }
 
-func NewDatabase(cfg config.DBConfig) *DB {
+func NewDatabase(cfg config.DBConfig, opts ...Option) *DB {
+	driver := NormalizeDriver(cfg.Driver)
 
-	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DataBase, cfg.SSLMode)
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverSQLite:
+		dialector = sqlite.Dialector{DriverName: "sqlite", DSN: sqliteDSN(cfg.Path)}
+	case DriverPostgres:
+		dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DataBase, cfg.SSLMode)
+		dialector = postgres.Open(dsn)
+	default:
+		panic(fmt.Sprintf("unsupported database driver %q", cfg.Driver))
+	}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	gormConfig := &gorm.Config{}
+	for _, opt := range opts {
+		opt(gormConfig)
+	}
+	db, err := gorm.Open(dialector, gormConfig)
 	if err != nil {
 		panic("failed to connect database")
 	}
+	if err := db.Use(metricsPlugin{}); err != nil {
+		panic(fmt.Sprintf("failed to register db metrics: %v", err))
+	}
+
+	if driver == DriverSQLite {
+		// SQLite allows a single writer; one connection also keeps ":memory:" databases alive
+		sqldb, err := db.DB()
+		if err != nil {
+			panic("failed to connect database")
+		}
+		sqldb.SetMaxOpenConns(1)
+	}
+
+	return &DB{DB: db, Driver: driver}
+}
 
-	return &DB{DB: db}
+// NormalizeDriver maps config values to a supported driver name ("" => postgres).
+func NormalizeDriver(driver string) string {
+	switch strings.ToLower(strings.TrimSpace(driver)) {
+	case "", "postgres", "postgresql", "pg":
+		return DriverPostgres
+	case "sqlite", "sqlite3":
+		return DriverSQLite
+	default:
+		return driver
+	}
+}
+
+func sqliteDSN(path string) string {
+	if path == "" {
+		path = "catbot.db"
+	}
+	if path == ":memory:" {
+		return "file::memory:?_pragma=foreign_keys(1)"
+	}
+	return fmt.Sprintf("file:%s?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)", path)
+}
+
+// Ping checks that the database answers.
+func (d *DB) Ping(ctx context.Context) error {
+	sqldb, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqldb.PingContext(ctx)
+}
+
+// Close closes the underlying connection pool.
+func (d *DB) Close() error {
+	sqldb, err := d.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqldb.Close()
 }
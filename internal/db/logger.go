@@ -0,0 +1,65 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// slowQuery is logged at warn level.
+const slowQuery = 200 * time.Millisecond
+
+// slogLogger sends GORM's logs to slog: failed queries at error, slow ones
+// at warn and the rest at debug, each with its latency and row count. The
+// SQL is logged with placeholders only, never with the bound values. Queries
+// run for a command carry its fields (see logging.WithLogger).
+type slogLogger struct {
+	log *slog.Logger
+}
+
+func (l slogLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface { return l }
+
+func (l slogLogger) Info(ctx context.Context, msg string, args ...any) {
+	l.log.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Warn(ctx context.Context, msg string, args ...any) {
+	l.log.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Error(ctx context.Context, msg string, args ...any) {
+	l.log.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l slogLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	log := logging.FromContextOr(ctx, l.log)
+	latency := time.Since(begin)
+	level := slog.LevelDebug
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelError
+	case latency > slowQuery:
+		level = slog.LevelWarn
+	}
+	if !log.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	attrs := []slog.Attr{slog.String("sql", sql), slog.Int64("rows", rows), slog.Duration(logging.KeyLatency, latency)}
+	if err != nil {
+		attrs = append(attrs, slog.Any("error", err))
+	}
+	log.LogAttrs(ctx, level, "query", attrs...)
+}
+
+// ParamsFilter keeps bound values (player names, passwords) out of the SQL.
+func (l slogLogger) ParamsFilter(_ context.Context, sql string, _ ...any) (string, []any) {
+	return sql, nil
+}
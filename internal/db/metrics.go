@@ -0,0 +1,57 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+	"gorm.io/gorm"
+)
+
+// metricsPlugin times every statement into metrics.DBQueryDuration, labelled
+// with GORM's operation (create, query, update, delete, row, raw).
+type metricsPlugin struct{}
+
+const startedAtKey = "catbot:started_at"
+
+func (metricsPlugin) Name() string { return "catbot:metrics" }
+
+func (metricsPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("catbot:metrics_before_create", startTimer),
+		cb.Create().After("gorm:create").Register("catbot:metrics_after_create", observe("create")),
+		cb.Query().Before("gorm:query").Register("catbot:metrics_before_query", startTimer),
+		cb.Query().After("gorm:query").Register("catbot:metrics_after_query", observe("query")),
+		cb.Update().Before("gorm:update").Register("catbot:metrics_before_update", startTimer),
+		cb.Update().After("gorm:update").Register("catbot:metrics_after_update", observe("update")),
+		cb.Delete().Before("gorm:delete").Register("catbot:metrics_before_delete", startTimer),
+		cb.Delete().After("gorm:delete").Register("catbot:metrics_after_delete", observe("delete")),
+		cb.Row().Before("gorm:row").Register("catbot:metrics_before_row", startTimer),
+		cb.Row().After("gorm:row").Register("catbot:metrics_after_row", observe("row")),
+		cb.Raw().Before("gorm:raw").Register("catbot:metrics_before_raw", startTimer),
+		cb.Raw().After("gorm:raw").Register("catbot:metrics_after_raw", observe("raw")),
+	)
+}
+
+func startTimer(db *gorm.DB) {
+	db.InstanceSet(startedAtKey, time.Now())
+}
+
+func observe(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startedAtKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+		status := "ok"
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			status = "error"
+		}
+		metrics.DBQueryDuration.WithLabelValues(op, status).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,137 @@
+package cat_event
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db"
+)
+
+/*
+MODEL
+One row per interaction with Purrito and per daily decay. Rows are only ever
+appended, so a player's love can be traced back ("who fed Purrito
+yesterday?", "why did my love drop?") while cat_player keeps only the
+current values.
+*/
+
+// Actions besides the interaction commands (pet, love, feed, laser, catnip,
+// slap, kick).
+const (
+	ActionDecay         = "decay"
+	ActionBond          = "bond"    // BondPoints awarded after an interaction
+	ActionSetLove       = "setlove" // an admin's !setlove
+	ActionSetBondPoints = "setbp"   // an admin's !setbp
+)
+
+// Outcomes.
+const (
+	OutcomeAccepted = "accepted" // Purrito liked it
+	OutcomeRejected = "rejected" // he didn't
+	OutcomeWarned   = "warned"   // first slap/kick: a warning, no love lost
+	OutcomePunished = "punished" // slap/kick after the warning
+	OutcomeDecayed  = "decayed"  // a bonded player skipped a game day
+	OutcomeAwarded  = "awarded"  // BondPoints for a bonded player's day
+	OutcomeSet      = "set"      // an admin set the value
+)
+
+type CatEvent struct {
+	ID         uint64    `gorm:"column:id;primaryKey;autoIncrement"`
+	CreatedAt  time.Time `gorm:"column:created_at;not null"`
+	Network    string    `gorm:"column:network;not null"`
+	Channel    string    `gorm:"column:channel;not null"`
+	Player     string    `gorm:"column:player;not null"`
+	Action     string    `gorm:"column:action;not null"`
+	Outcome    string    `gorm:"column:outcome;not null"`
+	LoveBefore int       `gorm:"column:love_before;not null"`
+	LoveAfter  int       `gorm:"column:love_after;not null"`
+	BondPoints int       `gorm:"column:bond_points;not null;default:0"` // awarded by this event; for setbp, the new total
+}
+
+func (CatEvent) TableName() string { return "cat_event" }
+
+// Query narrows History; zero fields don't filter.
+type Query struct {
+	Player string
+	Action string
+	Since  time.Time // inclusive
+	Until  time.Time // exclusive
+	Limit  int       // <= 0 = DefaultLimit, capped at MaxLimit
+}
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 500
+)
+
+func (q Query) limit() int {
+	switch {
+	case q.Limit <= 0:
+		return DefaultLimit
+	case q.Limit > MaxLimit:
+		return MaxLimit
+	}
+	return q.Limit
+}
+
+/*
+REPOSITORY INTERFACE
+*/
+
+type CatEventRepository interface {
+	// Append writes e, stamping CreatedAt when it is zero.
+	Append(ctx context.Context, e *CatEvent) error
+	// History returns the channel's events matching q, newest first.
+	History(ctx context.Context, network, channel string, q Query) ([]*CatEvent, error)
+}
+
+/*
+REPOSITORY IMPL
+*/
+
+type CatEventRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewCatEventRepository(database *db.DB) CatEventRepository {
+	return &CatEventRepositoryImpl{db: database}
+}
+
+func norm(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+// normalize lowercases the scope like cat_player and fills in CreatedAt.
+func normalize(e *CatEvent) {
+	e.Network, e.Channel, e.Player = norm(e.Network), norm(e.Channel), norm(e.Player)
+	e.Action = norm(e.Action)
+	if e.CreatedAt.IsZero() {
+		e.CreatedAt = time.Now()
+	}
+	e.CreatedAt = e.CreatedAt.UTC()
+}
+
+func (r *CatEventRepositoryImpl) Append(ctx context.Context, e *CatEvent) error {
+	normalize(e)
+	return r.db.DB.WithContext(ctx).Create(e).Error
+}
+
+func (r *CatEventRepositoryImpl) History(ctx context.Context, network, channel string, q Query) ([]*CatEvent, error) {
+	tx := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", norm(network), norm(channel))
+	if q.Player != "" {
+		tx = tx.Where("player = ?", norm(q.Player))
+	}
+	if q.Action != "" {
+		tx = tx.Where("action = ?", norm(q.Action))
+	}
+	if !q.Since.IsZero() {
+		tx = tx.Where("created_at >= ?", q.Since.UTC())
+	}
+	if !q.Until.IsZero() {
+		tx = tx.Where("created_at < ?", q.Until.UTC())
+	}
+
+	var out []*CatEvent
+	err := tx.Order("created_at DESC").Order("id DESC").Limit(q.limit()).Find(&out).Error
+	return out, err
+}
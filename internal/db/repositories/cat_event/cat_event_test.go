@@ -0,0 +1,96 @@
+package cat_event_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+)
+
+func TestCatEventRepository(t *testing.T) {
+	repos := map[string]func(t *testing.T) cat_event.CatEventRepository{
+		"memory": func(*testing.T) cat_event.CatEventRepository {
+			return cat_event.NewMemoryCatEventRepository()
+		},
+		"sqlite": func(t *testing.T) cat_event.CatEventRepository {
+			database := db.NewDatabase(config.DBConfig{Driver: db.DriverSQLite, Path: ":memory:"})
+			if err := migrations.MigrateDatabaseUp(database); err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+			t.Cleanup(func() { _ = database.Close() })
+			return cat_event.NewCatEventRepository(database)
+		},
+	}
+
+	yesterday := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	today := yesterday.Add(24 * time.Hour)
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			ctx := context.Background()
+
+			for _, e := range []cat_event.CatEvent{
+				{CreatedAt: yesterday.Add(9 * time.Hour), Player: "Alice", Action: "feed", Outcome: cat_event.OutcomeAccepted, LoveBefore: 10, LoveAfter: 11},
+				{CreatedAt: yesterday.Add(10 * time.Hour), Player: "bob", Action: "feed", Outcome: cat_event.OutcomeRejected, LoveBefore: 5, LoveAfter: 4},
+				{CreatedAt: yesterday.Add(11 * time.Hour), Player: "alice", Action: "pet", Outcome: cat_event.OutcomeAccepted, LoveBefore: 11, LoveAfter: 12},
+				{CreatedAt: today.Add(time.Hour), Player: "alice", Action: "feed", Outcome: cat_event.OutcomeAccepted, LoveBefore: 12, LoveAfter: 13, BondPoints: 1},
+				// appended late but happened first: order is by time, not insertion
+				{CreatedAt: yesterday, Player: "alice", Action: cat_event.ActionDecay, Outcome: cat_event.OutcomeDecayed, LoveBefore: 15, LoveAfter: 10},
+			} {
+				e.Network, e.Channel = "TestNet", "#TestChan"
+				if err := repo.Append(ctx, &e); err != nil {
+					t.Fatalf("Append: %v", err)
+				}
+				if e.ID == 0 {
+					t.Fatal("Append should assign an ID")
+				}
+			}
+			other := cat_event.CatEvent{Network: "testnet", Channel: "#other", Player: "alice", Action: "pet", Outcome: cat_event.OutcomeAccepted}
+			if err := repo.Append(ctx, &other); err != nil {
+				t.Fatal(err)
+			}
+
+			history := func(q cat_event.Query) []*cat_event.CatEvent {
+				t.Helper()
+				out, err := repo.History(ctx, "testnet", "#testchan", q)
+				if err != nil {
+					t.Fatalf("History: %v", err)
+				}
+				return out
+			}
+
+			// a player's history, newest first
+			got := history(cat_event.Query{Player: "ALICE"})
+			if len(got) != 4 {
+				t.Fatalf("alice: got %d events, want 4", len(got))
+			}
+			want := []int{13, 12, 11, 10}
+			for i, e := range got {
+				if e.LoveAfter != want[i] || e.Player != "alice" {
+					t.Errorf("alice[%d] = %s %s %d→%d, want love after %d", i, e.Player, e.Action, e.LoveBefore, e.LoveAfter, want[i])
+				}
+			}
+			if !got[0].CreatedAt.Equal(today.Add(time.Hour)) || got[0].BondPoints != 1 {
+				t.Errorf("newest event not stored as written: %+v", got[0])
+			}
+
+			// who fed Purrito yesterday?
+			fed := history(cat_event.Query{Action: "feed", Since: yesterday, Until: today})
+			if len(fed) != 2 || fed[0].Player != "bob" || fed[1].Player != "alice" {
+				t.Errorf("fed yesterday: %+v", fed)
+			}
+
+			if got := history(cat_event.Query{Limit: 2}); len(got) != 2 || got[0].LoveAfter != 13 {
+				t.Errorf("limit 2: %+v", got)
+			}
+			if got := history(cat_event.Query{Player: "nobody"}); len(got) != 0 {
+				t.Errorf("unknown player: %+v", got)
+			}
+		})
+	}
+}
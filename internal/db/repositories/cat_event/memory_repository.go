@@ -0,0 +1,66 @@
+package cat_event
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+/*
+IN-MEMORY REPOSITORY
+Used by unit tests and "serve --memory".
+*/
+
+type MemoryCatEventRepository struct {
+	mu     sync.RWMutex
+	events []CatEvent // in insertion order
+}
+
+func NewMemoryCatEventRepository() CatEventRepository {
+	return &MemoryCatEventRepository{}
+}
+
+func (r *MemoryCatEventRepository) Append(_ context.Context, e *CatEvent) error {
+	normalize(e)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	e.ID = uint64(len(r.events) + 1)
+	r.events = append(r.events, *e)
+	return nil
+}
+
+func (r *MemoryCatEventRepository) History(_ context.Context, network, channel string, q Query) ([]*CatEvent, error) {
+	network, channel = norm(network), norm(channel)
+	player, action := norm(q.Player), norm(q.Action)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*CatEvent
+	for i := range r.events {
+		e := r.events[i]
+		switch {
+		case e.Network != network || e.Channel != channel,
+			player != "" && e.Player != player,
+			action != "" && e.Action != action,
+			!q.Since.IsZero() && e.CreatedAt.Before(q.Since),
+			!q.Until.IsZero() && !e.CreatedAt.Before(q.Until):
+			continue
+		}
+		out = append(out, &e)
+	}
+
+	// newest first, like ORDER BY created_at DESC, id DESC
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.After(out[j].CreatedAt)
+		}
+		return out[i].ID > out[j].ID
+	})
+	if len(out) > q.limit() {
+		out = out[:q.limit()]
+	}
+	return out, nil
+}
@@ -0,0 +1,278 @@
+package cat_player
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+IN-MEMORY REPOSITORY
+Same normalization, upsert and ordering semantics as CatPlayerRepositoryImpl.
+Used by unit tests and "serve --memory" (dry-run without a database).
+*/
+
+type MemoryCatPlayerRepository struct {
+	mu      sync.RWMutex
+	players map[string]*CatPlayer // key: name|network|channel (normalized)
+}
+
+func NewMemoryPlayerRepository() CatPlayerRepository {
+	return &MemoryCatPlayerRepository{
+		players: make(map[string]*CatPlayer),
+	}
+}
+
+func memKey(name, network, channel string) string {
+	network, channel = normScope(network, channel)
+	return norm(name) + "|" + network + "|" + channel
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+// clonePlayer returns a detached copy so callers never share state with the store.
+func clonePlayer(p *CatPlayer) *CatPlayer {
+	c := *p
+	c.LastInteractedAt = copyTime(p.LastInteractedAt)
+	c.LastDecayAt = copyTime(p.LastDecayAt)
+	c.LastBondPointsAt = copyTime(p.LastBondPointsAt)
+	c.LastStreakAt = copyTime(p.LastStreakAt)
+	return &c
+}
+
+// update applies fn to the stored player (if any) under the write lock.
+func (r *MemoryCatPlayerRepository) update(name, network, channel string, fn func(p *CatPlayer)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p, ok := r.players[memKey(name, network, channel)]; ok {
+		fn(p)
+		p.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// list returns copies of all players in scope matching keep, ordered by name.
+func (r *MemoryCatPlayerRepository) list(network, channel string, keep func(p *CatPlayer) bool) []*CatPlayer {
+	network, channel = normScope(network, channel)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*CatPlayer
+	for _, p := range r.players {
+		if p.Network != network || p.Channel != channel {
+			continue
+		}
+		if keep != nil && !keep(p) {
+			continue
+		}
+		out = append(out, clonePlayer(p))
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+/*
+CRUD
+*/
+
+func (r *MemoryCatPlayerRepository) GetPlayerByID(_ context.Context, id string) (*CatPlayer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, p := range r.players {
+		if p.ID == id {
+			return clonePlayer(p), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *MemoryCatPlayerRepository) GetAllPlayers(_ context.Context, network, channel string) ([]*CatPlayer, error) {
+	return r.list(network, channel, nil), nil
+}
+
+func (r *MemoryCatPlayerRepository) GetPlayerByName(_ context.Context, name, network, channel string) (*CatPlayer, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if p, ok := r.players[memKey(name, network, channel)]; ok {
+		return clonePlayer(p), nil
+	}
+	return nil, nil
+}
+
+// Upsert by (name, network, channel)
+func (r *MemoryCatPlayerRepository) UpsertPlayer(_ context.Context, player *CatPlayer) error {
+	player.Name = norm(player.Name)
+	player.Network, player.Channel = normScope(player.Network, player.Channel)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	key := memKey(player.Name, player.Network, player.Channel)
+
+	if existing, ok := r.players[key]; ok {
+		// keep same primary key
+		player.ID = existing.ID
+		if player.CreatedAt.IsZero() {
+			player.CreatedAt = existing.CreatedAt
+		}
+	} else {
+		if player.ID == "" {
+			id, err := newID()
+			if err != nil {
+				return err
+			}
+			player.ID = id
+		}
+		if player.CreatedAt.IsZero() {
+			player.CreatedAt = now
+		}
+	}
+	player.UpdatedAt = now
+
+	r.players[key] = clonePlayer(player)
+	return nil
+}
+
+/*
+LEADERBOARD
+*/
+
+func (r *MemoryCatPlayerRepository) TopLoveMeter(_ context.Context, network, channel string, limit int) ([]*CatPlayer, error) {
+	if limit <= 0 {
+		limit = 5
+	}
+
+	players := r.list(network, channel, nil)
+	sort.SliceStable(players, func(i, j int) bool { return players[i].LoveMeter > players[j].LoveMeter })
+
+	if len(players) > limit {
+		players = players[:limit]
+	}
+	return players, nil
+}
+
+func (r *MemoryCatPlayerRepository) PageByLove(_ context.Context, network, channel string, offset, limit int) ([]*CatPlayer, int, error) {
+	players := r.list(network, channel, nil)
+	sort.SliceStable(players, func(i, j int) bool { return players[i].LoveMeter > players[j].LoveMeter })
+
+	total := len(players)
+	if offset > total {
+		offset = total
+	}
+	players = players[offset:]
+	if len(players) > limit {
+		players = players[:limit]
+	}
+	return players, total, nil
+}
+
+/*
+DAILY DECAY HELPERS
+*/
+
+func (r *MemoryCatPlayerRepository) TouchInteraction(_ context.Context, name, network, channel string, t time.Time) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.LastInteractedAt = &t })
+}
+
+func (r *MemoryCatPlayerRepository) SetDecayAt(_ context.Context, name, network, channel string, t time.Time) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.LastDecayAt = &t })
+}
+
+func (r *MemoryCatPlayerRepository) ListPlayersAtOrAbove(_ context.Context, network, channel string, minLove int) ([]*CatPlayer, error) {
+	return r.list(network, channel, func(p *CatPlayer) bool { return p.LoveMeter >= minLove }), nil
+}
+
+func (r *MemoryCatPlayerRepository) SetPerfectDropWarned(_ context.Context, name, network, channel string, warned bool) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.PerfectDropWarned = warned })
+}
+
+/*
+BOND HELPERS
+*/
+
+func (r *MemoryCatPlayerRepository) AddBondPoints(_ context.Context, name, network, channel string, delta int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.BondPoints += delta })
+}
+
+func (r *MemoryCatPlayerRepository) SetBondPoints(_ context.Context, name, network, channel string, points int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.BondPoints = points })
+}
+
+func (r *MemoryCatPlayerRepository) SetBondPointsAt(_ context.Context, name, network, channel string, t time.Time) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.LastBondPointsAt = &t })
+}
+
+func (r *MemoryCatPlayerRepository) SetBondPointStreak(_ context.Context, name, network, channel string, streak int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.BondPointStreak = streak })
+}
+
+func (r *MemoryCatPlayerRepository) SetHighestBondStreak(_ context.Context, name, network, channel string, streak int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.HighestBondStreak = streak })
+}
+
+/*
+GIFTS (bitmask)
+*/
+
+func (r *MemoryCatPlayerRepository) AddGiftsUnlocked(_ context.Context, name, network, channel string, giftMask int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.GiftsUnlocked |= giftMask })
+}
+
+func (r *MemoryCatPlayerRepository) SetGiftsUnlocked(_ context.Context, name, network, channel string, giftsUnlocked int) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.GiftsUnlocked = giftsUnlocked })
+}
+
+/*
+DAILY STREAK HELPERS
+*/
+
+func (r *MemoryCatPlayerRepository) SetStreak(_ context.Context, name, network, channel string, current, highest int, t time.Time) error {
+	return r.update(name, network, channel, func(p *CatPlayer) {
+		p.CurrentStreak = current
+		p.HighestStreak = highest
+		p.LastStreakAt = &t
+	})
+}
+
+func (r *MemoryCatPlayerRepository) ResetStreak(_ context.Context, name, network, channel string) error {
+	return r.update(name, network, channel, func(p *CatPlayer) { p.CurrentStreak = 0 })
+}
+
+func (r *MemoryCatPlayerRepository) SetLoveMeter(_ context.Context, nick, network, channel string, love int) error {
+	return r.update(nick, network, channel, func(p *CatPlayer) { p.LoveMeter = love })
+}
+
+func (r *MemoryCatPlayerRepository) AddLove(_ context.Context, nick, network, channel string, delta int) (*CatPlayer, int, error) {
+	network, channel = normScope(network, channel)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	key := memKey(nick, network, channel)
+	p, ok := r.players[key]
+	if !ok {
+		id, err := newID()
+		if err != nil {
+			return nil, 0, err
+		}
+		p = &CatPlayer{ID: id, CreatedAt: now, Name: norm(nick), Network: network, Channel: channel}
+		r.players[key] = p
+	}
+	p.PreviousLove = p.LoveMeter
+	p.LoveMeter = min(100, max(0, p.LoveMeter+delta))
+	p.UpdatedAt = now
+	return clonePlayer(p), p.PreviousLove, nil
+}
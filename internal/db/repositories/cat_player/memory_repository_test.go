@@ -0,0 +1,49 @@
+package cat_player_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player/repotest"
+)
+
+func TestMemoryCatPlayerRepository(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) cat_player.CatPlayerRepository {
+		return cat_player.NewMemoryPlayerRepository()
+	})
+}
+
+func TestMemoryCatPlayerRepository_Concurrent(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	ctx := context.Background()
+
+	if err := repo.UpsertPlayer(ctx, &cat_player.CatPlayer{Name: "shared", Network: "testnet", Channel: "#testchan"}); err != nil {
+		t.Fatalf("UpsertPlayer: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_ = repo.UpsertPlayer(ctx, &cat_player.CatPlayer{Name: "p" + strconv.Itoa(i), Network: "testnet", Channel: "#testchan"})
+			_ = repo.AddBondPoints(ctx, "shared", "testnet", "#testchan", 1)
+			_, _ = repo.TopLoveMeter(ctx, "testnet", "#testchan", 10)
+			_, _ = repo.GetPlayerByName(ctx, "shared", "testnet", "#testchan")
+		}(i)
+	}
+	wg.Wait()
+
+	p, _ := repo.GetPlayerByName(ctx, "shared", "testnet", "#testchan")
+	if p == nil || p.BondPoints != 50 {
+		t.Fatalf("expected 50 bond points after concurrent adds, got %+v", p)
+	}
+
+	all, _ := repo.GetAllPlayers(ctx, "testnet", "#testchan")
+	if len(all) != 51 {
+		t.Errorf("expected 51 players, got %d", len(all))
+	}
+}
@@ -1,6 +1,38 @@
 package cat_player
 
+import (
+	"crypto/rand"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
 // TableName overrides the default table name.
 func (CatPlayer) TableName() string {
 	return "cat_player"
 }
+
+// BeforeCreate assigns a UUID in Go so inserts don't depend on
+// gen_random_uuid() (postgres only).
+func (p *CatPlayer) BeforeCreate(_ *gorm.DB) error {
+	if p.ID != "" {
+		return nil
+	}
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	p.ID = id
+	return nil
+}
+
+// newID returns a random (version 4) UUID string.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -3,6 +3,7 @@ package cat_player
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -15,16 +16,17 @@ MODEL
 */
 
 type CatPlayer struct {
-	ID        string    `gorm:"column:id;primaryKey;type:uuid;default:gen_random_uuid()" json:"id"`
+	ID        string    `gorm:"column:id;primaryKey;type:uuid" json:"id"` // generated in BeforeCreate (portable across postgres/sqlite)
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
 
-	Name    string `gorm:"column:name;type:varchar(100);not null;index:idx_player_scope,priority:1"`
-	Network string `gorm:"column:network;type:varchar(100);not null;index:idx_player_scope,priority:2"`
-	Channel string `gorm:"column:channel;type:varchar(100);not null;index:idx_player_scope,priority:3"`
+	Name    string `gorm:"column:name;type:varchar(100);not null;index:idx_player_scope,priority:1;uniqueIndex:idx_player_unique,priority:1"`
+	Network string `gorm:"column:network;type:varchar(100);not null;index:idx_player_scope,priority:2;uniqueIndex:idx_player_unique,priority:2"`
+	Channel string `gorm:"column:channel;type:varchar(100);not null;index:idx_player_scope,priority:3;uniqueIndex:idx_player_unique,priority:3"`
 
-	LoveMeter int `gorm:"column:love_meter;type:int;not null;default:0"`
-	Count     int `gorm:"column:count;type:int;not null;default:0"`
+	LoveMeter    int `gorm:"column:love_meter;type:int;not null;default:0"`
+	PreviousLove int `gorm:"column:previous_love;type:int;not null;default:0"` // love before the last AddLove
+	Count        int `gorm:"column:count;type:int;not null;default:0"`
 
 	LastInteractedAt *time.Time `gorm:"column:last_interacted_at;index"`
 	LastDecayAt      *time.Time `gorm:"column:last_decay_at;index"`
@@ -40,7 +42,10 @@ type CatPlayer struct {
 	// bitmask gifts
 	GiftsUnlocked int `gorm:"column:gifts_unlocked;type:int;not null;default:0"`
 
-	HighestStreak int `gorm:"column:highest_streak;type:int;not null;default:0" json:"highest_streak"`
+	// daily bonding streak (drives HighestStreak, titles and gifts)
+	CurrentStreak int        `gorm:"column:current_streak;type:int;not null;default:0" json:"current_streak"`
+	LastStreakAt  *time.Time `gorm:"column:last_streak_at;index" json:"last_streak_at"`
+	HighestStreak int        `gorm:"column:highest_streak;type:int;not null;default:0" json:"highest_streak"`
 }
 
 /*
@@ -54,6 +59,9 @@ type CatPlayerRepository interface {
 
 	UpsertPlayer(ctx context.Context, player *CatPlayer) error
 	TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*CatPlayer, error)
+	// PageByLove is one page of the leaderboard (TopLoveMeter's order) and
+	// the number of players in the channel.
+	PageByLove(ctx context.Context, network, channel string, offset, limit int) ([]*CatPlayer, int, error)
 
 	// daily decay helpers
 	TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error
@@ -63,6 +71,7 @@ type CatPlayerRepository interface {
 
 	// bond helpers
 	AddBondPoints(ctx context.Context, name, network, channel string, delta int) error
+	SetBondPoints(ctx context.Context, name, network, channel string, points int) error
 	SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error
 	SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error
 	SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error
@@ -71,7 +80,17 @@ type CatPlayerRepository interface {
 	AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error
 	SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error
 
+	// daily streak helpers
+	SetStreak(ctx context.Context, name, network, channel string, current, highest int, t time.Time) error
+	ResetStreak(ctx context.Context, name, network, channel string) error
+
 	SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error
+	// AddLove adds delta to the player's love, clamped to 0..100, creating
+	// the player if needed, and returns the updated row and the love it had
+	// before. It is a single statement, so concurrent changes to the same
+	// player are never lost and before is always the love this change
+	// started from.
+	AddLove(ctx context.Context, nick, network, channel string, delta int) (p *CatPlayer, before int, err error)
 }
 
 /*
@@ -177,6 +196,7 @@ func (r *CatPlayerRepositoryImpl) TopLoveMeter(ctx context.Context, network, cha
 	if err := r.db.DB.WithContext(ctx).
 		Where("network = ? AND channel = ?", network, channel).
 		Order("love_meter DESC").
+		Order("name ASC").
 		Limit(limit).
 		Find(&players).Error; err != nil {
 		return nil, err
@@ -184,6 +204,28 @@ func (r *CatPlayerRepositoryImpl) TopLoveMeter(ctx context.Context, network, cha
 	return players, nil
 }
 
+func (r *CatPlayerRepositoryImpl) PageByLove(ctx context.Context, network, channel string, offset, limit int) ([]*CatPlayer, int, error) {
+	network, channel = normScope(network, channel)
+
+	scope := r.db.DB.WithContext(ctx).Model(&CatPlayer{}).Where("network = ? AND channel = ?", network, channel)
+	var total int64
+	if err := scope.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var players []*CatPlayer
+	if err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", network, channel).
+		Order("love_meter DESC").
+		Order("name ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&players).Error; err != nil {
+		return nil, 0, err
+	}
+	return players, int(total), nil
+}
+
 /*
 DAILY DECAY HELPERS
 */
@@ -244,6 +286,16 @@ func (r *CatPlayerRepositoryImpl) AddBondPoints(ctx context.Context, name, netwo
 		UpdateColumn("bond_points", gorm.Expr("bond_points + ?", delta)).Error
 }
 
+func (r *CatPlayerRepositoryImpl) SetBondPoints(ctx context.Context, name, network, channel string, points int) error {
+	name = norm(name)
+	network, channel = normScope(network, channel)
+
+	return r.db.DB.WithContext(ctx).
+		Model(&CatPlayer{}).
+		Where("name = ? AND network = ? AND channel = ?", name, network, channel).
+		Update("bond_points", points).Error
+}
+
 func (r *CatPlayerRepositoryImpl) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
 	name = norm(name)
 	network, channel = normScope(network, channel)
@@ -299,6 +351,34 @@ func (r *CatPlayerRepositoryImpl) SetGiftsUnlocked(ctx context.Context, name, ne
 		Update("gifts_unlocked", giftsUnlocked).Error
 }
 
+/*
+DAILY STREAK HELPERS
+*/
+
+func (r *CatPlayerRepositoryImpl) SetStreak(ctx context.Context, name, network, channel string, current, highest int, t time.Time) error {
+	name = norm(name)
+	network, channel = normScope(network, channel)
+
+	return r.db.DB.WithContext(ctx).
+		Model(&CatPlayer{}).
+		Where("name = ? AND network = ? AND channel = ?", name, network, channel).
+		Updates(map[string]interface{}{
+			"current_streak": current,
+			"highest_streak": highest,
+			"last_streak_at": &t,
+		}).Error
+}
+
+func (r *CatPlayerRepositoryImpl) ResetStreak(ctx context.Context, name, network, channel string) error {
+	name = norm(name)
+	network, channel = normScope(network, channel)
+
+	return r.db.DB.WithContext(ctx).
+		Model(&CatPlayer{}).
+		Where("name = ? AND network = ? AND channel = ?", name, network, channel).
+		Update("current_streak", 0).Error
+}
+
 func (r *CatPlayerRepositoryImpl) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
 	nick = norm(nick)
 	network, channel = normScope(network, channel)
@@ -308,3 +388,50 @@ func (r *CatPlayerRepositoryImpl) SetLoveMeter(ctx context.Context, nick, networ
 		Where("name = ? AND network = ? AND channel = ?", nick, network, channel).
 		Update("love_meter", love).Error
 }
+
+/*
+ATOMIC LOVE
+One INSERT ... ON CONFLICT DO UPDATE ... RETURNING, with no transaction or
+row lock (relies on idx_player_unique). The update copies the old love to
+previous_love in the same statement, so the love before and after a change
+always belong together.
+*/
+
+// clampLove is the SQL for min(100, max(0, expr)) in the database's dialect.
+func (r *CatPlayerRepositoryImpl) clampLove(expr string) string {
+	if r.db.Driver == db.DriverSQLite {
+		return fmt.Sprintf("MIN(100, MAX(0, %s))", expr) // scalar MIN/MAX
+	}
+	return fmt.Sprintf("LEAST(100, GREATEST(0, %s))", expr)
+}
+
+func (r *CatPlayerRepositoryImpl) AddLove(ctx context.Context, nick, network, channel string, delta int) (*CatPlayer, int, error) {
+	nick = norm(nick)
+	network, channel = normScope(network, channel)
+
+	id, err := newID()
+	if err != nil {
+		return nil, 0, err
+	}
+	now := time.Now()
+
+	// the update adds the bound delta rather than EXCLUDED.love_meter: the
+	// inserted love is already clamped, so it is not the delta
+	query := `INSERT INTO cat_player (id, name, network, channel, love_meter, created_at, updated_at)
+VALUES (?, ?, ?, ?, ` + r.clampLove("?") + `, ?, ?)
+ON CONFLICT (network, channel, name) DO UPDATE
+SET previous_love = cat_player.love_meter,
+    love_meter = ` + r.clampLove("cat_player.love_meter + ?") + `,
+    updated_at = EXCLUDED.updated_at
+RETURNING *`
+
+	var p CatPlayer
+	res := r.db.DB.WithContext(ctx).Raw(query, id, nick, network, channel, delta, now, now, delta).Scan(&p)
+	if res.Error != nil {
+		return nil, 0, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return nil, 0, fmt.Errorf("add love for %s: no row returned", nick)
+	}
+	return &p, p.PreviousLove, nil
+}
@@ -0,0 +1,92 @@
+package cat_player_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player/repotest"
+)
+
+// Repository tests run against every available backend:
+//   - sqlite (in-memory) always
+//   - postgres when TEST_DBHOST is set (TEST_DBPORT, TEST_DBNAME, TEST_DBUSERNAME, TEST_DBPASSWORD)
+
+type backend struct {
+	name string
+	open func(t *testing.T) *db.DB
+}
+
+func backends() []backend {
+	out := []backend{{
+		name: "sqlite",
+		open: func(t *testing.T) *db.DB {
+			return db.NewDatabase(config.DBConfig{Driver: db.DriverSQLite, Path: ":memory:"})
+		},
+	}}
+
+	host := os.Getenv("TEST_DBHOST")
+	if host == "" {
+		return out
+	}
+
+	port, _ := strconv.Atoi(os.Getenv("TEST_DBPORT"))
+	if port == 0 {
+		port = 5432
+	}
+	cfg := config.DBConfig{
+		Driver:   db.DriverPostgres,
+		Host:     host,
+		Port:     uint(port),
+		DataBase: envOr("TEST_DBNAME", "catbot_test"),
+		User:     envOr("TEST_DBUSERNAME", "postgres"),
+		Password: envOr("TEST_DBPASSWORD", "mysecretpassword"),
+		SSLMode:  "disable",
+	}
+
+	return append(out, backend{
+		name: "postgres",
+		open: func(t *testing.T) *db.DB {
+			return db.NewDatabase(cfg)
+		},
+	})
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func openRepo(t *testing.T, b backend) cat_player.CatPlayerRepository {
+	t.Helper()
+
+	database := b.open(t)
+	if err := migrations.MigrateDatabaseUp(database); err != nil {
+		t.Fatalf("migrate %s: %v", b.name, err)
+	}
+	if err := database.DB.Exec("DELETE FROM cat_player").Error; err != nil {
+		t.Fatalf("clean %s: %v", b.name, err)
+	}
+	t.Cleanup(func() {
+		if sqldb, err := database.DB.DB(); err == nil {
+			_ = sqldb.Close()
+		}
+	})
+
+	return cat_player.NewPlayerRepository(database)
+}
+
+func TestCatPlayerRepository(t *testing.T) {
+	for _, b := range backends() {
+		b := b
+		t.Run(b.name, func(t *testing.T) {
+			repotest.RunConformance(t, func(t *testing.T) cat_player.CatPlayerRepository { return openRepo(t, b) })
+		})
+	}
+}
@@ -0,0 +1,469 @@
+// Package repotest holds the shared conformance suite for
+// cat_player.CatPlayerRepository implementations.
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+)
+
+func sameInstant(a, b time.Time) bool {
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d < time.Second
+}
+
+func mustUpsert(t *testing.T, repo cat_player.CatPlayerRepository, p *cat_player.CatPlayer) {
+	t.Helper()
+	if err := repo.UpsertPlayer(context.Background(), p); err != nil {
+		t.Fatalf("UpsertPlayer: %v", err)
+	}
+}
+
+func mustGet(t *testing.T, repo cat_player.CatPlayerRepository, name string) *cat_player.CatPlayer {
+	t.Helper()
+	p, err := repo.GetPlayerByName(context.Background(), name, "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("GetPlayerByName: %v", err)
+	}
+	if p == nil {
+		t.Fatalf("player %s not found", name)
+	}
+	return p
+}
+
+// RunConformance runs the behaviour every cat_player.CatPlayerRepository
+// implementation must share. newRepo must return an empty repository.
+func RunConformance(t *testing.T, newRepo func(t *testing.T) cat_player.CatPlayerRepository) {
+	ctx := context.Background()
+
+	t.Run("GetPlayerByName_Missing", func(t *testing.T) {
+		repo := newRepo(t)
+		p, err := repo.GetPlayerByName(ctx, "nobody", "testnet", "#testchan")
+		if err != nil || p != nil {
+			t.Fatalf("expected (nil, nil), got (%v, %v)", p, err)
+		}
+	})
+
+	t.Run("UpsertPlayer_NormalizesAndAssignsID", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "  Player1 ", Network: "TestNet", Channel: "#TestChan", LoveMeter: 10})
+
+		p := mustGet(t, repo, "PLAYER1")
+		if p.ID == "" {
+			t.Error("expected ID to be generated")
+		}
+		if p.Name != "player1" || p.Network != "testnet" || p.Channel != "#testchan" {
+			t.Errorf("expected normalized scope, got %q %q %q", p.Name, p.Network, p.Channel)
+		}
+		if p.LoveMeter != 10 {
+			t.Errorf("expected love 10, got %d", p.LoveMeter)
+		}
+	})
+
+	t.Run("UpsertPlayer_UpdatesExisting", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan", LoveMeter: 10})
+		first := mustGet(t, repo, "player1")
+
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "Player1", Network: "testnet", Channel: "#testchan", LoveMeter: 42})
+		second := mustGet(t, repo, "player1")
+
+		if second.ID != first.ID {
+			t.Errorf("expected same ID after upsert, got %s vs %s", second.ID, first.ID)
+		}
+		if second.LoveMeter != 42 {
+			t.Errorf("expected love 42, got %d", second.LoveMeter)
+		}
+
+		all, err := repo.GetAllPlayers(ctx, "testnet", "#testchan")
+		if err != nil {
+			t.Fatalf("GetAllPlayers: %v", err)
+		}
+		if len(all) != 1 {
+			t.Errorf("expected 1 row after upsert, got %d", len(all))
+		}
+	})
+
+	t.Run("GetPlayerByID", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan"})
+		want := mustGet(t, repo, "player1")
+
+		got, err := repo.GetPlayerByID(ctx, want.ID)
+		if err != nil || got == nil {
+			t.Fatalf("GetPlayerByID: (%v, %v)", got, err)
+		}
+		if got.Name != "player1" {
+			t.Errorf("expected player1, got %s", got.Name)
+		}
+
+		missing, err := repo.GetPlayerByID(ctx, "00000000-0000-4000-8000-000000000000")
+		if err != nil || missing != nil {
+			t.Errorf("expected (nil, nil) for missing id, got (%v, %v)", missing, err)
+		}
+	})
+
+	t.Run("GetAllPlayers_ScopedByNetworkAndChannel", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "a", Network: "testnet", Channel: "#testchan"})
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "b", Network: "testnet", Channel: "#testchan"})
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "c", Network: "testnet", Channel: "#other"})
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "d", Network: "othernet", Channel: "#testchan"})
+
+		all, err := repo.GetAllPlayers(ctx, "TESTNET", "#TestChan")
+		if err != nil {
+			t.Fatalf("GetAllPlayers: %v", err)
+		}
+		if len(all) != 2 {
+			t.Errorf("expected 2 players in scope, got %d", len(all))
+		}
+	})
+
+	t.Run("TopLoveMeter_OrderAndLimit", func(t *testing.T) {
+		repo := newRepo(t)
+		for i, love := range []int{5, 50, 20, 100, 0, 75} {
+			mustUpsert(t, repo, &cat_player.CatPlayer{
+				Name:      "p" + strconv.Itoa(i),
+				Network:   "testnet",
+				Channel:   "#testchan",
+				LoveMeter: love,
+			})
+		}
+
+		top, err := repo.TopLoveMeter(ctx, "testnet", "#testchan", 3)
+		if err != nil {
+			t.Fatalf("TopLoveMeter: %v", err)
+		}
+		if len(top) != 3 {
+			t.Fatalf("expected 3 players, got %d", len(top))
+		}
+		for i, want := range []int{100, 75, 50} {
+			if top[i].LoveMeter != want {
+				t.Errorf("top[%d] love = %d, want %d", i, top[i].LoveMeter, want)
+			}
+		}
+
+		def, err := repo.TopLoveMeter(ctx, "testnet", "#testchan", 0)
+		if err != nil {
+			t.Fatalf("TopLoveMeter default: %v", err)
+		}
+		if len(def) != 5 {
+			t.Errorf("expected default limit of 5, got %d", len(def))
+		}
+	})
+
+	t.Run("TopLoveMeter_TiesOrderedByName", func(t *testing.T) {
+		repo := newRepo(t)
+		for _, name := range []string{"carol", "alice", "bob"} {
+			mustUpsert(t, repo, &cat_player.CatPlayer{Name: name, Network: "testnet", Channel: "#testchan", LoveMeter: 30})
+		}
+
+		top, err := repo.TopLoveMeter(ctx, "testnet", "#testchan", 10)
+		if err != nil {
+			t.Fatalf("TopLoveMeter: %v", err)
+		}
+		var names []string
+		for _, p := range top {
+			names = append(names, p.Name)
+		}
+		if strings.Join(names, ",") != "alice,bob,carol" {
+			t.Errorf("expected ties ordered by name, got %v", names)
+		}
+	})
+
+	t.Run("PageByLove_OffsetLimitAndTotal", func(t *testing.T) {
+		repo := newRepo(t)
+		for i, love := range []int{5, 50, 20, 100, 0, 75} {
+			mustUpsert(t, repo, &cat_player.CatPlayer{Name: "p" + strconv.Itoa(i), Network: "testnet", Channel: "#testchan", LoveMeter: love})
+		}
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "elsewhere", Network: "testnet", Channel: "#other", LoveMeter: 60})
+
+		for _, tc := range []struct {
+			offset, limit int
+			want          []int
+		}{
+			{0, 2, []int{100, 75}},
+			{2, 3, []int{50, 20, 5}},
+			{5, 10, []int{0}},
+			{10, 10, nil},
+		} {
+			page, total, err := repo.PageByLove(ctx, "TestNet", "#TestChan", tc.offset, tc.limit)
+			if err != nil {
+				t.Fatalf("PageByLove: %v", err)
+			}
+			if total != 6 {
+				t.Errorf("offset %d: total = %d, want 6", tc.offset, total)
+			}
+			var got []int
+			for _, p := range page {
+				got = append(got, p.LoveMeter)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("offset %d limit %d: got %v, want %v", tc.offset, tc.limit, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("GetPlayerByName_ReturnsCopy", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan", LoveMeter: 10})
+
+		p := mustGet(t, repo, "player1")
+		p.LoveMeter = 99
+
+		if got := mustGet(t, repo, "player1").LoveMeter; got != 10 {
+			t.Errorf("mutating a returned player must not change the store, got love %d", got)
+		}
+	})
+
+	t.Run("TouchInteraction_SetDecayAt", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan"})
+
+		now := time.Now().UTC().Truncate(time.Second)
+		if err := repo.TouchInteraction(ctx, "Player1", "testnet", "#testchan", now); err != nil {
+			t.Fatalf("TouchInteraction: %v", err)
+		}
+		if err := repo.SetDecayAt(ctx, "player1", "TestNet", "#testchan", now.Add(-time.Hour)); err != nil {
+			t.Fatalf("SetDecayAt: %v", err)
+		}
+
+		p := mustGet(t, repo, "player1")
+		if p.LastInteractedAt == nil || !sameInstant(*p.LastInteractedAt, now) {
+			t.Errorf("expected LastInteractedAt %v, got %v", now, p.LastInteractedAt)
+		}
+		if p.LastDecayAt == nil || !sameInstant(*p.LastDecayAt, now.Add(-time.Hour)) {
+			t.Errorf("expected LastDecayAt %v, got %v", now.Add(-time.Hour), p.LastDecayAt)
+		}
+	})
+
+	t.Run("ListPlayersAtOrAbove", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "a", Network: "testnet", Channel: "#testchan", LoveMeter: 100})
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "b", Network: "testnet", Channel: "#testchan", LoveMeter: 99})
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "c", Network: "testnet", Channel: "#other", LoveMeter: 100})
+
+		players, err := repo.ListPlayersAtOrAbove(ctx, "testnet", "#testchan", 100)
+		if err != nil {
+			t.Fatalf("ListPlayersAtOrAbove: %v", err)
+		}
+		if len(players) != 1 || players[0].Name != "a" {
+			t.Errorf("expected only player a, got %+v", players)
+		}
+	})
+
+	t.Run("SetPerfectDropWarned", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan"})
+
+		if err := repo.SetPerfectDropWarned(ctx, "player1", "testnet", "#testchan", true); err != nil {
+			t.Fatalf("SetPerfectDropWarned: %v", err)
+		}
+		if !mustGet(t, repo, "player1").PerfectDropWarned {
+			t.Error("expected PerfectDropWarned to be true")
+		}
+	})
+
+	t.Run("BondHelpers", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan", BondPoints: 3})
+
+		now := time.Now().UTC().Truncate(time.Second)
+		if err := repo.AddBondPoints(ctx, "player1", "testnet", "#testchan", 4); err != nil {
+			t.Fatalf("AddBondPoints: %v", err)
+		}
+		if err := repo.SetBondPointsAt(ctx, "player1", "testnet", "#testchan", now); err != nil {
+			t.Fatalf("SetBondPointsAt: %v", err)
+		}
+		if err := repo.SetBondPointStreak(ctx, "player1", "testnet", "#testchan", 6); err != nil {
+			t.Fatalf("SetBondPointStreak: %v", err)
+		}
+		if err := repo.SetHighestBondStreak(ctx, "player1", "testnet", "#testchan", 9); err != nil {
+			t.Fatalf("SetHighestBondStreak: %v", err)
+		}
+
+		p := mustGet(t, repo, "player1")
+		if p.BondPoints != 7 {
+			t.Errorf("expected 7 bond points, got %d", p.BondPoints)
+		}
+		if p.LastBondPointsAt == nil || !sameInstant(*p.LastBondPointsAt, now) {
+			t.Errorf("expected LastBondPointsAt %v, got %v", now, p.LastBondPointsAt)
+		}
+		if p.BondPointStreak != 6 || p.HighestBondStreak != 9 {
+			t.Errorf("expected streak 6 / highest 9, got %d / %d", p.BondPointStreak, p.HighestBondStreak)
+		}
+
+		if err := repo.SetBondPoints(ctx, "player1", "testnet", "#testchan", 2); err != nil {
+			t.Fatalf("SetBondPoints: %v", err)
+		}
+		if p := mustGet(t, repo, "player1"); p.BondPoints != 2 {
+			t.Errorf("expected 2 bond points after SetBondPoints, got %d", p.BondPoints)
+		}
+	})
+
+	t.Run("Gifts", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan", GiftsUnlocked: 1})
+
+		if err := repo.AddGiftsUnlocked(ctx, "player1", "testnet", "#testchan", 4); err != nil {
+			t.Fatalf("AddGiftsUnlocked: %v", err)
+		}
+		if err := repo.AddGiftsUnlocked(ctx, "player1", "testnet", "#testchan", 4); err != nil {
+			t.Fatalf("AddGiftsUnlocked: %v", err)
+		}
+		if got := mustGet(t, repo, "player1").GiftsUnlocked; got != 5 {
+			t.Errorf("expected gifts mask 5, got %d", got)
+		}
+
+		if err := repo.SetGiftsUnlocked(ctx, "player1", "testnet", "#testchan", 2); err != nil {
+			t.Fatalf("SetGiftsUnlocked: %v", err)
+		}
+		if got := mustGet(t, repo, "player1").GiftsUnlocked; got != 2 {
+			t.Errorf("expected gifts mask 2, got %d", got)
+		}
+	})
+
+	t.Run("SetLoveMeter", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan"})
+
+		if err := repo.SetLoveMeter(ctx, "PLAYER1", "testnet", "#testchan", 64); err != nil {
+			t.Fatalf("SetLoveMeter: %v", err)
+		}
+		if got := mustGet(t, repo, "player1").LoveMeter; got != 64 {
+			t.Errorf("expected love 64, got %d", got)
+		}
+	})
+
+	t.Run("AddLove_CreatesAndClamps", func(t *testing.T) {
+		repo := newRepo(t)
+
+		p, before, err := repo.AddLove(ctx, " NewPlayer ", "TestNet", "#TestChan", 3)
+		if err != nil {
+			t.Fatalf("AddLove: %v", err)
+		}
+		if p == nil || p.ID == "" || p.Name != "newplayer" || p.Channel != "#testchan" || p.LoveMeter != 3 || before != 0 {
+			t.Fatalf("expected a new normalized player going 0 -> 3, got %+v from %d", p, before)
+		}
+		if got := mustGet(t, repo, "newplayer").LoveMeter; got != 3 {
+			t.Errorf("expected stored love 3, got %d", got)
+		}
+
+		if p, before, _ = repo.AddLove(ctx, "newplayer", "testnet", "#testchan", -10); p.LoveMeter != 0 || before != 3 {
+			t.Errorf("expected love 3 clamped to 0, got %d -> %d", before, p.LoveMeter)
+		}
+		if p, before, _ = repo.AddLove(ctx, "newplayer", "testnet", "#testchan", 250); p.LoveMeter != 100 || before != 0 {
+			t.Errorf("expected love 0 clamped to 100, got %d -> %d", before, p.LoveMeter)
+		}
+		if p, before, _ = repo.AddLove(ctx, "newplayer", "testnet", "#testchan", 1); p.LoveMeter != 100 || before != 100 {
+			t.Errorf("expected love to stay at 100, got %d -> %d", before, p.LoveMeter)
+		}
+		if p, _, _ = repo.AddLove(ctx, "ghost", "testnet", "#testchan", -5); p.LoveMeter != 0 {
+			t.Errorf("expected a new player to start clamped at 0, got %d", p.LoveMeter)
+		}
+	})
+
+	t.Run("AddLove_KeepsOtherFields", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan", LoveMeter: 50, BondPoints: 7, HighestStreak: 4})
+		id := mustGet(t, repo, "player1").ID
+
+		p, before, err := repo.AddLove(ctx, "Player1", "testnet", "#testchan", -1)
+		if err != nil {
+			t.Fatalf("AddLove: %v", err)
+		}
+		if before != 50 || p.ID != id || p.LoveMeter != 49 || p.BondPoints != 7 || p.HighestStreak != 4 {
+			t.Errorf("expected only love to change, got %+v", p)
+		}
+		if all, _ := repo.GetAllPlayers(ctx, "testnet", "#testchan"); len(all) != 1 {
+			t.Errorf("expected 1 player, got %d", len(all))
+		}
+	})
+
+	t.Run("AddLove_ConcurrentDeltasAreNotLost", func(t *testing.T) {
+		repo := newRepo(t)
+
+		var wg sync.WaitGroup
+		errs := make(chan error, 60)
+		befores := make(chan int, 60)
+		for i := 0; i < 60; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p, before, err := repo.AddLove(ctx, "shared", "testnet", "#testchan", 1)
+				if err != nil {
+					errs <- err
+					return
+				}
+				if p.LoveMeter != before+1 {
+					errs <- fmt.Errorf("love went %d -> %d", before, p.LoveMeter)
+				}
+				befores <- before
+			}()
+		}
+		wg.Wait()
+		close(errs)
+		close(befores)
+		for err := range errs {
+			t.Fatalf("AddLove: %v", err)
+		}
+
+		if got := mustGet(t, repo, "shared").LoveMeter; got != 60 {
+			t.Errorf("expected love 60 after 60 concurrent +1s, got %d", got)
+		}
+		// every change started from a different love: 0, 1, ..., 59
+		seen := make(map[int]bool)
+		for before := range befores {
+			if seen[before] {
+				t.Errorf("two changes both started from %d", before)
+			}
+			seen[before] = true
+		}
+	})
+
+	t.Run("Streak", func(t *testing.T) {
+		repo := newRepo(t)
+		mustUpsert(t, repo, &cat_player.CatPlayer{Name: "player1", Network: "testnet", Channel: "#testchan"})
+
+		now := time.Now().UTC().Truncate(time.Second)
+		if err := repo.SetStreak(ctx, "player1", "testnet", "#testchan", 3, 8, now); err != nil {
+			t.Fatalf("SetStreak: %v", err)
+		}
+		p := mustGet(t, repo, "player1")
+		if p.CurrentStreak != 3 || p.HighestStreak != 8 {
+			t.Errorf("expected streak 3 / highest 8, got %d / %d", p.CurrentStreak, p.HighestStreak)
+		}
+		if p.LastStreakAt == nil || !sameInstant(*p.LastStreakAt, now) {
+			t.Errorf("expected LastStreakAt %v, got %v", now, p.LastStreakAt)
+		}
+
+		if err := repo.ResetStreak(ctx, "player1", "testnet", "#testchan"); err != nil {
+			t.Fatalf("ResetStreak: %v", err)
+		}
+		p = mustGet(t, repo, "player1")
+		if p.CurrentStreak != 0 || p.HighestStreak != 8 {
+			t.Errorf("expected streak 0 / highest 8 after reset, got %d / %d", p.CurrentStreak, p.HighestStreak)
+		}
+	})
+
+	t.Run("UpdatesOnMissingPlayerAreNoop", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.SetLoveMeter(ctx, "ghost", "testnet", "#testchan", 50); err != nil {
+			t.Fatalf("SetLoveMeter: %v", err)
+		}
+		p, err := repo.GetPlayerByName(ctx, "ghost", "testnet", "#testchan")
+		if err != nil || p != nil {
+			t.Errorf("expected no row to be created, got (%v, %v)", p, err)
+		}
+	})
+}
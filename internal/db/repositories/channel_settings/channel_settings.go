@@ -0,0 +1,91 @@
+package channel_settings
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"gorm.io/gorm/clause"
+)
+
+/*
+MODEL
+Per-channel overrides of the game settings, one row per key. Keys and values
+are validated by the settings service; the repository stores them as text.
+*/
+
+type ChannelSetting struct {
+	Network string `gorm:"column:network;type:varchar(100);primaryKey"`
+	Channel string `gorm:"column:channel;type:varchar(100);primaryKey"`
+	Key     string `gorm:"column:name;type:varchar(100);primaryKey"`
+
+	Value     string    `gorm:"column:value;not null"`
+	UpdatedBy string    `gorm:"column:updated_by;not null;default:''"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ChannelSetting) TableName() string { return "channel_settings" }
+
+/*
+REPOSITORY INTERFACE
+*/
+
+type ChannelSettingsRepository interface {
+	// GetSettings returns the channel's overrides by key (empty when none).
+	GetSettings(ctx context.Context, network, channel string) (map[string]string, error)
+	SetSetting(ctx context.Context, network, channel, key, value, updatedBy string) error
+	// DeleteSetting drops an override so the default applies again.
+	DeleteSetting(ctx context.Context, network, channel, key string) error
+}
+
+/*
+REPOSITORY IMPL
+*/
+
+type ChannelSettingsRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewChannelSettingsRepository(database *db.DB) ChannelSettingsRepository {
+	return &ChannelSettingsRepositoryImpl{db: database}
+}
+
+func norm(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+func (r *ChannelSettingsRepositoryImpl) GetSettings(ctx context.Context, network, channel string) (map[string]string, error) {
+	var rows []ChannelSetting
+	if err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", norm(network), norm(channel)).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]string, len(rows))
+	for _, row := range rows {
+		out[row.Key] = row.Value
+	}
+	return out, nil
+}
+
+func (r *ChannelSettingsRepositoryImpl) SetSetting(ctx context.Context, network, channel, key, value, updatedBy string) error {
+	row := ChannelSetting{
+		Network:   norm(network),
+		Channel:   norm(channel),
+		Key:       norm(key),
+		Value:     value,
+		UpdatedBy: updatedBy,
+	}
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "channel"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{"value", "updated_by", "updated_at"}),
+		}).
+		Create(&row).Error
+}
+
+func (r *ChannelSettingsRepositoryImpl) DeleteSetting(ctx context.Context, network, channel, key string) error {
+	return r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ? AND name = ?", norm(network), norm(channel), norm(key)).
+		Delete(&ChannelSetting{}).Error
+}
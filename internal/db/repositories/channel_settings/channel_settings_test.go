@@ -0,0 +1,63 @@
+package channel_settings_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_settings"
+)
+
+func TestChannelSettingsRepository(t *testing.T) {
+	repos := map[string]func(t *testing.T) channel_settings.ChannelSettingsRepository{
+		"memory": func(*testing.T) channel_settings.ChannelSettingsRepository {
+			return channel_settings.NewMemoryChannelSettingsRepository()
+		},
+		"sqlite": func(t *testing.T) channel_settings.ChannelSettingsRepository {
+			database := db.NewDatabase(config.DBConfig{Driver: db.DriverSQLite, Path: ":memory:"})
+			if err := migrations.MigrateDatabaseUp(database); err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+			t.Cleanup(func() { _ = database.Close() })
+			return channel_settings.NewChannelSettingsRepository(database)
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			ctx := context.Background()
+
+			got, err := repo.GetSettings(ctx, "net", "#cats")
+			if err != nil || len(got) != 0 {
+				t.Fatalf("fresh channel: got %v, %v", got, err)
+			}
+
+			must(t, repo.SetSetting(ctx, "Net", "#Cats", "accept_chance", "70", "alice"))
+			must(t, repo.SetSetting(ctx, "net", "#cats", "ACCEPT_CHANCE", "80", "bob"))
+			must(t, repo.SetSetting(ctx, "net", "#cats", "decay", "0", "bob"))
+			must(t, repo.SetSetting(ctx, "net", "#dogs", "decay", "9", "bob"))
+
+			got, _ = repo.GetSettings(ctx, "NET", "#CATS")
+			if len(got) != 2 || got["accept_chance"] != "80" || got["decay"] != "0" {
+				t.Errorf("expected upserted, normalized keys scoped to the channel, got %v", got)
+			}
+
+			must(t, repo.DeleteSetting(ctx, "net", "#cats", "Decay"))
+			must(t, repo.DeleteSetting(ctx, "net", "#cats", "missing"))
+			got, _ = repo.GetSettings(ctx, "net", "#cats")
+			if _, ok := got["decay"]; ok || len(got) != 1 {
+				t.Errorf("delete should drop only that key, got %v", got)
+			}
+		})
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
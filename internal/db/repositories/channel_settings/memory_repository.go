@@ -0,0 +1,52 @@
+package channel_settings
+
+import (
+	"context"
+	"sync"
+)
+
+/*
+IN-MEMORY REPOSITORY
+Used by unit tests and "serve --memory".
+*/
+
+type MemoryChannelSettingsRepository struct {
+	mu       sync.RWMutex
+	settings map[string]map[string]string // network|channel -> key -> value
+}
+
+func NewMemoryChannelSettingsRepository() ChannelSettingsRepository {
+	return &MemoryChannelSettingsRepository{settings: make(map[string]map[string]string)}
+}
+
+func (r *MemoryChannelSettingsRepository) GetSettings(_ context.Context, network, channel string) (map[string]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make(map[string]string)
+	for k, v := range r.settings[norm(network)+"|"+norm(channel)] {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (r *MemoryChannelSettingsRepository) SetSetting(_ context.Context, network, channel, key, value, _ string) error {
+	scope := norm(network) + "|" + norm(channel)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.settings[scope] == nil {
+		r.settings[scope] = make(map[string]string)
+	}
+	r.settings[scope][norm(key)] = value
+	return nil
+}
+
+func (r *MemoryChannelSettingsRepository) DeleteSetting(_ context.Context, network, channel, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.settings[norm(network)+"|"+norm(channel)], norm(key))
+	return nil
+}
@@ -0,0 +1,168 @@
+package channel_state
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+/*
+MODEL
+Per-channel game state that used to live only in CatActions' memory:
+Purrito's presence window, the next respawn, and per-player catnip/slap state.
+*/
+
+type ChannelState struct {
+	Network string `gorm:"column:network;type:varchar(100);primaryKey"`
+	Channel string `gorm:"column:channel;type:varchar(100);primaryKey"`
+
+	PresentUntil *time.Time `gorm:"column:present_until"`
+	NextSpawnAt  *time.Time `gorm:"column:next_spawn_at"`
+
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (ChannelState) TableName() string { return "channel_state" }
+
+type PlayerState struct {
+	Network string `gorm:"column:network;type:varchar(100);primaryKey"`
+	Channel string `gorm:"column:channel;type:varchar(100);primaryKey"`
+	Name    string `gorm:"column:name;type:varchar(100);primaryKey"`
+
+	CatnipUsedAt *time.Time `gorm:"column:catnip_used_at"`
+	SlapWarned   bool       `gorm:"column:slap_warned;not null;default:false"`
+
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+func (PlayerState) TableName() string { return "channel_player_state" }
+
+/*
+REPOSITORY INTERFACE
+Every setter is a single upsert, so a write either lands completely or not at all.
+*/
+
+type ChannelStateRepository interface {
+	// GetChannelState returns (nil, nil) when the channel has never been saved.
+	GetChannelState(ctx context.Context, network, channel string) (*ChannelState, error)
+	// SetPresence stores both timers at once; a zero time is stored as NULL.
+	SetPresence(ctx context.Context, network, channel string, presentUntil, nextSpawnAt time.Time) error
+
+	GetPlayerStates(ctx context.Context, network, channel string) ([]*PlayerState, error)
+	SetCatnipUsedAt(ctx context.Context, name, network, channel string, t time.Time) error
+	SetSlapWarned(ctx context.Context, name, network, channel string, warned bool) error
+}
+
+/*
+REPOSITORY IMPL
+*/
+
+type ChannelStateRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewChannelStateRepository(database *db.DB) ChannelStateRepository {
+	return &ChannelStateRepositoryImpl{db: database}
+}
+
+/*
+NORMALIZATION
+*/
+
+func norm(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+func normScope(network, channel string) (string, string) {
+	return norm(network), norm(channel)
+}
+
+// timePtr maps the zero time to NULL and stores everything else in UTC.
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+/*
+CHANNEL
+*/
+
+func (r *ChannelStateRepositoryImpl) GetChannelState(ctx context.Context, network, channel string) (*ChannelState, error) {
+	network, channel = normScope(network, channel)
+
+	var s ChannelState
+	err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", network, channel).
+		First(&s).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *ChannelStateRepositoryImpl) SetPresence(ctx context.Context, network, channel string, presentUntil, nextSpawnAt time.Time) error {
+	network, channel = normScope(network, channel)
+
+	row := ChannelState{
+		Network:      network,
+		Channel:      channel,
+		PresentUntil: timePtr(presentUntil),
+		NextSpawnAt:  timePtr(nextSpawnAt),
+	}
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "channel"}},
+			DoUpdates: clause.AssignmentColumns([]string{"present_until", "next_spawn_at", "updated_at"}),
+		}).
+		Create(&row).Error
+}
+
+/*
+PLAYERS
+*/
+
+func (r *ChannelStateRepositoryImpl) GetPlayerStates(ctx context.Context, network, channel string) ([]*PlayerState, error) {
+	network, channel = normScope(network, channel)
+
+	var states []*PlayerState
+	if err := r.db.DB.WithContext(ctx).
+		Where("network = ? AND channel = ?", network, channel).
+		Order("name ASC").
+		Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *ChannelStateRepositoryImpl) SetCatnipUsedAt(ctx context.Context, name, network, channel string, t time.Time) error {
+	row := PlayerState{Name: norm(name), CatnipUsedAt: timePtr(t)}
+	row.Network, row.Channel = normScope(network, channel)
+
+	return r.upsertPlayer(ctx, &row, "catnip_used_at")
+}
+
+func (r *ChannelStateRepositoryImpl) SetSlapWarned(ctx context.Context, name, network, channel string, warned bool) error {
+	row := PlayerState{Name: norm(name), SlapWarned: warned}
+	row.Network, row.Channel = normScope(network, channel)
+
+	return r.upsertPlayer(ctx, &row, "slap_warned")
+}
+
+// upsertPlayer inserts row, or updates only column (and updated_at) when the player already has state.
+func (r *ChannelStateRepositoryImpl) upsertPlayer(ctx context.Context, row *PlayerState, column string) error {
+	return r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "network"}, {Name: "channel"}, {Name: "name"}},
+			DoUpdates: clause.AssignmentColumns([]string{column, "updated_at"}),
+		}).
+		Create(row).Error
+}
@@ -0,0 +1,32 @@
+package channel_state_test
+
+import (
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state/repotest"
+)
+
+func TestMemoryChannelStateRepository(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) channel_state.ChannelStateRepository {
+		return channel_state.NewMemoryChannelStateRepository()
+	})
+}
+
+func TestChannelStateRepository_SQLite(t *testing.T) {
+	repotest.RunConformance(t, func(t *testing.T) channel_state.ChannelStateRepository {
+		database := db.NewDatabase(config.DBConfig{Driver: db.DriverSQLite, Path: ":memory:"})
+		if err := migrations.MigrateDatabaseUp(database); err != nil {
+			t.Fatalf("migrate: %v", err)
+		}
+		t.Cleanup(func() {
+			if sqldb, err := database.DB.DB(); err == nil {
+				_ = sqldb.Close()
+			}
+		})
+		return channel_state.NewChannelStateRepository(database)
+	})
+}
@@ -0,0 +1,114 @@
+package channel_state
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+/*
+IN-MEMORY REPOSITORY
+Same normalization and upsert semantics as ChannelStateRepositoryImpl.
+Used by unit tests and "serve --memory".
+*/
+
+type MemoryChannelStateRepository struct {
+	mu       sync.RWMutex
+	channels map[string]*ChannelState // key: network|channel
+	players  map[string]*PlayerState  // key: network|channel|name
+}
+
+func NewMemoryChannelStateRepository() ChannelStateRepository {
+	return &MemoryChannelStateRepository{
+		channels: make(map[string]*ChannelState),
+		players:  make(map[string]*PlayerState),
+	}
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	return &c
+}
+
+func (r *MemoryChannelStateRepository) GetChannelState(_ context.Context, network, channel string) (*ChannelState, error) {
+	network, channel = normScope(network, channel)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.channels[network+"|"+channel]
+	if !ok {
+		return nil, nil
+	}
+	c := *s
+	c.PresentUntil = copyTime(s.PresentUntil)
+	c.NextSpawnAt = copyTime(s.NextSpawnAt)
+	return &c, nil
+}
+
+func (r *MemoryChannelStateRepository) SetPresence(_ context.Context, network, channel string, presentUntil, nextSpawnAt time.Time) error {
+	network, channel = normScope(network, channel)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.channels[network+"|"+channel] = &ChannelState{
+		Network:      network,
+		Channel:      channel,
+		PresentUntil: timePtr(presentUntil),
+		NextSpawnAt:  timePtr(nextSpawnAt),
+		UpdatedAt:    time.Now(),
+	}
+	return nil
+}
+
+func (r *MemoryChannelStateRepository) GetPlayerStates(_ context.Context, network, channel string) ([]*PlayerState, error) {
+	network, channel = normScope(network, channel)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var out []*PlayerState
+	for _, p := range r.players {
+		if p.Network != network || p.Channel != channel {
+			continue
+		}
+		c := *p
+		c.CatnipUsedAt = copyTime(p.CatnipUsedAt)
+		out = append(out, &c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func (r *MemoryChannelStateRepository) SetCatnipUsedAt(_ context.Context, name, network, channel string, t time.Time) error {
+	r.updatePlayer(name, network, channel, func(p *PlayerState) { p.CatnipUsedAt = timePtr(t) })
+	return nil
+}
+
+func (r *MemoryChannelStateRepository) SetSlapWarned(_ context.Context, name, network, channel string, warned bool) error {
+	r.updatePlayer(name, network, channel, func(p *PlayerState) { p.SlapWarned = warned })
+	return nil
+}
+
+// updatePlayer creates the player's state if needed and applies fn under the write lock.
+func (r *MemoryChannelStateRepository) updatePlayer(name, network, channel string, fn func(p *PlayerState)) {
+	name = norm(name)
+	network, channel = normScope(network, channel)
+	key := network + "|" + channel + "|" + name
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.players[key]
+	if !ok {
+		p = &PlayerState{Network: network, Channel: channel, Name: name}
+		r.players[key] = p
+	}
+	fn(p)
+	p.UpdatedAt = time.Now()
+}
@@ -0,0 +1,116 @@
+// Package repotest holds the shared conformance suite for
+// channel_state.ChannelStateRepository implementations.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state"
+)
+
+func sameInstant(a *time.Time, b time.Time) bool {
+	if a == nil {
+		return false
+	}
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+	return d < time.Second
+}
+
+// RunConformance runs the behaviour every channel_state.ChannelStateRepository
+// implementation must share. newRepo must return an empty repository.
+func RunConformance(t *testing.T, newRepo func(t *testing.T) channel_state.ChannelStateRepository) {
+	ctx := context.Background()
+
+	t.Run("GetChannelState_Missing", func(t *testing.T) {
+		repo := newRepo(t)
+		s, err := repo.GetChannelState(ctx, "testnet", "#testchan")
+		if err != nil || s != nil {
+			t.Fatalf("expected (nil, nil), got (%v, %v)", s, err)
+		}
+	})
+
+	t.Run("SetPresence_UpsertsAndNormalizes", func(t *testing.T) {
+		repo := newRepo(t)
+		until := time.Now().Add(30 * time.Minute)
+
+		if err := repo.SetPresence(ctx, "TestNet", "#TestChan", until, time.Time{}); err != nil {
+			t.Fatalf("SetPresence: %v", err)
+		}
+		s, err := repo.GetChannelState(ctx, "testnet", "#testchan")
+		if err != nil || s == nil {
+			t.Fatalf("GetChannelState: (%v, %v)", s, err)
+		}
+		if !sameInstant(s.PresentUntil, until) {
+			t.Errorf("present_until = %v, want %v", s.PresentUntil, until)
+		}
+		if s.NextSpawnAt != nil {
+			t.Errorf("zero next_spawn_at should be stored as NULL, got %v", s.NextSpawnAt)
+		}
+
+		next := time.Now().Add(2 * time.Hour)
+		if err := repo.SetPresence(ctx, "testnet", "#testchan", time.Time{}, next); err != nil {
+			t.Fatalf("SetPresence: %v", err)
+		}
+		s, _ = repo.GetChannelState(ctx, "testnet", "#testchan")
+		if s.PresentUntil != nil || !sameInstant(s.NextSpawnAt, next) {
+			t.Errorf("expected presence replaced, got %v / %v", s.PresentUntil, s.NextSpawnAt)
+		}
+	})
+
+	t.Run("PlayerState_SettersKeepOtherColumns", func(t *testing.T) {
+		repo := newRepo(t)
+		used := time.Now().Add(-time.Hour)
+
+		if err := repo.SetSlapWarned(ctx, " Alice ", "testnet", "#testchan", true); err != nil {
+			t.Fatalf("SetSlapWarned: %v", err)
+		}
+		if err := repo.SetCatnipUsedAt(ctx, "alice", "testnet", "#testchan", used); err != nil {
+			t.Fatalf("SetCatnipUsedAt: %v", err)
+		}
+		if err := repo.SetCatnipUsedAt(ctx, "bob", "testnet", "#testchan", used); err != nil {
+			t.Fatalf("SetCatnipUsedAt: %v", err)
+		}
+
+		states, err := repo.GetPlayerStates(ctx, "TESTNET", "#testchan")
+		if err != nil {
+			t.Fatalf("GetPlayerStates: %v", err)
+		}
+		if len(states) != 2 || states[0].Name != "alice" || states[1].Name != "bob" {
+			t.Fatalf("expected alice and bob, got %+v", states)
+		}
+		if !states[0].SlapWarned || !sameInstant(states[0].CatnipUsedAt, used) {
+			t.Errorf("alice should keep both fields, got %+v", states[0])
+		}
+		if states[1].SlapWarned {
+			t.Errorf("bob was never warned, got %+v", states[1])
+		}
+
+		if err := repo.SetSlapWarned(ctx, "alice", "testnet", "#testchan", false); err != nil {
+			t.Fatalf("SetSlapWarned: %v", err)
+		}
+		states, _ = repo.GetPlayerStates(ctx, "testnet", "#testchan")
+		if states[0].SlapWarned || !sameInstant(states[0].CatnipUsedAt, used) {
+			t.Errorf("clearing the warning should keep catnip, got %+v", states[0])
+		}
+	})
+
+	t.Run("GetPlayerStates_ScopedByNetworkAndChannel", func(t *testing.T) {
+		repo := newRepo(t)
+		_ = repo.SetSlapWarned(ctx, "a", "testnet", "#testchan", true)
+		_ = repo.SetSlapWarned(ctx, "b", "testnet", "#other", true)
+		_ = repo.SetSlapWarned(ctx, "c", "othernet", "#testchan", true)
+
+		states, err := repo.GetPlayerStates(ctx, "testnet", "#testchan")
+		if err != nil {
+			t.Fatalf("GetPlayerStates: %v", err)
+		}
+		if len(states) != 1 || states[0].Name != "a" {
+			t.Errorf("expected only a in scope, got %+v", states)
+		}
+	})
+}
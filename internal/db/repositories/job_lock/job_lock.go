@@ -0,0 +1,111 @@
+package job_lock
+
+import (
+	"context"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"gorm.io/gorm/clause"
+)
+
+/*
+MODEL
+A lease on a named job. Replicas sharing a database take the lease before
+running the job, so it only runs once; a crashed holder loses it when
+LockedUntil passes. LastDue is the last due time that completed: a replica
+whose timer fires a moment late finds it done and skips it.
+*/
+
+type JobLock struct {
+	Name        string     `gorm:"column:name;type:varchar(200);primaryKey"`
+	Owner       string     `gorm:"column:owner;not null"`
+	LockedUntil time.Time  `gorm:"column:locked_until;not null"`
+	LastDue     *time.Time `gorm:"column:last_due"`
+}
+
+func (JobLock) TableName() string { return "job_locks" }
+
+/*
+REPOSITORY INTERFACE
+*/
+
+type JobLockRepository interface {
+	// TryLock takes (or renews) the lease on name for ttl to run due. It
+	// reports false while another owner holds an unexpired lease, or when due
+	// (or a later due time) already completed.
+	TryLock(ctx context.Context, name, owner string, due time.Time, ttl time.Duration) (bool, error)
+	// Complete records due as done and releases the lease, if owner still
+	// holds it.
+	Complete(ctx context.Context, name, owner string, due time.Time) error
+	// Unlock releases the lease without completing anything (the run
+	// failed, another replica may retry it), if owner still holds it.
+	Unlock(ctx context.Context, name, owner string) error
+	// LastDue is the last due time of name that completed (nil if none has).
+	LastDue(ctx context.Context, name string) (*time.Time, error)
+}
+
+/*
+REPOSITORY IMPL
+*/
+
+type JobLockRepositoryImpl struct {
+	db *db.DB
+}
+
+func NewJobLockRepository(database *db.DB) JobLockRepository {
+	return &JobLockRepositoryImpl{db: database}
+}
+
+func (r *JobLockRepositoryImpl) TryLock(ctx context.Context, name, owner string, due time.Time, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+	until := now.Add(ttl)
+
+	// first run of the job: whoever inserts the row holds it
+	res := r.db.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&JobLock{Name: name, Owner: owner, LockedUntil: until})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// otherwise take it over once it expired (or renew our own), unless due
+	// is already done
+	res = r.db.DB.WithContext(ctx).
+		Model(&JobLock{}).
+		Where("name = ? AND (locked_until < ? OR owner = ?)", name, now, owner).
+		Where("last_due IS NULL OR last_due < ?", due.UTC()).
+		Updates(map[string]any{"owner": owner, "locked_until": until})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+func (r *JobLockRepositoryImpl) Complete(ctx context.Context, name, owner string, due time.Time) error {
+	due = due.UTC()
+	return r.db.DB.WithContext(ctx).
+		Model(&JobLock{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Updates(map[string]any{"last_due": &due, "locked_until": time.Now().UTC()}).Error
+}
+
+func (r *JobLockRepositoryImpl) Unlock(ctx context.Context, name, owner string) error {
+	return r.db.DB.WithContext(ctx).
+		Model(&JobLock{}).
+		Where("name = ? AND owner = ?", name, owner).
+		Update("locked_until", time.Now().UTC()).Error
+}
+
+func (r *JobLockRepositoryImpl) LastDue(ctx context.Context, name string) (*time.Time, error) {
+	var locks []JobLock
+	if err := r.db.DB.WithContext(ctx).Where("name = ?", name).Limit(1).Find(&locks).Error; err != nil {
+		return nil, err
+	}
+	if len(locks) == 0 {
+		return nil, nil
+	}
+	return locks[0].LastDue, nil
+}
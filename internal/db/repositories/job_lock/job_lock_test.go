@@ -0,0 +1,109 @@
+package job_lock_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/config"
+	migrations "github.com/MyelinBots/catbot-go/db"
+	"github.com/MyelinBots/catbot-go/internal/db"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/job_lock"
+)
+
+func TestJobLockRepository(t *testing.T) {
+	repos := map[string]func(t *testing.T) job_lock.JobLockRepository{
+		"memory": func(*testing.T) job_lock.JobLockRepository {
+			return job_lock.NewMemoryJobLockRepository()
+		},
+		"sqlite": func(t *testing.T) job_lock.JobLockRepository {
+			database := db.NewDatabase(config.DBConfig{Driver: db.DriverSQLite, Path: ":memory:"})
+			if err := migrations.MigrateDatabaseUp(database); err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+			t.Cleanup(func() { _ = database.Close() })
+			return job_lock.NewJobLockRepository(database)
+		},
+	}
+
+	for name, newRepo := range repos {
+		t.Run(name, func(t *testing.T) {
+			repo := newRepo(t)
+			ctx := context.Background()
+
+			day1 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+			day2 := day1.Add(24 * time.Hour)
+
+			lockDue := func(job, owner string, due time.Time, ttl time.Duration) bool {
+				t.Helper()
+				ok, err := repo.TryLock(ctx, job, owner, due, ttl)
+				if err != nil {
+					t.Fatalf("TryLock: %v", err)
+				}
+				return ok
+			}
+			lock := func(job, owner string, ttl time.Duration) bool {
+				t.Helper()
+				return lockDue(job, owner, day1, ttl)
+			}
+
+			if !lock("decay", "a", time.Minute) {
+				t.Fatal("free lock should be taken")
+			}
+			if lock("decay", "b", time.Minute) {
+				t.Error("b must not take a's lease")
+			}
+			if !lock("decay", "a", time.Minute) {
+				t.Error("a should renew its own lease")
+			}
+			if !lock("other", "b", time.Minute) {
+				t.Error("locks are per job")
+			}
+
+			if err := repo.Unlock(ctx, "decay", "b"); err != nil {
+				t.Fatal(err)
+			}
+			if lock("decay", "b", time.Minute) {
+				t.Error("only the owner can unlock")
+			}
+			if err := repo.Unlock(ctx, "decay", "a"); err != nil {
+				t.Fatal(err)
+			}
+			if !lock("decay", "b", time.Minute) {
+				t.Error("released (failed) lock should be free to retry")
+			}
+
+			if last, err := repo.LastDue(ctx, "decay"); err != nil || last != nil {
+				t.Errorf("nothing completed yet, got %v, %v", last, err)
+			}
+
+			// a completed due time is not run again, by anyone
+			if err := repo.Complete(ctx, "decay", "b", day1); err != nil {
+				t.Fatal(err)
+			}
+			if last, err := repo.LastDue(ctx, "decay"); err != nil || last == nil || !last.Equal(day1) {
+				t.Errorf("LastDue = %v, %v, want %v", last, err, day1)
+			}
+			if last, err := repo.LastDue(ctx, "unknown"); err != nil || last != nil {
+				t.Errorf("unknown job: %v, %v", last, err)
+			}
+			if lock("decay", "a", time.Minute) || lock("decay", "b", time.Minute) {
+				t.Error("a completed due time must be skipped")
+			}
+			if lockDue("decay", "a", day1.Add(-24*time.Hour), time.Minute) {
+				t.Error("an older due time must be skipped")
+			}
+			if !lockDue("decay", "a", day2, time.Minute) {
+				t.Error("the next due time should be taken")
+			}
+
+			// an expired lease is taken over
+			if !lock("crashed", "a", -time.Second) {
+				t.Fatal("lock")
+			}
+			if !lock("crashed", "b", time.Minute) {
+				t.Error("expired lease should be taken over")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package job_lock
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+/*
+IN-MEMORY REPOSITORY
+Used by unit tests and "serve --memory" (a single process, so the lease
+only guards against overlapping runs).
+*/
+
+type MemoryJobLockRepository struct {
+	mu    sync.Mutex
+	locks map[string]JobLock
+}
+
+func NewMemoryJobLockRepository() JobLockRepository {
+	return &MemoryJobLockRepository{locks: make(map[string]JobLock)}
+}
+
+func (r *MemoryJobLockRepository) TryLock(_ context.Context, name, owner string, due time.Time, ttl time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	l, ok := r.locks[name]
+	if ok && l.Owner != owner && now.Before(l.LockedUntil) {
+		return false, nil
+	}
+	if ok && l.LastDue != nil && !l.LastDue.Before(due) {
+		return false, nil
+	}
+	r.locks[name] = JobLock{Name: name, Owner: owner, LockedUntil: now.Add(ttl), LastDue: l.LastDue}
+	return true, nil
+}
+
+func (r *MemoryJobLockRepository) Complete(_ context.Context, name, owner string, due time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.locks[name]; ok && l.Owner == owner {
+		l.LastDue, l.LockedUntil = &due, time.Now()
+		r.locks[name] = l
+	}
+	return nil
+}
+
+func (r *MemoryJobLockRepository) Unlock(_ context.Context, name, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.locks[name]; ok && l.Owner == owner {
+		l.LockedUntil = time.Now()
+		r.locks[name] = l
+	}
+	return nil
+}
+
+func (r *MemoryJobLockRepository) LastDue(_ context.Context, name string) (*time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.locks[name].LastDue, nil
+}
@@ -2,24 +2,282 @@ package healthcheck
 
 import (
 	"context"
-	"log"
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/MyelinBots/catbot-go/config"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
 )
 
-// Healthcheck that starts http server
-func StartHealthcheck(ctx context.Context, cfg config.AppConfig) {
+/*
+HEALTH
+The bot reports into a Health: the database it pings and, per network,
+whether the connection is registered and which channels are joined. The
+HTTP server turns that into probes for the orchestrator:
+  /livez   the process is alive (fails once a network stays down too long)
+  /readyz  the database answers, every network is registered and every
+           configured channel is joined
+  /status  everything above as JSON, with each channel's presence
+*/
+
+// pingTimeout bounds the database check of /readyz and /status.
+const pingTimeout = 2 * time.Second
+
+// ChannelStatus is one channel of a network.
+type ChannelStatus struct {
+	Name         string     `json:"name"`
+	Configured   bool       `json:"configured"` // listed in the config (not joined through !invite)
+	Joined       bool       `json:"joined"`
+	Playing      bool       `json:"playing"` // game loop running
+	Present      bool       `json:"present"` // Purrito is in the channel right now
+	PresentUntil *time.Time `json:"present_until,omitempty"`
+	NextSpawnAt  *time.Time `json:"next_spawn_at,omitempty"`
+}
+
+// NetworkStatus is one IRC network as seen by the bot.
+type NetworkStatus struct {
+	Name       string          `json:"name"`
+	Registered bool            `json:"registered"` // connected and welcomed by the server
+	Since      time.Time       `json:"since"`      // when Registered last changed
+	Channels   []ChannelStatus `json:"channels"`
+}
+
+// Status is the /status document.
+type Status struct {
+	App           string          `json:"app"`
+	Version       string          `json:"version"`
+	StartedAt     time.Time       `json:"started_at"`
+	Uptime        string          `json:"uptime"`
+	UptimeSeconds int64           `json:"uptime_seconds"`
+	Live          bool            `json:"live"`
+	Ready         bool            `json:"ready"`
+	Problems      []string        `json:"problems,omitempty"`
+	Database      string          `json:"database"` // ok | error | none
+	Networks      []NetworkStatus `json:"networks"`
+}
+
+type Health struct {
+	app     string
+	version string
+	started time.Time
+	now     func() time.Time
+
+	// a network unregistered for longer fails /livez (0 = never)
+	livenessTimeout time.Duration
+
+	mu       sync.RWMutex
+	database func(context.Context) error
+	networks map[string]func() NetworkStatus // key: network name
+
+	log *slog.Logger
+}
+
+// Option configures Health.
+type Option func(*Health)
+
+// WithLogger sets the logger for handler errors (default: slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(h *Health) { h.log = l }
+}
+
+func New(cfg config.AppConfig, opts ...Option) *Health {
+	h := &Health{
+		app:             cfg.APPName,
+		version:         cfg.Version,
+		started:         time.Now(),
+		now:             time.Now,
+		livenessTimeout: time.Duration(cfg.LivenessTimeoutSeconds) * time.Second,
+		networks:        make(map[string]func() NetworkStatus),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.log = logging.Or(h.log)
+	return h
+}
+
+// SetDatabase makes readiness depend on ping.
+func (h *Health) SetDatabase(ping func(context.Context) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.database = ping
+}
+
+// AddNetwork reports a network through status until RemoveNetwork.
+func (h *Health) AddNetwork(name string, status func() NetworkStatus) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.networks[name] = status
+}
+
+// RemoveNetwork stops reporting a network, e.g. once it stopped for good.
+func (h *Health) RemoveNetwork(name string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.networks, name)
+}
+
+// Networks snapshots every network, sorted by name.
+func (h *Health) Networks() []NetworkStatus {
+	h.mu.RLock()
+	reporters := make([]func() NetworkStatus, 0, len(h.networks))
+	for _, status := range h.networks {
+		reporters = append(reporters, status)
+	}
+	h.mu.RUnlock()
+
+	out := make([]NetworkStatus, 0, len(reporters))
+	for _, status := range reporters {
+		out = append(out, status())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// Live returns why the bot should be restarted, or nil.
+func (h *Health) Live() error {
+	return h.live(h.Networks())
+}
+
+func (h *Health) live(networks []NetworkStatus) error {
+	if h.livenessTimeout <= 0 {
+		return nil
+	}
+	for _, n := range networks {
+		if down := h.now().Sub(n.Since); !n.Registered && down > h.livenessTimeout {
+			return fmt.Errorf("%s: not registered for %s", n.Name, down.Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// Ready lists what keeps the bot from serving its channels (none = ready).
+func (h *Health) Ready(ctx context.Context) []string {
+	problems, _ := h.ready(ctx, h.Networks())
+	return problems
+}
+
+func (h *Health) ready(ctx context.Context, networks []NetworkStatus) (problems []string, database string) {
+	h.mu.RLock()
+	ping := h.database
+	h.mu.RUnlock()
+
+	database = "none"
+	if ping != nil {
+		ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+		defer cancel()
+		database = "ok"
+		if err := ping(ctx); err != nil {
+			database = "error"
+			problems = append(problems, fmt.Sprintf("database: %v", err))
+		}
+	}
+
+	for _, n := range networks {
+		if !n.Registered {
+			problems = append(problems, n.Name+": not registered")
+			continue
+		}
+		for _, ch := range n.Channels {
+			if ch.Configured && !ch.Joined {
+				problems = append(problems, fmt.Sprintf("%s: %s not joined", n.Name, ch.Name))
+			}
+		}
+	}
+	return problems, database
+}
+
+// Status is the whole picture for /status.
+func (h *Health) Status(ctx context.Context) Status {
+	networks := h.Networks()
+	problems, database := h.ready(ctx, networks)
+	uptime := h.now().Sub(h.started)
+
+	s := Status{
+		App:           h.app,
+		Version:       h.version,
+		StartedAt:     h.started,
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+		Ready:         len(problems) == 0,
+		Problems:      problems,
+		Database:      database,
+		Networks:      networks,
+	}
+	if err := h.live(networks); err != nil {
+		s.Problems = append(s.Problems, err.Error())
+	} else {
+		s.Live = true
+	}
+	return s
+}
+
+/* HTTP */
+
+// Handler serves the probes, /status and /metrics; any other path answers
+// "OK" like the original healthcheck.
+func (h *Health) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, _ *http.Request) {
+		if err := h.Live(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if problems := h.Ready(r.Context()); len(problems) > 0 {
+			http.Error(w, strings.Join(problems, "\n"), http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("OK"))
+	})
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(h.Status(r.Context())); err != nil {
+			h.log.Error("failed to encode status", "error", err)
+		}
+	})
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/", HealthCheckHandler())
+	return mux
+}
+
+// Healthcheck that starts http server, shut down when ctx is done.
+// handler is usually Health.Handler, possibly with more routes next to it.
+func StartHealthcheck(ctx context.Context, cfg config.AppConfig, handler http.Handler, log *slog.Logger) {
+	log = logging.Or(log)
+	srv := &http.Server{
+		Addr:    ":" + strconv.Itoa(cfg.Port),
+		Handler: handler,
+	}
+
 	// start http server
 	go func() {
-		port := strconv.Itoa(cfg.Port)
-		err := http.ListenAndServe(":"+port, HealthCheckHandler())
+		err := srv.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
-			log.Printf("healthcheck server error: %v", err)
+			log.Error("http server failed", "error", err)
 		}
 	}()
 
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Error("http server shutdown failed", "error", err)
+		}
+	}()
 }
 
 func HealthCheckHandler() http.HandlerFunc {
@@ -0,0 +1,139 @@
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/config"
+)
+
+func newTestHealth() (*Health, *time.Time) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	h := New(config.AppConfig{APPName: "purrito", Version: "1.2.3", LivenessTimeoutSeconds: 600})
+	h.started = now
+	h.now = func() time.Time { return now }
+	return h, &now
+}
+
+func get(t *testing.T, srv *httptest.Server, path string) (int, string) {
+	t.Helper()
+	res, err := http.Get(srv.URL + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	return res.StatusCode, string(body)
+}
+
+func TestHandler_Routes(t *testing.T) {
+	h, _ := newTestHealth()
+	srv := httptest.NewServer(h.Handler())
+	defer srv.Close()
+
+	for path, want := range map[string]string{
+		"/":        "OK",
+		"/livez":   "OK",
+		"/readyz":  "OK",
+		"/metrics": "go_goroutines",
+		"/status":  `"version": "1.2.3"`,
+	} {
+		if code, body := get(t, srv, path); code != http.StatusOK || !strings.Contains(body, want) {
+			t.Errorf("%s: %d %q, want %q", path, code, body, want)
+		}
+	}
+}
+
+func TestReady(t *testing.T) {
+	h, now := newTestHealth()
+	var dbErr error
+	h.SetDatabase(func(context.Context) error { return dbErr })
+	network := NetworkStatus{Name: "libera", Since: *now}
+	h.AddNetwork("libera", func() NetworkStatus { return network })
+
+	if problems := h.Ready(context.Background()); len(problems) != 1 || problems[0] != "libera: not registered" {
+		t.Errorf("unregistered: %q", problems)
+	}
+
+	network.Registered = true
+	network.Channels = []ChannelStatus{
+		{Name: "#cats", Configured: true, Joined: true},
+		{Name: "#dogs", Configured: true},
+		{Name: "#invited"},
+	}
+	if problems := h.Ready(context.Background()); len(problems) != 1 || problems[0] != "libera: #dogs not joined" {
+		t.Errorf("configured channel missing: %q", problems)
+	}
+
+	network.Channels[1].Joined = true
+	dbErr = errors.New("connection refused")
+	if problems := h.Ready(context.Background()); len(problems) != 1 || !strings.Contains(problems[0], "connection refused") {
+		t.Errorf("database down: %q", problems)
+	}
+
+	dbErr = nil
+	if problems := h.Ready(context.Background()); len(problems) != 0 {
+		t.Errorf("should be ready, got %q", problems)
+	}
+}
+
+func TestLive_NetworkDownTooLong(t *testing.T) {
+	h, now := newTestHealth()
+	srv := httptest.NewServer(h.Handler())
+	defer srv.Close()
+	h.AddNetwork("libera", func() NetworkStatus {
+		return NetworkStatus{Name: "libera", Since: time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)}
+	})
+
+	*now = now.Add(10 * time.Minute)
+	if code, _ := get(t, srv, "/livez"); code != http.StatusOK {
+		t.Errorf("down for the whole timeout is still alive, got %d", code)
+	}
+	*now = now.Add(time.Second)
+	if code, body := get(t, srv, "/livez"); code != http.StatusServiceUnavailable || !strings.Contains(body, "libera") {
+		t.Errorf("down longer than the timeout: %d %q", code, body)
+	}
+
+	h.livenessTimeout = 0
+	if err := h.Live(); err != nil {
+		t.Errorf("a zero timeout never fails, got %v", err)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	h, now := newTestHealth()
+	until := now.Add(5 * time.Minute)
+	h.AddNetwork("libera", func() NetworkStatus {
+		return NetworkStatus{Name: "libera", Registered: true, Since: *now, Channels: []ChannelStatus{
+			{Name: "#cats", Configured: true, Joined: true, Playing: true, Present: true, PresentUntil: &until},
+		}}
+	})
+	*now = now.Add(90 * time.Minute)
+
+	var s Status
+	if err := json.Unmarshal(mustJSON(t, h.Status(context.Background())), &s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Live || !s.Ready || s.Database != "none" || s.Uptime != "1h30m0s" || s.UptimeSeconds != 5400 {
+		t.Errorf("unexpected status %+v", s)
+	}
+	if len(s.Networks) != 1 || len(s.Networks[0].Channels) != 1 || !s.Networks[0].Channels[0].Present {
+		t.Errorf("unexpected networks %+v", s.Networks)
+	}
+}
+
+func mustJSON(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
@@ -0,0 +1,144 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/config"
+)
+
+/*
+LOGGING
+One slog.Logger is built from config in StartBot and handed down: the bot
+adds the network, each channel's game the channel, and the command layer
+the nick and command for the duration of one command (see WithLogger).
+*/
+
+// Field names shared by every layer.
+const (
+	KeyNetwork = "network"
+	KeyChannel = "channel"
+	KeyNick    = "nick"
+	KeyCommand = "command"
+	KeyLatency = "latency"
+)
+
+// New builds a logger writing to w. Level is debug | info | warn | error,
+// Format text | json.
+func New(cfg config.LogConfig, w io.Writer) (*slog.Logger, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(cfg.Level))); err != nil {
+		return nil, fmt.Errorf("log level %q: want debug, info, warn or error", cfg.Level)
+	}
+	opts := &slog.HandlerOptions{Level: level}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Format)) {
+	case "", "text":
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	}
+	return nil, fmt.Errorf("log format %q: want text or json", cfg.Format)
+}
+
+// Discard drops everything, for tests and optional loggers.
+func Discard() *slog.Logger { return slog.New(slog.NewTextHandler(io.Discard, nil)) }
+
+// Or returns l, or slog.Default() when l is nil.
+func Or(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return slog.Default()
+	}
+	return l
+}
+
+/* PER-COMMAND CONTEXT */
+
+type loggerKey struct{}
+
+// WithLogger attaches l to ctx, so code called for one command logs with
+// its fields.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// FromContext is the logger attached to ctx, or slog.Default().
+func FromContext(ctx context.Context) *slog.Logger {
+	return FromContextOr(ctx, slog.Default())
+}
+
+// FromContextOr is the logger attached to ctx, or fallback.
+func FromContextOr(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// Since is the latency attribute for work started at start.
+func Since(start time.Time) slog.Attr {
+	return slog.Duration(KeyLatency, time.Since(start))
+}
+
+/* REDACTION */
+
+const redacted = "[REDACTED]"
+
+// secretNames mark fields whose values must never be logged.
+var secretNames = []string{"password", "secret", "token", "apikey"}
+
+// Redact logs a config struct with its secrets blanked out: fields tagged
+// `secret:"true"` or named like a password, secret or token.
+func Redact(v any) slog.LogValuer { return redactor{v} }
+
+type redactor struct{ v any }
+
+func (r redactor) LogValue() slog.Value { return redactValue(reflect.ValueOf(r.v)) }
+
+func redactValue(v reflect.Value) slog.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return slog.AnyValue(nil)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return slog.AnyValue(v.Interface())
+	}
+
+	t := v.Type()
+	attrs := make([]slog.Attr, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if isSecret(f) {
+			if !fv.IsZero() {
+				attrs = append(attrs, slog.String(f.Name, redacted))
+			}
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: f.Name, Value: redactValue(fv)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func isSecret(f reflect.StructField) bool {
+	if f.Tag.Get("secret") == "true" {
+		return true
+	}
+	name := strings.ToLower(f.Name)
+	for _, s := range secretNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/MyelinBots/catbot-go/config"
+)
+
+func TestNew(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := New(config.LogConfig{Level: "warn", Format: "json"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Info("hidden")
+	l.Warn("shown", KeyChannel, "#cats")
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("expected one JSON line, got %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "shown" || line[KeyChannel] != "#cats" {
+		t.Errorf("got %v", line)
+	}
+
+	for _, cfg := range []config.LogConfig{{Level: "loud"}, {Level: "info", Format: "xml"}} {
+		if _, err := New(cfg, &buf); err == nil {
+			t.Errorf("%+v should be rejected", cfg)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	l, _ := New(config.LogConfig{Level: "info", Format: "text"}, &buf)
+
+	cfg := config.Config{
+		IRCConfig: config.IRCConfig{Nick: "Purrito", NickservPassword: "hunter2", SaslPassword: "s3cret"},
+		DBConfig:  config.DBConfig{User: "postgres", Password: "mysecretpassword"},
+	}
+	l.Info("starting bot", "config", Redact(cfg))
+
+	out := buf.String()
+	for _, secret := range []string{"hunter2", "s3cret", "mysecretpassword"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%s leaked: %s", secret, out)
+		}
+	}
+	for _, want := range []string{"config.IRCConfig.Nick=Purrito", "config.DBConfig.Password=[REDACTED]", "config.DBConfig.User=postgres"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %s in %s", want, out)
+		}
+	}
+	if strings.Contains(out, "IRCConfig.Password=") {
+		t.Error("empty secrets are left out, not shown as redacted")
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	l := Discard()
+	if FromContext(WithLogger(context.Background(), l)) != l {
+		t.Error("expected the attached logger")
+	}
+	if FromContextOr(context.Background(), l) != l {
+		t.Error("expected the fallback")
+	}
+}
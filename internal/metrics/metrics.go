@@ -0,0 +1,176 @@
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+/*
+METRICS
+Everything Purrito exports to Prometheus lives here, registered once in
+Registry and served on the healthcheck server's /metrics. The game code
+only touches the collectors below; label values are always the raw
+network/channel/command names.
+*/
+
+const namespace = "catbot"
+
+// Registry holds every catbot collector plus the Go runtime and process ones.
+var Registry = prometheus.NewRegistry()
+
+// Outcomes of a command (see commands.Outcome).
+const (
+	OutcomeOK          = "ok"
+	OutcomeUsage       = "usage"
+	OutcomeDenied      = "denied"
+	OutcomeRateLimited = "rate_limited"
+	OutcomeUnavailable = "unavailable"
+	OutcomeError       = "error"
+)
+
+// Results of an interaction with Purrito.
+const (
+	ResultAccepted = "accepted"
+	ResultRejected = "rejected"
+)
+
+// Reasons Purrito leaves a channel.
+const (
+	LeaveTimeout     = "timeout"     // nobody interacted during the spawn window
+	LeaveInteraction = "interaction" // one interaction per spawn
+)
+
+var (
+	// CommandsHandled counts dispatched commands by name and outcome.
+	CommandsHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "commands_handled_total",
+		Help:      "Commands handled, by command and outcome.",
+	}, []string{"command", "outcome"})
+
+	// Interactions counts pet/love/feed/laser/catnip by whether Purrito accepted.
+	Interactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "interactions_total",
+		Help:      "Interactions with Purrito, by action and result (accepted or rejected).",
+	}, []string{"network", "channel", "action", "result"})
+
+	// Spawns counts Purrito appearing in a channel.
+	Spawns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cat_spawns_total",
+		Help:      "Times Purrito appeared in a channel.",
+	}, []string{"network", "channel"})
+
+	// Leaves counts Purrito leaving a channel, by reason.
+	Leaves = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cat_leaves_total",
+		Help:      "Times Purrito left a channel, by reason (timeout or interaction).",
+	}, []string{"network", "channel", "reason"})
+
+	// LoveMeter is the player's love meter right after each interaction.
+	LoveMeter = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "love_meter",
+		Help:      "Love meter (0-100) of the player right after an interaction.",
+		Buckets:   prometheus.LinearBuckets(10, 10, 10), // 10, 20 ... 100
+	}, []string{"network", "channel"})
+
+	// BondPointsAwarded sums the BondPoints given to Forever Humans.
+	BondPointsAwarded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "bond_points_awarded_total",
+		Help:      "BondPoints awarded.",
+	}, []string{"network", "channel"})
+
+	// DBQueryDuration is the latency of every SQL statement by operation.
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency, by operation (create, query, update, delete, row, raw).",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "status"})
+
+	// IRCReconnects counts reconnects after a dropped or failed connection.
+	IRCReconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "irc_reconnects_total",
+		Help:      "Reconnects to the IRC network after the connection dropped or failed.",
+	}, []string{"network"})
+
+	// Outbound reports every registered outbound queue when scraped.
+	Outbound = newQueueCollector()
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		CommandsHandled, Interactions, Spawns, Leaves, LoveMeter,
+		BondPointsAwarded, DBQueryDuration, IRCReconnects, Outbound,
+	)
+}
+
+// Handler serves Registry in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+/* OUTBOUND QUEUES */
+
+// QueueStats is what the collector reads from a queue on every scrape.
+type QueueStats struct {
+	Depth   map[string]int // queued lines per priority
+	Sent    uint64
+	Dropped uint64
+}
+
+var (
+	queueDepthDesc = prometheus.NewDesc(namespace+"_outbound_queue_depth",
+		"Lines waiting in the outbound queue, by priority.", []string{"network", "priority"}, nil)
+	queueSentDesc = prometheus.NewDesc(namespace+"_outbound_sent_total",
+		"Lines sent to the IRC server.", []string{"network"}, nil)
+	queueDroppedDesc = prometheus.NewDesc(namespace+"_outbound_dropped_total",
+		"Lines dropped because the outbound queue was full.", []string{"network"}, nil)
+)
+
+type queueCollector struct {
+	mu     sync.Mutex
+	queues map[string]func() QueueStats // key: network
+}
+
+func newQueueCollector() *queueCollector {
+	return &queueCollector{queues: make(map[string]func() QueueStats)}
+}
+
+// Watch reports network's queue through stats until the process exits.
+// Watching the same network again replaces it.
+func (c *queueCollector) Watch(network string, stats func() QueueStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queues[network] = stats
+}
+
+func (c *queueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- queueSentDesc
+	ch <- queueDroppedDesc
+}
+
+func (c *queueCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for network, stats := range c.queues {
+		s := stats()
+		for priority, n := range s.Depth {
+			ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(n), network, priority)
+		}
+		ch <- prometheus.MustNewConstMetric(queueSentDesc, prometheus.CounterValue, float64(s.Sent), network)
+		ch <- prometheus.MustNewConstMetric(queueDroppedDesc, prometheus.CounterValue, float64(s.Dropped), network)
+	}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestOutboundCollector(t *testing.T) {
+	c := newQueueCollector()
+	c.Watch("testnet", func() QueueStats {
+		return QueueStats{Depth: map[string]int{"high": 2, "low": 0}, Sent: 7, Dropped: 1}
+	})
+
+	want := `
+# HELP catbot_outbound_queue_depth Lines waiting in the outbound queue, by priority.
+# TYPE catbot_outbound_queue_depth gauge
+catbot_outbound_queue_depth{network="testnet",priority="high"} 2
+catbot_outbound_queue_depth{network="testnet",priority="low"} 0
+# HELP catbot_outbound_sent_total Lines sent to the IRC server.
+# TYPE catbot_outbound_sent_total counter
+catbot_outbound_sent_total{network="testnet"} 7
+# HELP catbot_outbound_dropped_total Lines dropped because the outbound queue was full.
+# TYPE catbot_outbound_dropped_total counter
+catbot_outbound_dropped_total{network="testnet"} 1
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRegistryLints(t *testing.T) {
+	problems, err := testutil.GatherAndLint(Registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Errorf("%s: %s", p.Metric, p.Text)
+	}
+}
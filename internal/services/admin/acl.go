@@ -0,0 +1,194 @@
+package admin
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+/*
+ACL
+Who may run admin commands, by hostmask (nick!user@host, * and ? wildcards)
+or by services account (IRCv3 account-tag). Also holds the bot-wide ban list:
+banned hostmasks are ignored by every command.
+*/
+
+type Config struct {
+	Masks     []string // admin hostmasks, e.g. "*!*@staff.example.org"
+	Accounts  []string // admin services accounts
+	Bans      []string // banned hostmasks
+	RequireOp bool     // admins must also be op in the channel they use
+}
+
+// ErrNoAdmins means a Config lists neither admin hostmasks nor accounts.
+var ErrNoAdmins = errors.New("no admin hostmasks or accounts configured")
+
+// HasAdmins reports whether anyone at all would be an admin.
+func (c Config) HasAdmins() bool {
+	return len(normList(c.Masks)) > 0 || len(normList(c.Accounts)) > 0
+}
+
+type ACL struct {
+	mu        sync.RWMutex
+	masks     []string
+	accounts  map[string]bool
+	bans      []string
+	requireOp bool
+}
+
+func NewACL(cfg Config) *ACL {
+	a := &ACL{}
+	a.Reload(cfg)
+	return a
+}
+
+// Reload replaces the admins and RequireOp. Bans from cfg are added;
+// bans made at runtime with Ban are kept.
+func (a *ACL) Reload(cfg Config) {
+	masks := normList(cfg.Masks)
+	accounts := make(map[string]bool)
+	for _, acc := range normList(cfg.Accounts) {
+		accounts[acc] = true
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.masks = masks
+	a.accounts = accounts
+	a.requireOp = cfg.RequireOp
+	for _, b := range normList(cfg.Bans) {
+		a.addBanLocked(b)
+	}
+}
+
+// IsAdmin reports whether hostmask (nick!user@host) or account is on the ACL.
+// An empty or "*" account never matches.
+func (a *ACL) IsAdmin(hostmask, account string) bool {
+	hostmask, account = norm(hostmask), norm(account)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if account != "" && account != "*" && a.accounts[account] {
+		return true
+	}
+	for _, m := range a.masks {
+		if MatchMask(m, hostmask) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ACL) RequireOp() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.requireOp
+}
+
+// Ban adds mask to the ban list. It returns false when it was already banned.
+func (a *ACL) Ban(mask string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.addBanLocked(norm(mask))
+}
+
+func (a *ACL) addBanLocked(mask string) bool {
+	for _, b := range a.bans {
+		if b == mask {
+			return false
+		}
+	}
+	a.bans = append(a.bans, mask)
+	return true
+}
+
+// Unban removes mask from the ban list. It returns false when it wasn't banned.
+func (a *ACL) Unban(mask string) bool {
+	mask = norm(mask)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, b := range a.bans {
+		if b == mask {
+			a.bans = append(a.bans[:i], a.bans[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Banned reports whether hostmask matches a ban. Admins are never banned.
+func (a *ACL) Banned(hostmask, account string) bool {
+	if a.IsAdmin(hostmask, account) {
+		return false
+	}
+	hostmask = norm(hostmask)
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, b := range a.bans {
+		if MatchMask(b, hostmask) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ACL) Bans() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]string(nil), a.bans...)
+}
+
+// MatchMask matches an IRC glob (* any run, ? one character), case-insensitively.
+func MatchMask(mask, s string) bool {
+	mask, s = norm(mask), norm(s)
+
+	// iterative glob with single-star backtracking
+	m, i := 0, 0
+	star, mark := -1, 0
+	for i < len(s) {
+		switch {
+		case m < len(mask) && (mask[m] == '?' || mask[m] == s[i]):
+			m++
+			i++
+		case m < len(mask) && mask[m] == '*':
+			star, mark = m, i
+			m++
+		case star >= 0:
+			m = star + 1
+			mark++
+			i = mark
+		default:
+			return false
+		}
+	}
+	for m < len(mask) && mask[m] == '*' {
+		m++
+	}
+	return m == len(mask)
+}
+
+// HostmaskFor turns a bare nick into nick!*@* so "!ban alice" works.
+func HostmaskFor(target string) string {
+	if strings.ContainsAny(target, "!@") {
+		return target
+	}
+	return target + "!*@*"
+}
+
+func norm(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
+
+func normList(in []string) []string {
+	var out []string
+	for _, s := range in {
+		if s = norm(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,106 @@
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchMask(t *testing.T) {
+	tests := []struct {
+		mask, s string
+		want    bool
+	}{
+		{"*!*@staff.example.org", "Alice!alice@STAFF.example.org", true},
+		{"alice!*@*", "alice!~a@1.2.3.4", true},
+		{"alice!*@*", "alice_!~a@1.2.3.4", false},
+		{"*!?bob@*", "x!~bob@host", true},
+		{"*!?bob@*", "x!bob@host", false},
+		{"*", "anything", true},
+		{"*.example.org", "a!b@c.example.org", true},
+		{"*.example.org", "a!b@example.org.evil", false},
+	}
+	for _, tt := range tests {
+		if got := MatchMask(tt.mask, tt.s); got != tt.want {
+			t.Errorf("MatchMask(%q, %q) = %v, want %v", tt.mask, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestACL_AdminsByMaskAndAccount(t *testing.T) {
+	acl := NewACL(Config{Masks: []string{" *!*@staff.example.org "}, Accounts: []string{"Boss"}})
+
+	if !acl.IsAdmin("alice!a@staff.example.org", "") {
+		t.Error("hostmask should match")
+	}
+	if !acl.IsAdmin("whoever!x@elsewhere", "boss") {
+		t.Error("account should match")
+	}
+	if acl.IsAdmin("eve!e@elsewhere", "*") || acl.IsAdmin("eve!e@elsewhere", "") {
+		t.Error("unknown sender should not be admin")
+	}
+
+	acl.Reload(Config{Accounts: []string{"other"}, RequireOp: true})
+	if acl.IsAdmin("alice!a@staff.example.org", "boss") {
+		t.Error("reload should replace the admins")
+	}
+	if !acl.RequireOp() {
+		t.Error("reload should set RequireOp")
+	}
+}
+
+func TestConfig_HasAdmins(t *testing.T) {
+	if (Config{Masks: []string{" "}, Accounts: []string{""}, Bans: []string{"*!*@bad.host"}}).HasAdmins() {
+		t.Error("blank entries and bans are not admins")
+	}
+	if !(Config{Accounts: []string{"Boss"}}).HasAdmins() || !(Config{Masks: []string{"*!*@staff"}}).HasAdmins() {
+		t.Error("a mask or an account is enough")
+	}
+}
+
+func TestACL_Bans(t *testing.T) {
+	acl := NewACL(Config{Masks: []string{"admin!*@*"}, Bans: []string{"*!*@bad.host"}})
+
+	if !acl.Banned("spam!s@BAD.host", "") {
+		t.Error("config ban should apply")
+	}
+	if !acl.Ban(HostmaskFor("troll")) || acl.Ban("TROLL!*@*") {
+		t.Error("Ban should report whether the mask was new")
+	}
+	if !acl.Banned("troll!t@anywhere", "") {
+		t.Error("runtime ban should apply")
+	}
+	if acl.Banned("admin!a@bad.host", "") {
+		t.Error("admins are never banned")
+	}
+
+	acl.Reload(Config{})
+	if !acl.Banned("troll!t@anywhere", "") {
+		t.Error("reload should keep runtime bans")
+	}
+	if !acl.Unban("troll!*@*") || acl.Banned("troll!t@anywhere", "") {
+		t.Error("unban should lift the ban")
+	}
+	if acl.Unban("nobody!*@*") {
+		t.Error("unban of an unknown mask should report false")
+	}
+}
+
+func TestAuditor_Record(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewAuditor(&buf)
+	a.now = func() time.Time { return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC) }
+
+	a.Record(Entry{Who: "alice!a@host", Account: "alice", Channel: "#cats", Command: "setlove", Args: []string{"bob", "50"}, Result: "ok"})
+	a.Record(Entry{Who: "eve!e@host", Channel: "#cats", Command: "reset", Result: errors.New("boom").Error()})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if lines[0] != "2025-01-01T12:00:00Z AUDIT alice!a@host (alice) #cats !setlove bob 50 => ok" {
+		t.Errorf("got %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "#cats !reset => boom") {
+		t.Errorf("got %q", lines[1])
+	}
+}
@@ -0,0 +1,62 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+AUDIT LOG
+One line per admin command, allowed or not:
+2025-01-01T00:00:00Z nick!user@host #chan !setlove alice 50 => ok
+*/
+
+type Entry struct {
+	Who     string // nick!user@host
+	Account string
+	Channel string
+	Command string // canonical name, without prefix
+	Args    []string
+	Result  string // "ok", "denied" or the error
+}
+
+type Auditor struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+func NewAuditor(w io.Writer) *Auditor {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &Auditor{w: w, now: time.Now}
+}
+
+// OpenAuditor appends to the file at path, or writes to stdout when path is "".
+func OpenAuditor(path string) (*Auditor, error) {
+	if path == "" {
+		return NewAuditor(os.Stdout), nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return NewAuditor(f), nil
+}
+
+func (a *Auditor) Record(e Entry) {
+	who := e.Who
+	if e.Account != "" && e.Account != "*" {
+		who += " (" + e.Account + ")"
+	}
+	line := strings.TrimSpace(fmt.Sprintf("!%s %s", e.Command, strings.Join(e.Args, " ")))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fmt.Fprintf(a.w, "%s AUDIT %s %s %s => %s\n", a.now().UTC().Format(time.RFC3339), who, e.Channel, line, e.Result)
+}
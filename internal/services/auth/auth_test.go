@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluffle/goirc/state"
+)
+
+type mockConn struct {
+	nick  string
+	raw   []string
+	nicks []string
+}
+
+func (m *mockConn) Raw(line string)  { m.raw = append(m.raw, line) }
+func (m *mockConn) Nick(nick string) { m.nicks = append(m.nicks, nick) }
+func (m *mockConn) Me() *state.Nick  { return &state.Nick{Nick: m.nick} }
+func (m *mockConn) last() string     { return m.raw[len(m.raw)-1] }
+func (m *mockConn) sent(s string) bool {
+	for _, r := range m.raw {
+		if strings.Contains(r, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// setup returns an identifier whose timer is fired by hand.
+func setup(cfg Config, nick string) (*Identifier, *mockConn, *int, *func()) {
+	conn := &mockConn{nick: nick}
+	joins := 0
+	id := NewIdentifier(cfg, conn, func() { joins++ })
+
+	var fire func()
+	id.after = func(_ time.Duration, f func()) func() {
+		fire = f
+		return func() {}
+	}
+	return id, conn, &joins, &fire
+}
+
+func TestIdentifier_WaitsForConfirmation(t *testing.T) {
+	id, conn, joins, _ := setup(Config{Nick: "Purrito", Password: "secret"}, "Purrito")
+
+	id.Connected()
+	if conn.last() != "PRIVMSG NickServ :IDENTIFY secret" {
+		t.Fatalf("got %q", conn.last())
+	}
+	if *joins != 0 {
+		t.Fatal("should not join before services confirm")
+	}
+
+	id.Notice("somebody", "You are now identified")
+	if *joins != 0 {
+		t.Fatal("only services can confirm")
+	}
+	id.Notice("NickServ", "You are now identified for Purrito.")
+	if *joins != 1 || !id.Ready() {
+		t.Fatalf("expected one join after confirmation, got %d", *joins)
+	}
+	id.LoggedIn()
+	if *joins != 1 {
+		t.Error("a second confirmation must not join again")
+	}
+}
+
+func TestIdentifier_RetriesThenJoinsUnidentified(t *testing.T) {
+	id, conn, joins, fire := setup(Config{Password: "wrong", Command: "NS IDENTIFY %s", Retries: 1}, "Purrito")
+
+	id.Connected()
+	id.Notice("NickServ", "Invalid password for Purrito.")
+	if len(conn.raw) != 2 || conn.last() != "NS IDENTIFY wrong" {
+		t.Fatalf("expected a retry, got %q", conn.raw)
+	}
+	(*fire)() // no answer to the retry
+	if *joins != 1 {
+		t.Fatal("should give up and join after the retries")
+	}
+	if len(conn.raw) != 2 {
+		t.Errorf("no IDENTIFY after giving up, got %q", conn.raw)
+	}
+}
+
+func TestIdentifier_SASLSkipsNickServ(t *testing.T) {
+	id, conn, joins, _ := setup(Config{Nick: "Purrito", Password: "secret"}, "Purrito")
+
+	id.LoggedIn() // 903 during registration
+	id.Connected()
+	if *joins != 1 || len(conn.raw) != 0 {
+		t.Fatalf("SASL login should join right away, joins=%d raw=%q", *joins, conn.raw)
+	}
+
+	// a reconnect without SASL goes through NickServ again
+	id.Reset()
+	id.Connected()
+	if !conn.sent("IDENTIFY secret") {
+		t.Error("expected IDENTIFY after reset")
+	}
+}
+
+func TestIdentifier_RegainAndGhost(t *testing.T) {
+	id, conn, _, _ := setup(Config{Nick: "Purrito", Password: "secret"}, "Purrito_")
+	id.Connected()
+	id.LoggedIn() // 900 after IDENTIFY
+	if conn.last() != "PRIVMSG NickServ :REGAIN Purrito" {
+		t.Errorf("got %q", conn.last())
+	}
+
+	id, conn, _, _ = setup(Config{Nick: "Purrito", Password: "secret", Regain: RegainGhost}, "Purrito_")
+	id.Connected()
+	id.Notice("NickServ", "Password accepted - you are now recognized.")
+	if conn.last() != "PRIVMSG NickServ :GHOST Purrito" || len(conn.nicks) != 0 {
+		t.Fatalf("expected GHOST first, got %q %q", conn.raw, conn.nicks)
+	}
+	id.Notice("NickServ", "Purrito has been ghosted.")
+	if len(conn.nicks) != 1 || conn.nicks[0] != "Purrito" {
+		t.Errorf("expected NICK Purrito after the ghost, got %q", conn.nicks)
+	}
+}
+
+func TestIdentifier_NoPasswordJoinsImmediately(t *testing.T) {
+	id, conn, joins, _ := setup(Config{Nick: "Purrito"}, "Purrito_")
+	id.Connected()
+	if *joins != 1 || len(conn.raw) != 0 {
+		t.Errorf("joins=%d raw=%q", *joins, conn.raw)
+	}
+}
+
+func TestNewSASL(t *testing.T) {
+	if c, err := NewSASL("", "", ""); c != nil || err != nil {
+		t.Error("no mechanism means no SASL")
+	}
+	if _, err := NewSASL("PLAIN", "purrito", ""); err == nil {
+		t.Error("plain without a password should fail")
+	}
+	c, err := NewSASL("plain", "purrito", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mech, ir, _ := c.Start()
+	if mech != "PLAIN" || string(ir) != "\x00purrito\x00secret" {
+		t.Errorf("got %s %q", mech, ir)
+	}
+	if c, _ := NewSASL("external", "", ""); c == nil {
+		t.Error("external needs no credentials")
+	}
+	if _, err := NewSASL("scram", "a", "b"); err == nil {
+		t.Error("unknown mechanism should fail")
+	}
+}
@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/fluffle/goirc/state"
+)
+
+/*
+NICKSERV
+Identification for networks (or setups) without SASL. After registration the
+IDENTIFY command is sent and channels are only joined once services confirm it
+(a NickServ notice or RPL_LOGGEDIN). No confirmation within Timeout, or a
+rejected password, is retried up to Retries times; after that the bot joins
+unidentified rather than sitting outside its channels.
+Once identified, a nick taken by a ghost session is taken back with REGAIN or
+GHOST + NICK.
+*/
+
+// Conn is the part of *irc.Conn the identifier needs.
+type Conn interface {
+	Raw(line string)
+	Nick(nick string)
+	Me() *state.Nick
+}
+
+type Config struct {
+	Nick     string        // nick to hold, regained when the server gave us another one
+	Password string        // "" skips identification
+	Command  string        // raw IDENTIFY line, %s is replaced by the password
+	Services string        // services nick, default NickServ
+	Regain   string        // "regain" (default), "ghost" or "none"
+	Timeout  time.Duration // wait this long for the confirmation
+	Retries  int           // extra IDENTIFY attempts after a timeout or rejection
+	Logger   *slog.Logger  // default slog.Default()
+}
+
+const (
+	RegainNone  = "none"
+	RegainGhost = "ghost"
+	RegainNick  = "regain"
+)
+
+type phase int
+
+const (
+	phaseIdle        phase = iota // not connected yet
+	phaseIdentifying              // IDENTIFY sent, waiting for services
+	phaseGhosting                 // GHOST sent, NICK follows the services reply
+	phaseReady                    // channels joined
+)
+
+var (
+	identifiedReplies = []string{"you are now identified", "password accepted", "you are now logged in", "you're now logged in"}
+	rejectedReplies   = []string{"invalid password", "incorrect password", "password incorrect", "authentication failed"}
+	unknownReplies    = []string{"is not registered", "isn't registered", "not a registered nickname"}
+)
+
+type Identifier struct {
+	mu      sync.Mutex
+	cfg     Config
+	conn    Conn
+	ready   func() // joins channels, once per connection
+	phase   phase
+	sasl    bool // logged in during registration
+	attempt int
+	gen     int // bumps on every IDENTIFY and reset, stale timers check it
+	stop    func()
+	after   func(time.Duration, func()) func()
+}
+
+// NewIdentifier calls ready once per connection, when it is safe to join channels.
+func NewIdentifier(cfg Config, conn Conn, ready func()) *Identifier {
+	if cfg.Services == "" {
+		cfg.Services = "NickServ"
+	}
+	if cfg.Command == "" {
+		cfg.Command = "PRIVMSG " + cfg.Services + " :IDENTIFY %s"
+	}
+	if cfg.Regain == "" {
+		cfg.Regain = RegainNick
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 15 * time.Second
+	}
+	cfg.Logger = logging.Or(cfg.Logger)
+	return &Identifier{
+		cfg:   cfg,
+		conn:  conn,
+		ready: ready,
+		after: func(d time.Duration, f func()) func() {
+			t := time.AfterFunc(d, f)
+			return func() { t.Stop() }
+		},
+	}
+}
+
+// Reset forgets the connection, call it on DISCONNECTED.
+func (id *Identifier) Reset() {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+
+	id.stopTimerLocked()
+	id.phase = phaseIdle
+	id.sasl = false
+	id.attempt = 0
+	id.gen++
+}
+
+// LoggedIn handles RPL_LOGGEDIN (900) and RPL_SASLSUCCESS (903). Before
+// CONNECTED it means SASL worked, afterwards it confirms IDENTIFY.
+func (id *Identifier) LoggedIn() {
+	id.mu.Lock()
+	join := false
+	switch id.phase {
+	case phaseIdle:
+		id.sasl = true
+	case phaseIdentifying:
+		join = id.finishLocked(true)
+	}
+	id.mu.Unlock()
+	id.join(join)
+}
+
+// Connected starts identification after registration (CONNECTED).
+func (id *Identifier) Connected() {
+	id.mu.Lock()
+	join := false
+	switch {
+	case id.phase != phaseIdle:
+	case id.sasl:
+		id.cfg.Logger.Info("identified via SASL")
+		join = id.finishLocked(true)
+	case id.cfg.Password == "":
+		join = id.finishLocked(false)
+	default:
+		id.identifyLocked()
+	}
+	id.mu.Unlock()
+	id.join(join)
+}
+
+// Notice handles a NOTICE; only those from services matter.
+func (id *Identifier) Notice(from, text string) {
+	if !strings.EqualFold(from, id.cfg.Services) {
+		return
+	}
+	msg := strings.ToLower(text)
+
+	id.mu.Lock()
+	join := false
+	switch id.phase {
+	case phaseIdentifying:
+		switch {
+		case containsAny(msg, identifiedReplies):
+			join = id.finishLocked(true)
+		case containsAny(msg, unknownReplies):
+			id.cfg.Logger.Warn("nick is not registered, joining unidentified", "services", id.cfg.Services)
+			join = id.finishLocked(false)
+		case containsAny(msg, rejectedReplies):
+			id.cfg.Logger.Warn("services rejected the password", "services", id.cfg.Services, "attempt", id.attempt)
+			join = id.retryLocked()
+		}
+	case phaseGhosting:
+		// whatever services said, the ghost is gone or never will be
+		id.phase = phaseReady
+		id.conn.Nick(id.cfg.Nick)
+	}
+	id.mu.Unlock()
+	id.join(join)
+}
+
+// Ready reports whether channels have been joined on this connection.
+func (id *Identifier) Ready() bool {
+	id.mu.Lock()
+	defer id.mu.Unlock()
+	return id.phase >= phaseGhosting
+}
+
+func (id *Identifier) join(ok bool) {
+	if ok && id.ready != nil {
+		id.ready()
+	}
+}
+
+// identifyLocked sends IDENTIFY and waits Timeout for the answer. Caller holds mu.
+func (id *Identifier) identifyLocked() {
+	id.phase = phaseIdentifying
+	id.attempt++
+	id.gen++
+	gen := id.gen
+
+	id.conn.Raw(fmt.Sprintf(id.cfg.Command, id.cfg.Password))
+	id.stopTimerLocked()
+	id.stop = id.after(id.cfg.Timeout, func() { id.timeout(gen) })
+}
+
+func (id *Identifier) timeout(gen int) {
+	id.mu.Lock()
+	join := false
+	if gen == id.gen && id.phase == phaseIdentifying {
+		id.cfg.Logger.Warn("no answer from services", "services", id.cfg.Services, "attempt", id.attempt)
+		join = id.retryLocked()
+	}
+	id.mu.Unlock()
+	id.join(join)
+}
+
+// retryLocked identifies again, or gives up and reports that channels should
+// be joined anyway. Caller holds mu.
+func (id *Identifier) retryLocked() bool {
+	if id.attempt <= id.cfg.Retries {
+		id.identifyLocked()
+		return false
+	}
+	id.cfg.Logger.Error("could not identify, joining unidentified", "services", id.cfg.Services)
+	return id.finishLocked(false)
+}
+
+// finishLocked regains the nick when identified and reports that channels
+// should be joined (ready is called by the caller, outside mu).
+func (id *Identifier) finishLocked(identified bool) bool {
+	id.stopTimerLocked()
+	id.gen++
+	id.phase = phaseReady
+
+	if identified {
+		id.regainLocked()
+	}
+	return true
+}
+
+// regainLocked takes the configured nick back from a ghost. Caller holds mu.
+func (id *Identifier) regainLocked() {
+	me := id.conn.Me()
+	if id.cfg.Nick == "" || me == nil || strings.EqualFold(me.Nick, id.cfg.Nick) {
+		return
+	}
+	switch id.cfg.Regain {
+	case RegainNick:
+		id.cfg.Logger.Info("regaining nick", logging.KeyNick, id.cfg.Nick)
+		id.conn.Raw(fmt.Sprintf("PRIVMSG %s :REGAIN %s", id.cfg.Services, id.cfg.Nick))
+	case RegainGhost:
+		id.cfg.Logger.Info("ghosting nick", logging.KeyNick, id.cfg.Nick)
+		id.conn.Raw(fmt.Sprintf("PRIVMSG %s :GHOST %s", id.cfg.Services, id.cfg.Nick))
+		id.phase = phaseGhosting
+	}
+}
+
+func (id *Identifier) stopTimerLocked() {
+	if id.stop != nil {
+		id.stop()
+		id.stop = nil
+	}
+}
+
+func containsAny(s string, subs []string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
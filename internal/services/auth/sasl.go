@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emersion/go-sasl"
+)
+
+const (
+	SASLPlain    = "plain"
+	SASLExternal = "external" // authenticates with the TLS client certificate
+)
+
+// NewSASL returns the SASL client for mechanism, or nil when mechanism is "".
+// PLAIN needs user and password; EXTERNAL only works with a client certificate.
+func NewSASL(mechanism, user, password string) (sasl.Client, error) {
+	switch strings.ToLower(strings.TrimSpace(mechanism)) {
+	case "":
+		return nil, nil
+	case SASLPlain:
+		if user == "" || password == "" {
+			return nil, fmt.Errorf("sasl plain: user and password are required")
+		}
+		return sasl.NewPlainClient("", user, password), nil
+	case SASLExternal:
+		return sasl.NewExternalClient(""), nil
+	}
+	return nil, fmt.Errorf("sasl: unknown mechanism %q (want plain or external)", mechanism)
+}
@@ -3,10 +3,10 @@ package bondpoints
 import (
 	"context"
 	"math"
-	"time"
 
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
 	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
 )
 
 type Result struct {
@@ -24,25 +24,26 @@ type Service interface {
 
 type Impl struct {
 	repo cat_player.CatPlayerRepository
-	loc  *time.Location
+	cal  *calendar.Calendar
 }
 
-func New(repo cat_player.CatPlayerRepository) Service {
-	loc, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		loc = time.Local
-	}
-	return &Impl{repo: repo, loc: loc}
-}
+// Option configures the service.
+type Option func(*Impl)
 
-func (s *Impl) nyNow() time.Time {
-	return time.Now().In(s.loc)
+// WithCalendar sets the game day (default: calendar.DefaultTimezone).
+func WithCalendar(cal *calendar.Calendar) Option {
+	return func(s *Impl) { s.cal = cal }
 }
 
-func (s *Impl) sameDayNY(a, b time.Time) bool {
-	aa := a.In(s.loc)
-	bb := b.In(s.loc)
-	return aa.Year() == bb.Year() && aa.YearDay() == bb.YearDay()
+func New(repo cat_player.CatPlayerRepository, opts ...Option) Service {
+	s := &Impl{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.cal == nil {
+		s.cal = calendar.Default()
+	}
+	return s
 }
 
 func pointsForStreak(streak int) int {
@@ -57,7 +58,7 @@ func pointsForStreak(streak int) int {
 }
 
 func (s *Impl) RecordBondedInteraction(ctx context.Context, nick, network, channel string) (Result, error) {
-	now := s.nyNow()
+	now := s.cal.Now()
 
 	p, err := s.repo.GetPlayerByName(ctx, nick, network, channel)
 	if err != nil {
@@ -86,8 +87,8 @@ func (s *Impl) RecordBondedInteraction(ctx context.Context, nick, network, chann
 		}, nil
 	}
 
-	// One award per NY day
-	if p.LastBondPointsAt != nil && s.sameDayNY(*p.LastBondPointsAt, now) {
+	// One award per game day
+	if p.LastBondPointsAt != nil && s.cal.SameDay(*p.LastBondPointsAt, now) {
 		return Result{
 			AwardedPoints: 0,
 			TotalPoints:   p.BondPoints,
@@ -99,11 +100,8 @@ func (s *Impl) RecordBondedInteraction(ctx context.Context, nick, network, chann
 
 	// Compute streak (daily)
 	newStreak := 1
-	if p.LastBondPointsAt != nil {
-		yesterday := now.AddDate(0, 0, -1)
-		if s.sameDayNY(*p.LastBondPointsAt, yesterday) {
-			newStreak = p.BondPointStreak + 1
-		}
+	if p.LastBondPointsAt != nil && s.cal.DayBefore(*p.LastBondPointsAt, now) {
+		newStreak = p.BondPointStreak + 1
 	}
 
 	pts := pointsForStreak(newStreak)
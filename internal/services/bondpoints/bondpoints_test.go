@@ -2,146 +2,14 @@ package bondpoints
 
 import (
 	"context"
-	"strings"
-	"sync"
 	"testing"
 	"time"
 
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
 )
 
-// mockCatPlayerRepo is a simple in-memory mock for testing
-type mockCatPlayerRepo struct {
-	mu      sync.RWMutex
-	players map[string]*cat_player.CatPlayer
-}
-
-func newMockRepo() *mockCatPlayerRepo {
-	return &mockCatPlayerRepo{
-		players: make(map[string]*cat_player.CatPlayer),
-	}
-}
-
-func (m *mockCatPlayerRepo) key(name, network, channel string) string {
-	return strings.ToLower(name) + "|" + strings.ToLower(network) + "|" + strings.ToLower(channel)
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByID(ctx context.Context, id string) (*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByName(ctx context.Context, name, network, channel string) (*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	p := m.players[m.key(name, network, channel)]
-	if p != nil {
-		// Return a copy
-		cp := *p
-		return &cp, nil
-	}
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetAllPlayers(ctx context.Context, network, channel string) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) UpsertPlayer(ctx context.Context, player *cat_player.CatPlayer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(player.Name, player.Network, player.Channel)
-	m.players[k] = player
-	return nil
-}
-
-func (m *mockCatPlayerRepo) TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetDecayAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) ListPlayersAtOrAbove(ctx context.Context, network, channel string, minLove int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) SetPerfectDropWarned(ctx context.Context, name, network, channel string, warned bool) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddBondPoints(ctx context.Context, name, network, channel string, delta int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPoints += delta
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastBondPointsAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPointStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.HighestBondStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.GiftsUnlocked |= giftMask
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.GiftsUnlocked = giftsUnlocked
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(nick, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LoveMeter = love
-	}
-	return nil
+func newPlayerRepo() cat_player.CatPlayerRepository {
+	return cat_player.NewMemoryPlayerRepository()
 }
 
 // Tests
@@ -174,7 +42,7 @@ func TestPointsForStreak(t *testing.T) {
 }
 
 func TestNew(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo)
 	if svc == nil {
 		t.Fatal("New() returned nil")
@@ -182,7 +50,7 @@ func TestNew(t *testing.T) {
 }
 
 func TestRecordBondedInteraction_NewPlayer(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo)
 
 	ctx := context.Background()
@@ -206,7 +74,7 @@ func TestRecordBondedInteraction_NewPlayer(t *testing.T) {
 }
 
 func TestRecordBondedInteraction_SameDay(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo)
 	ctx := context.Background()
 
@@ -229,12 +97,12 @@ func TestRecordBondedInteraction_SameDay(t *testing.T) {
 }
 
 func TestRecordBondedInteraction_HighestStreak(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo).(*Impl)
 	ctx := context.Background()
 
 	// Setup: player with existing highest streak
-	now := svc.nyNow()
+	now := svc.cal.Now()
 	yesterday := now.AddDate(0, 0, -1)
 
 	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
@@ -260,12 +128,12 @@ func TestRecordBondedInteraction_HighestStreak(t *testing.T) {
 }
 
 func TestRecordBondedInteraction_NewHighestStreak(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo).(*Impl)
 	ctx := context.Background()
 
 	// Setup: player about to beat their highest
-	now := svc.nyNow()
+	now := svc.cal.Now()
 	yesterday := now.AddDate(0, 0, -1)
 
 	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
@@ -286,12 +154,12 @@ func TestRecordBondedInteraction_NewHighestStreak(t *testing.T) {
 }
 
 func TestRecordBondedInteraction_StreakReset(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo).(*Impl)
 	ctx := context.Background()
 
 	// Setup: player who missed a day
-	now := svc.nyNow()
+	now := svc.cal.Now()
 	twoDaysAgo := now.AddDate(0, 0, -2)
 
 	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
@@ -317,7 +185,7 @@ func TestRecordBondedInteraction_StreakReset(t *testing.T) {
 }
 
 func TestSameDayNY(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	svc := New(repo).(*Impl)
 
 	loc, _ := time.LoadLocation("America/New_York")
@@ -325,14 +193,14 @@ func TestSameDayNY(t *testing.T) {
 	// Same day in NY
 	t1 := time.Date(2024, 1, 15, 10, 0, 0, 0, loc)
 	t2 := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
-	if !svc.sameDayNY(t1, t2) {
+	if !svc.cal.SameDay(t1, t2) {
 		t.Error("should be same day")
 	}
 
 	// Different days in NY
 	t3 := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
 	t4 := time.Date(2024, 1, 16, 1, 0, 0, 0, loc)
-	if svc.sameDayNY(t3, t4) {
+	if svc.cal.SameDay(t3, t4) {
 		t.Error("should be different days")
 	}
 }
@@ -16,6 +16,34 @@ type Unlock struct {
 	GiftName string
 }
 
+// Gift is one bit of CatPlayer.GiftsUnlocked.
+type Gift struct {
+	Mask int
+	Key  string // stable id for the API, e.g. "tiny_guinea_pig"
+	Name string
+}
+
+// Gifts lists every gift in unlock order.
+var Gifts = []Gift{
+	{Gift7, "tiny_guinea_pig", "🐹 Tiny Guinea Pig"},
+	{Gift14, "cute_python", "🐍 Cute Python"},
+	{Gift21, "noisy_parrot", "🦜 Noisy Parrot"},
+	{Gift30, "colorful_fish", "🐠 Colorful Fish"},
+	{Gift45, "friendly_kitten", "🐱 Friendly Kitten"},
+	{Gift100, "secret_gift", "🎁 Secret Gift (Forever Human)"},
+}
+
+// GiftsFromMask decodes a GiftsUnlocked bitmask.
+func GiftsFromMask(mask int) []Gift {
+	var out []Gift
+	for _, g := range Gifts {
+		if mask&g.Mask != 0 {
+			out = append(out, g)
+		}
+	}
+	return out
+}
+
 func TitleForHighestStreak(highest int) string {
 	switch {
 	case highest >= 100:
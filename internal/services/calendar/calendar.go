@@ -0,0 +1,122 @@
+package calendar
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+
+	// the game day must not depend on the host having tzdata installed
+	// (scratch/alpine images usually don't)
+	_ "time/tzdata"
+)
+
+/*
+CALENDAR
+Decides when a game day starts and ends. Catnip cooldowns, BondPoints,
+daily streaks and love decay all ask the same Calendar, so they agree on
+midnight. Each channel has its own (see the "timezone" channel setting).
+*/
+
+// DefaultTimezone is the game day used when none is configured.
+const DefaultTimezone = "America/New_York"
+
+type Calendar struct {
+	loc   atomic.Pointer[time.Location]
+	clock clock.Clock
+	log   *slog.Logger
+}
+
+// Option configures a Calendar.
+type Option func(*Calendar)
+
+// WithClock replaces the wall clock, e.g. with a clock.Fake in tests.
+func WithClock(c clock.Clock) Option {
+	return func(cal *Calendar) { cal.clock = c }
+}
+
+// WithLogger sets the logger for configuration problems (default: slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(cal *Calendar) { cal.log = l }
+}
+
+// New returns a calendar for timezone. An unknown timezone is logged and
+// DefaultTimezone is used instead.
+func New(timezone string, opts ...Option) *Calendar {
+	c := &Calendar{clock: clock.Real{}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.log = logging.Or(c.log)
+	if err := c.SetTimezone(timezone); err != nil {
+		c.log.Warn("unknown game timezone, using the default", "error", err, "timezone", DefaultTimezone)
+		loc, _ := LoadLocation(DefaultTimezone)
+		c.loc.Store(loc)
+	}
+	return c
+}
+
+// Default is a calendar in DefaultTimezone.
+func Default() *Calendar { return New(DefaultTimezone) }
+
+// LoadLocation is time.LoadLocation with "" meaning DefaultTimezone. When
+// even that can't be loaded it falls back to UTC, so callers always get a
+// usable location.
+func LoadLocation(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultTimezone
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// SetTimezone moves the game day to timezone; on error it is left unchanged.
+func (c *Calendar) SetTimezone(timezone string) error {
+	loc, err := LoadLocation(timezone)
+	if err != nil {
+		return err
+	}
+	c.loc.Store(loc)
+	return nil
+}
+
+// Location is the calendar's timezone.
+func (c *Calendar) Location() *time.Location { return c.loc.Load() }
+
+// Now is the current time in the calendar's timezone.
+func (c *Calendar) Now() time.Time { return c.clock.Now().In(c.Location()) }
+
+// StartOfDay is midnight of the game day t falls on.
+func (c *Calendar) StartOfDay(t time.Time) time.Time {
+	y, m, d := t.In(c.Location()).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, c.Location())
+}
+
+// NextDay is midnight at the end of the game day t falls on.
+func (c *Calendar) NextDay(t time.Time) time.Time {
+	y, m, d := t.In(c.Location()).Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, c.Location())
+}
+
+// SameDay reports whether a and b fall on the same game day.
+func (c *Calendar) SameDay(a, b time.Time) bool {
+	ay, am, ad := a.In(c.Location()).Date()
+	by, bm, bd := b.In(c.Location()).Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// DayBefore reports whether last falls on the game day before now.
+func (c *Calendar) DayBefore(last, now time.Time) bool {
+	return c.SameDay(last, c.StartOfDay(now).Add(-time.Nanosecond))
+}
+
+// Today reports whether t falls on the current game day.
+func (c *Calendar) Today(t time.Time) bool { return c.SameDay(t, c.Now()) }
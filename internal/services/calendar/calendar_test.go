@@ -0,0 +1,68 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+)
+
+func TestCalendar_SameDayFollowsTimezone(t *testing.T) {
+	// 03:00 UTC is still the previous evening in New York
+	a := time.Date(2024, 1, 16, 3, 0, 0, 0, time.UTC)
+	b := time.Date(2024, 1, 15, 20, 0, 0, 0, time.UTC)
+
+	ny := New("America/New_York")
+	if !ny.SameDay(a, b) {
+		t.Error("should be the same day in New York")
+	}
+
+	utc := New("UTC")
+	if utc.SameDay(a, b) {
+		t.Error("should be different days in UTC")
+	}
+	if !utc.DayBefore(b, a) || utc.DayBefore(a, a) {
+		t.Error("DayBefore should only match the previous day")
+	}
+
+	if err := utc.SetTimezone("Asia/Bangkok"); err != nil {
+		t.Fatal(err)
+	}
+	if got := utc.StartOfDay(a); !got.Equal(time.Date(2024, 1, 16, 0, 0, 0, 0, utc.Location())) {
+		t.Errorf("start of day = %v", got)
+	}
+}
+
+func TestCalendar_DayBeforeAcrossDST(t *testing.T) {
+	ny := New("America/New_York")
+	loc := ny.Location()
+
+	// the night clocks went forward: the day is only 23 hours long
+	last := time.Date(2024, 3, 9, 23, 30, 0, 0, loc)
+	now := time.Date(2024, 3, 10, 23, 45, 0, 0, loc)
+	if !ny.DayBefore(last, now) {
+		t.Error("March 9 is the day before March 10")
+	}
+	if next := ny.NextDay(last); next.Sub(last) != 30*time.Minute {
+		t.Errorf("next day starts in %v", next.Sub(last))
+	}
+}
+
+func TestCalendar_Fallbacks(t *testing.T) {
+	c := New("Nowhere/Special")
+	if c.Location().String() != DefaultTimezone {
+		t.Errorf("unknown timezone should fall back to %s, got %s", DefaultTimezone, c.Location())
+	}
+	if err := c.SetTimezone("Nowhere/Special"); err == nil || c.Location().String() != DefaultTimezone {
+		t.Error("a failed SetTimezone must keep the current timezone")
+	}
+
+	fixed := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	c = New("", WithClock(clock.NewFake(fixed)))
+	if !c.Now().Equal(fixed) || c.Now().Location().String() != DefaultTimezone {
+		t.Errorf("Now() = %v", c.Now())
+	}
+	if !c.Today(fixed.Add(time.Hour)) || c.Today(fixed.AddDate(0, 0, 1)) {
+		t.Error("Today should use the calendar's clock")
+	}
+}
@@ -3,15 +3,24 @@ package cat_actions
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
 	"github.com/MyelinBots/catbot-go/internal/services/bondpoints"
 	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
 	"github.com/MyelinBots/catbot-go/internal/services/lovemeter"
+	"github.com/MyelinBots/catbot-go/internal/services/random"
+	"github.com/MyelinBots/catbot-go/internal/services/settings"
+	"github.com/MyelinBots/catbot-go/internal/services/streak"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -82,23 +91,74 @@ type CatActions struct {
 	slapWarned   map[string]bool
 	catnipUsedAt map[string]time.Time
 	BondPoints   bondpoints.Service
+	Streaks      streak.Service
 
 	// spawn session
 	presentUntil time.Time
 	nextSpawnAt  time.Time
 
-	minRespawn  time.Duration
-	maxRespawn  time.Duration
-	spawnWindow time.Duration
+	// channel settings: spawn timings, odds, catnip and decay
+	settings settings.Settings
+
+	// game day shared with the love meter, BondPoints and streaks
+	cal *calendar.Calendar
+
+	// time and dice, swappable for deterministic tests and replays
+	clock clock.Clock
+	rand  random.Rand
 
 	lastLeaveMsg string
 	lastSpawnMsg string
+
+	// persisted copy of the state above (nil = memory only)
+	state  channel_state.ChannelStateRepository
+	writes stateWriter
+
+	// tagged with the network and channel
+	log *slog.Logger
+
+	// append-only history of interactions (nil = not logged)
+	eventLog cat_event.CatEventRepository
+}
+
+// Option configures CatActions.
+type Option func(*CatActions)
+
+// WithStateRepository loads presence, catnip cooldowns and slap warnings from repo
+// and writes every change back, so a restart doesn't reset them.
+func WithStateRepository(repo channel_state.ChannelStateRepository) Option {
+	return func(ca *CatActions) { ca.state = repo }
 }
 
-func NewCatActions(catPlayerRepo cat_player.CatPlayerRepository, network, channel string, spawnWindow, minRespawn, maxRespawn time.Duration) CatActionsImpl {
+// WithSettings starts the channel with s instead of the defaults.
+func WithSettings(s settings.Settings) Option {
+	return func(ca *CatActions) { ca.settings = s }
+}
+
+// WithClock replaces the wall clock (spawns, cooldowns, the game day).
+func WithClock(c clock.Clock) Option {
+	return func(ca *CatActions) { ca.clock = c }
+}
+
+// WithLogger sets the logger (default: slog.Default()); the channel's
+// network and channel are added to it.
+func WithLogger(l *slog.Logger) Option {
+	return func(ca *CatActions) { ca.log = l }
+}
+
+// WithEventLog appends every interaction, the daily decay and admins'
+// !setlove/!setbp to repo.
+func WithEventLog(repo cat_event.CatEventRepository) Option {
+	return func(ca *CatActions) { ca.eventLog = repo }
+}
+
+// WithRand replaces the random source, e.g. random.New(seed) to replay a game.
+func WithRand(r random.Rand) Option {
+	return func(ca *CatActions) { ca.rand = r }
+}
+
+func NewCatActions(catPlayerRepo cat_player.CatPlayerRepository, network, channel string, spawnWindow, minRespawn, maxRespawn time.Duration, opts ...Option) CatActionsImpl {
 	ca := &CatActions{
-		LoveMeter:     lovemeter.NewLoveMeter(catPlayerRepo, network, channel),
-		BondPoints:    bondpoints.New(catPlayerRepo),
 		Actions:       emotes,
 		CatPlayerRepo: catPlayerRepo,
 		Network:       network,
@@ -107,14 +167,37 @@ func NewCatActions(catPlayerRepo cat_player.CatPlayerRepository, network, channe
 		slapWarned:   make(map[string]bool),
 		catnipUsedAt: make(map[string]time.Time),
 
-		minRespawn:  minRespawn,
-		maxRespawn:  maxRespawn,
-		spawnWindow: spawnWindow,
+		settings: settings.Defaults(),
+		clock:    clock.Real{},
+	}
+	ca.settings.SpawnWindow = spawnWindow
+	ca.settings.MinRespawn = minRespawn
+	ca.settings.MaxRespawn = maxRespawn
+	for _, opt := range opts {
+		opt(ca)
+	}
+	if ca.rand == nil {
+		ca.rand = random.New(0)
+	}
+	ca.log = logging.Or(ca.log).With(logging.KeyNetwork, network, logging.KeyChannel, channel)
+
+	ca.cal = calendar.New(ca.settings.Timezone, calendar.WithClock(ca.clock), calendar.WithLogger(ca.log))
+	ca.Streaks = streak.New(catPlayerRepo, streak.WithCalendar(ca.cal))
+	ca.LoveMeter = lovemeter.NewLoveMeter(catPlayerRepo, network, channel, lovemeter.WithCalendar(ca.cal), lovemeter.WithLogger(ca.log), lovemeter.WithEventLog(ca.eventLog), lovemeter.WithStreaks(ca.Streaks))
+	ca.BondPoints = bondpoints.New(catPlayerRepo, bondpoints.WithCalendar(ca.cal))
+	ca.applyCalendarAndDecay()
+
+	if ca.loadState() {
+		return ca
 	}
 
-	// Start present immediately
-	now := time.Now()
-	ca.presentUntil = now.Add(ca.spawnWindow)
+	// First run in this channel: start present immediately
+	now := ca.clock.Now()
+	ca.mu.Lock()
+	ca.presentUntil = now.Add(ca.settings.SpawnWindow)
+	ca.savePresenceLocked()
+	ca.unlock()
+	metrics.Spawns.WithLabelValues(network, channel).Inc()
 
 	return ca
 }
@@ -126,7 +209,7 @@ func NewCatActions(catPlayerRepo cat_player.CatPlayerRepository, network, channe
 func (ca *CatActions) GetActions() []string { return ca.Actions }
 
 func (ca *CatActions) GetRandomAction() string {
-	return ca.Actions[rand.Intn(len(ca.Actions))]
+	return ca.Actions[ca.rand.Intn(len(ca.Actions))]
 }
 
 func (ca *CatActions) appendBondProgress(_ string, msg string) string { return msg }
@@ -158,44 +241,15 @@ func formatWait(d time.Duration) string {
 	return fmt.Sprintf("%dh %dm", hr, min)
 }
 
-func sameDayInNY(a, b time.Time) bool {
-	loc, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		loc = time.Local
-	}
-
-	aa := a.In(loc)
-	bb := b.In(loc)
-
-	return aa.Year() == bb.Year() && aa.YearDay() == bb.YearDay()
-}
-
 func giftNamesFromMask(mask int) []string {
 	var out []string
-
-	if mask&bondrewards.Gift7 != 0 {
-		out = append(out, "🐹 Tiny Guinea Pig")
-	}
-	if mask&bondrewards.Gift14 != 0 {
-		out = append(out, "🐍 Cute Python")
-	}
-	if mask&bondrewards.Gift21 != 0 {
-		out = append(out, "🦜 Noisy Parrot")
+	for _, g := range bondrewards.GiftsFromMask(mask) {
+		out = append(out, g.Name)
 	}
-	if mask&bondrewards.Gift30 != 0 {
-		out = append(out, "🐠 Colorful Fish")
-	}
-	if mask&bondrewards.Gift45 != 0 {
-		out = append(out, "🐱 Friendly Kitten")
-	}
-	if mask&bondrewards.Gift100 != 0 {
-		out = append(out, "🎁 Secret Gift (Forever Human)")
-	}
-
 	return out
 }
 
-func misuseMessage(player, action, target string) string {
+func misuseMessage(r random.Rand, player, action, target string) string {
 	pt := cases.Title(language.English).String(target)
 
 	// Special: slap misuse => cat retaliates
@@ -206,7 +260,7 @@ func misuseMessage(player, action, target string) string {
 			fmt.Sprintf("🐾 claws %s... I did not like you slapping %s", player, pt),
 			fmt.Sprintf("😿 bites %s lightly... Why would you slap %s?", player, pt),
 		}
-		return lines[rand.Intn(len(lines))]
+		return lines[r.Intn(len(lines))]
 	}
 
 	// Optional: kick misuse => cat retaliates
@@ -217,7 +271,7 @@ func misuseMessage(player, action, target string) string {
 			fmt.Sprintf("😼 hisses at %s... Why would you kick %s?", player, pt),
 			fmt.Sprintf("😿 bites %s’s ankle... Kicking %s made me sad...", player, pt),
 		}
-		return lines[rand.Intn(len(lines))]
+		return lines[r.Intn(len(lines))]
 	}
 
 	if action == "laser" {
@@ -227,7 +281,7 @@ func misuseMessage(player, action, target string) string {
 			fmt.Sprintf("🐾 Wrong target, %s... You have to -> !%s purrito", player, action),
 			fmt.Sprintf("😿 You seem confused... You have to -> !%s purrito", action),
 		}
-		return lines[rand.Intn(len(lines))]
+		return lines[r.Intn(len(lines))]
 	}
 
 	// Generic misuse for all other commands
@@ -251,7 +305,51 @@ func misuseMessage(player, action, target string) string {
 		fmt.Sprintf("😿 %s looks awkward... I think you meant to do that to Purrito.", pt),
 		fmt.Sprintf("😼 %s ignores you completely... That command is not for me.", pt),
 	}
-	return lines[rand.Intn(len(lines))]
+	return lines[r.Intn(len(lines))]
+}
+
+// --------------------
+// Settings
+// --------------------
+
+// Settings returns the channel's current settings.
+func (ca *CatActions) Settings() settings.Settings {
+	ca.mu.RLock()
+	defer ca.mu.RUnlock()
+	return ca.settings
+}
+
+// ApplySettings switches the channel to s right away. A cat that's already
+// present or waiting to respawn keeps its current timer.
+func (ca *CatActions) ApplySettings(s settings.Settings) {
+	ca.mu.Lock()
+	ca.settings = s
+	ca.mu.Unlock()
+	ca.applyCalendarAndDecay()
+}
+
+// Calendar is the channel's game day.
+func (ca *CatActions) Calendar() *calendar.Calendar { return ca.cal }
+
+// Clock is the channel's time source.
+func (ca *CatActions) Clock() clock.Clock { return ca.clock }
+
+// Logger is the channel's logger.
+func (ca *CatActions) Logger() *slog.Logger { return ca.log }
+
+func (ca *CatActions) applyCalendarAndDecay() {
+	s := ca.Settings()
+	if err := ca.cal.SetTimezone(s.Timezone); err != nil {
+		ca.log.Warn("invalid timezone setting", "error", err, "timezone", ca.cal.Location().String())
+	}
+	if lm, ok := ca.LoveMeter.(interface{ SetDailyDecay(int) }); ok {
+		lm.SetDailyDecay(s.DailyDecay)
+	}
+}
+
+// accepted rolls the channel's accept chance for pet/love/feed/laser.
+func (ca *CatActions) accepted() bool {
+	return ca.rand.Intn(100) < ca.Settings().AcceptChance
 }
 
 // --------------------
@@ -259,29 +357,53 @@ func misuseMessage(player, action, target string) string {
 // --------------------
 
 func (ca *CatActions) IsHere() bool {
-	now := time.Now()
+	now := ca.clock.Now()
 
 	ca.mu.Lock()
-	defer ca.mu.Unlock()
+	defer ca.unlock()
 
 	// present expired => despawn + schedule respawn (timeout leave)
 	if !ca.presentUntil.IsZero() && now.After(ca.presentUntil) {
-		ca.lastLeaveMsg = timeoutLeaveMessage() // ✅ now it's used
+		ca.lastLeaveMsg = timeoutLeaveMessage(ca.rand) // ✅ now it's used
 		ca.despawnLocked(now)
+		metrics.Leaves.WithLabelValues(ca.Network, ca.Channel, metrics.LeaveTimeout).Inc()
 	}
 
 	// not present but respawn time reached => spawn again
 	if ca.presentUntil.IsZero() && !ca.nextSpawnAt.IsZero() && !now.Before(ca.nextSpawnAt) {
-		ca.presentUntil = now.Add(ca.spawnWindow)
+		ca.presentUntil = now.Add(ca.settings.SpawnWindow)
 
 		// ✅ ตั้งข้อความ "โผล่" แค่ครั้งเดียวต่อรอบ
-		emote := emotes[rand.Intn(len(emotes))]
+		emote := emotes[ca.rand.Intn(len(emotes))]
 		ca.lastSpawnMsg = fmt.Sprintf("🐈 meowww ... %s", emote)
+		ca.savePresenceLocked()
+		metrics.Spawns.WithLabelValues(ca.Network, ca.Channel).Inc()
 	}
 
 	return !ca.presentUntil.IsZero() && now.Before(ca.presentUntil)
 }
 
+// Presence is a snapshot of the spawn session for status pages.
+type Presence struct {
+	Here         bool
+	PresentUntil time.Time // zero while away
+	NextSpawnAt  time.Time // zero while here or with no respawn pending
+}
+
+// Presence reports where Purrito is without spawning or despawning him
+// (IsHere does both when a timer is due).
+func (ca *CatActions) Presence() Presence {
+	now := ca.clock.Now()
+
+	ca.mu.RLock()
+	defer ca.mu.RUnlock()
+	return Presence{
+		Here:         !ca.presentUntil.IsZero() && now.Before(ca.presentUntil),
+		PresentUntil: ca.presentUntil,
+		NextSpawnAt:  ca.nextSpawnAt,
+	}
+}
+
 // EnsureHere is kept for backward compatibility (catbot.go still calls it).
 // With the "one interaction per spawn" system, we DO NOT want callers to keep
 // Purrito permanently present.
@@ -289,10 +411,10 @@ func (ca *CatActions) IsHere() bool {
 // EnsureHere only spawns Purrito if he is not present AND there is no pending spawn timer.
 // If he is present, it does nothing (does NOT extend the window).
 func (ca *CatActions) EnsureHere(forHowLong time.Duration) {
-	now := time.Now()
+	now := ca.clock.Now()
 
 	ca.mu.Lock()
-	defer ca.mu.Unlock()
+	defer ca.unlock()
 
 	// If already present, do not extend (prevents "always here" behavior)
 	if !ca.presentUntil.IsZero() && now.Before(ca.presentUntil) {
@@ -304,32 +426,36 @@ func (ca *CatActions) EnsureHere(forHowLong time.Duration) {
 		return
 	}
 
-	// Spawn now for at most forHowLong, but cap to ca.spawnWindow to keep gameplay consistent
+	// Spawn now for at most forHowLong, but cap to the spawn window to keep gameplay consistent
 	window := forHowLong
-	if window <= 0 || window > ca.spawnWindow {
-		window = ca.spawnWindow
+	if window <= 0 || window > ca.settings.SpawnWindow {
+		window = ca.settings.SpawnWindow
 	}
 
 	ca.presentUntil = now.Add(window)
 	ca.nextSpawnAt = time.Time{}
+	ca.savePresenceLocked()
+	metrics.Spawns.WithLabelValues(ca.Network, ca.Channel).Inc()
 }
 
 func (ca *CatActions) despawnLocked(now time.Time) {
 	ca.presentUntil = time.Time{}
 
-	delay := ca.minRespawn
-	if ca.maxRespawn > ca.minRespawn {
-		delay = ca.minRespawn + time.Duration(rand.Int63n(int64(ca.maxRespawn-ca.minRespawn)))
+	delay := ca.settings.MinRespawn
+	if ca.settings.MaxRespawn > ca.settings.MinRespawn {
+		delay = ca.settings.MinRespawn + time.Duration(ca.rand.Int63n(int64(ca.settings.MaxRespawn-ca.settings.MinRespawn)))
 	}
 	ca.nextSpawnAt = now.Add(delay)
+	ca.savePresenceLocked()
 }
 
 // DespawnAfterInteraction immediately despawns Purrito and starts the respawn timer.
 // Call this after a successful interaction to enforce "one interaction per spawn".
 func (ca *CatActions) DespawnAfterInteraction() {
 	ca.mu.Lock()
-	defer ca.mu.Unlock()
-	ca.despawnLocked(time.Now())
+	defer ca.unlock()
+	ca.despawnLocked(ca.clock.Now())
+	metrics.Leaves.WithLabelValues(ca.Network, ca.Channel, metrics.LeaveInteraction).Inc()
 }
 
 // --------------------
@@ -338,13 +464,14 @@ func (ca *CatActions) DespawnAfterInteraction() {
 
 func (ca *CatActions) CatnipRemaining(player string) time.Duration {
 	key := normalizeNick(player)
-	now := time.Now()
+	now := ca.clock.Now()
 
 	ca.mu.RLock()
 	last := ca.catnipUsedAt[key]
+	cooldown := ca.settings.CatnipCooldown
 	ca.mu.RUnlock()
 
-	return remainingCatnip(now, last)
+	return remainingCatnipAfter(now, last, cooldown)
 }
 
 func (ca *CatActions) CatnipOnCooldown(player string) bool { return ca.CatnipRemaining(player) > 0 }
@@ -389,7 +516,7 @@ func (ca *CatActions) gatePresenceForAction(_ string) (bool, string) {
 
 	wait := time.Duration(0)
 	if !next.IsZero() {
-		wait = time.Until(next)
+		wait = next.Sub(ca.clock.Now())
 	}
 
 	return false, fmt.Sprintf("🐾 Purrito is not here right now... he will be back in %s...", formatWait(wait))
@@ -406,7 +533,7 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 
 	// all other commands must target purrito
 	if t != "purrito" {
-		return misuseMessage(player, a, target)
+		return misuseMessage(ca.rand, player, a, target)
 	}
 
 	switch a {
@@ -419,13 +546,18 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 		// One interaction per spawn - despawn immediately
 		ca.DespawnAfterInteraction()
 
-		if rand.Intn(100) < 60 {
-			ca.LoveMeter.Increase(player, 1)
-			return ca.acceptMessage(player)
+		in := ca.beginInteraction(a, player)
+		if ca.accepted() {
+			ca.changeLove(in, 1)
+			streakNote := ca.advanceStreak(player)
+			msg := ca.acceptMessage(in) + streakNote
+			ca.finishInteraction(in, cat_event.OutcomeAccepted)
+			return msg
 		}
 
-		ca.LoveMeter.Decrease(player, 1)
-		return ca.rejectMessage(player)
+		love := ca.changeLove(in, -1)
+		ca.finishInteraction(in, cat_event.OutcomeRejected)
+		return ca.rejectMessage(player, love)
 
 	case "feed":
 		if ok, msg := ca.gatePresenceForAction(a); !ok {
@@ -440,15 +572,20 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 			"fish snacks", "cream", "shrimp", "turkey", "beef", "cat treats",
 			"catnip-infused snacks",
 		}
-		food := foods[rand.Intn(len(foods))]
-
-		if rand.Intn(100) < 60 {
-			ca.LoveMeter.Increase(player, 1)
-			return ca.feedAcceptMessage(player, food)
+		food := foods[ca.rand.Intn(len(foods))]
+
+		in := ca.beginInteraction(a, player)
+		if ca.accepted() {
+			love := ca.changeLove(in, 1)
+			streakNote := ca.advanceStreak(player)
+			msg := ca.feedAcceptMessage(player, food, love) + streakNote
+			ca.finishInteraction(in, cat_event.OutcomeAccepted)
+			return msg
 		}
 
-		ca.LoveMeter.Decrease(player, 1)
-		return ca.feedRejectMessage(player, food)
+		love := ca.changeLove(in, -1)
+		ca.finishInteraction(in, cat_event.OutcomeRejected)
+		return ca.feedRejectMessage(player, food, love)
 
 	case "laser":
 		if ok, msg := ca.gatePresenceForAction(a); !ok {
@@ -458,13 +595,18 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 		// One interaction per spawn - despawn immediately
 		ca.DespawnAfterInteraction()
 
-		if rand.Intn(100) < 60 {
-			ca.LoveMeter.Increase(player, 1)
-			return ca.laserAcceptMessage(player)
+		in := ca.beginInteraction(a, player)
+		if ca.accepted() {
+			love := ca.changeLove(in, 1)
+			streakNote := ca.advanceStreak(player)
+			msg := ca.laserAcceptMessage(player, love) + streakNote
+			ca.finishInteraction(in, cat_event.OutcomeAccepted)
+			return msg
 		}
 
-		ca.LoveMeter.Decrease(player, 1)
-		return ca.laserRejectMessage(player)
+		love := ca.changeLove(in, -1)
+		ca.finishInteraction(in, cat_event.OutcomeRejected)
+		return ca.laserRejectMessage(player, love)
 
 	case "catnip":
 		if ok, msg := ca.gatePresenceForAction(a); !ok {
@@ -487,14 +629,18 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 
 		key := strings.ToLower(strings.TrimSpace(player))
 
+		in := ca.beginInteraction(a, player)
 		ca.mu.Lock()
 		warned := ca.slapWarned[key]
 		if !warned {
 			ca.slapWarned[key] = true
+			ca.saveSlapWarnedLocked(key)
 		}
-		ca.mu.Unlock()
+		ca.unlock()
 
 		if !warned {
+			ca.keepLove(in)
+			ca.finishInteraction(in, cat_event.OutcomeWarned)
 			firstWarnings := []string{
 				fmt.Sprintf("😾 Purrito flattens his ears at %s... This is your warning... do not slap him again...", player),
 				fmt.Sprintf("⚠️ Purrito stares at %s with shocked eyes... he did not like that...", player),
@@ -502,13 +648,12 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 				fmt.Sprintf("⚠️ Purrito watches %s carefully... one more slap and he will be upset", player),
 				fmt.Sprintf("😼 Purrito lifts a paw at %s in warning... do not try that again...", player),
 			}
-			return firstWarnings[rand.Intn(len(firstWarnings))]
+			return firstWarnings[ca.rand.Intn(len(firstWarnings))]
 		}
 
-		ca.LoveMeter.Decrease(player, 1)
-		love := ca.LoveMeter.Get(player)
-		mood := ca.LoveMeter.GetMood(player)
-		bar := ca.LoveMeter.GetLoveBar(player)
+		love := ca.changeLove(in, -1)
+		ca.finishInteraction(in, cat_event.OutcomePunished)
+		mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 		secondPunishments := []string{
 			fmt.Sprintf("😾 Purrito swats back at %s and looks hurt. your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
@@ -517,7 +662,7 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 			fmt.Sprintf("😿 Purrito looks betrayed by %s. your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
 			fmt.Sprintf("😾 Purrito steps back from %s... do not hurt him. your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
 		}
-		return secondPunishments[rand.Intn(len(secondPunishments))]
+		return secondPunishments[ca.rand.Intn(len(secondPunishments))]
 
 	default:
 		return "purrito tilts its head, don't know what you mean 🐾"
@@ -528,34 +673,29 @@ func (ca *CatActions) ExecuteAction(actionName, player, target string) string {
 // Messages
 // --------------------
 
-func (ca *CatActions) acceptMessage(player string) string {
-	emote := emotes[rand.Intn(len(emotes))]
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) acceptMessage(in *interaction) string {
+	emote := emotes[ca.rand.Intn(len(emotes))]
+	love := in.loveAfter
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
-	bonus := ca.tryAwardBondPoints(player) // ✅ เพิ่ม
+	bonus := ca.tryAwardBondPoints(in) // ✅ เพิ่ม
 
 	base := fmt.Sprintf("%s at %s and your love meter is now %d%% and purrito is now %s %s%s",
-		emote, player, love, mood, bar, bonus)
+		emote, in.player, love, mood, bar, bonus)
 
 	return base
 }
 
-func (ca *CatActions) rejectMessage(player string) string {
-	reject := rejects[rand.Intn(len(rejects))]
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) rejectMessage(player string, love int) string {
+	reject := rejects[ca.rand.Intn(len(rejects))]
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	base := fmt.Sprintf("purrito %s at %s and your love meter is now %d%% and purrito is now %s %s", reject, player, love, mood, bar)
 	return ca.appendBondProgress(player, base)
 }
 
-func (ca *CatActions) feedAcceptMessage(player, food string) string {
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) feedAcceptMessage(player, food string, love int) string {
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	lines := []string{
 		fmt.Sprintf("😺 Purrito happily munches the %s you gave, %s! Your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
@@ -563,13 +703,11 @@ func (ca *CatActions) feedAcceptMessage(player, food string) string {
 		fmt.Sprintf("🍣 Purrito LOVES the %s from %s. Your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
 		fmt.Sprintf("😸 Purrito licks his lips after eating the %s from %s! Your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
 	}
-	return ca.appendBondProgress(player, lines[rand.Intn(len(lines))])
+	return ca.appendBondProgress(player, lines[ca.rand.Intn(len(lines))])
 }
 
-func (ca *CatActions) feedRejectMessage(player, food string) string {
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) feedRejectMessage(player, food string, love int) string {
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	lines := []string{
 		fmt.Sprintf("😼 Purrito sniffs the %s from %s and turns away... your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
@@ -577,13 +715,11 @@ func (ca *CatActions) feedRejectMessage(player, food string) string {
 		fmt.Sprintf("🙀 Purrito looks offended by the %s from %s. Your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
 		fmt.Sprintf("😿 Purrito walks away from the %s offered by %s... Your love meter is now %d%% and purrito is now %s %s", food, player, love, mood, bar),
 	}
-	return ca.appendBondProgress(player, lines[rand.Intn(len(lines))])
+	return ca.appendBondProgress(player, lines[ca.rand.Intn(len(lines))])
 }
 
-func (ca *CatActions) laserAcceptMessage(player string) string {
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) laserAcceptMessage(player string, love int) string {
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	lines := []string{
 		fmt.Sprintf("🔦⚡️ The laser flickers! Purrito darts after it, paws flying everywhere! Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
@@ -592,13 +728,11 @@ func (ca *CatActions) laserAcceptMessage(player string) string {
 		fmt.Sprintf("🔦⚡️ Purrito dives at the laser, misses, then looks proud anyway. Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
 		fmt.Sprintf("🔦⚡️ The red dot dances... Purrito bats at it with lightning speed! Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
 	}
-	return ca.appendBondProgress(player, lines[rand.Intn(len(lines))])
+	return ca.appendBondProgress(player, lines[ca.rand.Intn(len(lines))])
 }
 
-func (ca *CatActions) laserRejectMessage(player string) string {
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+func (ca *CatActions) laserRejectMessage(player string, love int) string {
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	lines := []string{
 		fmt.Sprintf("🔦😾 Purrito narrows his eyes... not impressed by the laser right now. Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
@@ -607,14 +741,13 @@ func (ca *CatActions) laserRejectMessage(player string) string {
 		fmt.Sprintf("🔦😼 Purrito watches... then turns away like it's beneath him. Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
 		fmt.Sprintf("🔦😾 Purrito swishes his tail in annoyance and refuses to play. Your love meter is now %d%% and purrito is now %s %s", love, mood, bar),
 	}
-	return ca.appendBondProgress(player, lines[rand.Intn(len(lines))])
+	return ca.appendBondProgress(player, lines[ca.rand.Intn(len(lines))])
 }
 
 func (ca *CatActions) statusMessage(player string) string {
 	// LoveMeter / Mood
 	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	// Presence
 	isHere := ca.IsHere()
@@ -624,19 +757,19 @@ func (ca *CatActions) statusMessage(player string) string {
 	nextSpawn := ca.nextSpawnAt
 	ca.mu.RUnlock()
 
-	now := time.Now()
+	now := ca.clock.Now()
 
 	// --- Presence line (colored) ---
 	var presenceLine string
 	if isHere && !presentUntil.IsZero() {
 		presenceLine = fmt.Sprintf(
 			"\x0310🐾 Presence:\x0F \x0303HERE\x0F (leaves in %s)",
-			formatWait(time.Until(presentUntil)),
+			formatWait(presentUntil.Sub(now)),
 		)
 	} else {
 		wait := time.Duration(0)
 		if !nextSpawn.IsZero() && now.Before(nextSpawn) {
-			wait = time.Until(nextSpawn)
+			wait = nextSpawn.Sub(now)
 		}
 		presenceLine = fmt.Sprintf(
 			"\x0310🐾 Presence:\x0F \x0304AWAY\x0F (back in %s)",
@@ -666,9 +799,9 @@ func (ca *CatActions) statusMessage(player string) string {
 		return strings.Join(lines, " | ")
 	}
 
-	// --- Main progression (HighestStreak + title) ---
+	// --- Main progression (daily streak + HighestStreak + title) ---
 	title := bondrewards.TitleForHighestStreak(p.HighestStreak)
-	mainLine := fmt.Sprintf("\x0310HighestStreak:\x0F %d | \x0310Title:\x0F %s", p.HighestStreak, title)
+	mainLine := fmt.Sprintf("\x0310Daily Streak:\x0F %d day(s) | \x0310HighestStreak:\x0F %d | \x0310Title:\x0F %s", p.CurrentStreak, p.HighestStreak, title)
 
 	// --- BondPoints progression ---
 	bpLine := fmt.Sprintf(
@@ -680,7 +813,7 @@ func (ca *CatActions) statusMessage(player string) string {
 	bpReady := "\x0304LOCKED\x0F (need HighestStreak \u2265 100)"
 	if p.HighestStreak >= 100 {
 		bpReady = "\x0303READY\x0F"
-		if p.LastBondPointsAt != nil && sameDayInNY(*p.LastBondPointsAt, now) {
+		if p.LastBondPointsAt != nil && ca.cal.SameDay(*p.LastBondPointsAt, now) {
 			bpReady = "\x0308ALREADY AWARDED TODAY\x0F"
 		}
 	}
@@ -708,42 +841,44 @@ func (ca *CatActions) statusMessage(player string) string {
 // catnipMessage assumes cooldown was checked BEFORE calling it.
 func (ca *CatActions) catnipMessage(player string) string {
 	key := normalizeNick(player)
-	now := time.Now()
+	now := ca.clock.Now()
 
 	ca.mu.Lock()
 	ca.catnipUsedAt[key] = now
-	ca.mu.Unlock()
+	ca.saveCatnipLocked(key)
+	gain := ca.settings.CatnipLove
+	ca.unlock()
 
-	if rand.Intn(100) < 70 {
-		ca.LoveMeter.Increase(player, 3)
-		love := ca.LoveMeter.Get(player)
-		mood := ca.LoveMeter.GetMood(player)
-		bar := ca.LoveMeter.GetLoveBar(player)
+	in := ca.beginInteraction("catnip", player)
+	if ca.rand.Intn(100) < 70 {
+		love := ca.changeLove(in, gain)
+		streakNote := ca.advanceStreak(player)
+		ca.finishInteraction(in, cat_event.OutcomeAccepted)
+		mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 		variants := []string{
 			fmt.Sprintf("🌿😺 Purrito sniffs the catnip and flops over, rolling around happily at %s... your love meter is now %d%% and purrito is now %s %s", player, love, mood, bar),
 			fmt.Sprintf("🌿😻 Purrito licks the catnip and goes into hyper-purr mode around %s... your love meter is now %d%% and purrito is now %s %s", player, love, mood, bar),
 			fmt.Sprintf("🌿🐾 Purrito cuddles into the catnip near %s and purrs loudly... your love meter is now %d%% and purrito is now %s %s", player, love, mood, bar),
 		}
-		return ca.appendBondProgress(player, variants[rand.Intn(len(variants))])
+		return ca.appendBondProgress(player, variants[ca.rand.Intn(len(variants))]) + streakNote
 	}
 
-	ca.LoveMeter.Decrease(player, 1)
-	love := ca.LoveMeter.Get(player)
-	mood := ca.LoveMeter.GetMood(player)
-	bar := ca.LoveMeter.GetLoveBar(player)
+	love := ca.changeLove(in, -1)
+	ca.finishInteraction(in, cat_event.OutcomeRejected)
+	mood, bar := lovemeter.MoodFor(love), lovemeter.RenderLoveBar(love)
 
 	variants := []string{
 		fmt.Sprintf("🌿🙀 Purrito gets overwhelmed by the catnip from %s and needs space. your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
 		fmt.Sprintf("🌿😾 Purrito sneezes and backs away from %s's catnip... too strong! your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
 		fmt.Sprintf("🌿😿 Purrito looks displeased with the catnip from %s and walks off... your love meter decreased to %d%% and purrito is now %s %s", player, love, mood, bar),
 	}
-	return ca.appendBondProgress(player, variants[rand.Intn(len(variants))])
+	return ca.appendBondProgress(player, variants[ca.rand.Intn(len(variants))])
 }
 
 // timeoutLeaveMessage returns a message when Purrito leaves because he stayed
 // for the full spawnWindow (timeout) and nobody interacted.
-func timeoutLeaveMessage() string {
+func timeoutLeaveMessage(r random.Rand) string {
 	lines := []string{
 		"(=^‥^=)っ ...looks around... no one came. He quietly walks away...",
 		"(=^‥^=)っ ...stretches, yawns, and wanders off...",
@@ -755,7 +890,7 @@ func timeoutLeaveMessage() string {
 		"(=^‥^=)っ ...hops onto a fence and vanishes...",
 		"(=^‥^=)っ ...Time’s up... Purrito got tired of waiting and left...",
 	}
-	return lines[rand.Intn(len(lines))]
+	return lines[r.Intn(len(lines))]
 }
 
 // PopLeaveMessage returns the timeout leave message once (then clears it).
@@ -763,9 +898,10 @@ func timeoutLeaveMessage() string {
 // and pending spawn timers.
 func (ca *CatActions) ForceAbsent() {
 	ca.mu.Lock()
-	defer ca.mu.Unlock()
+	defer ca.unlock()
 	ca.presentUntil = time.Time{}
 	ca.nextSpawnAt = time.Time{}
+	ca.savePresenceLocked()
 }
 
 func (ca *CatActions) PopLeaveMessage() string {
@@ -796,9 +932,9 @@ func (ca *CatActions) TickPresence() (spawnMsg string, leaveMsg string) {
 	return
 }
 
-func (ca *CatActions) tryAwardBondPoints(player string) string {
+func (ca *CatActions) tryAwardBondPoints(in *interaction) string {
 	// เรียกหลัง "สำเร็จ" เท่านั้น
-	res, err := ca.BondPoints.RecordBondedInteraction(context.Background(), player, ca.Network, ca.Channel)
+	res, err := ca.BondPoints.RecordBondedInteraction(context.Background(), in.player, ca.Network, ca.Channel)
 	if err != nil {
 		// อย่าให้พังเกมหลัก แค่แนบข้อความเบาๆ
 		return ""
@@ -808,11 +944,134 @@ func (ca *CatActions) tryAwardBondPoints(player string) string {
 	if res.AwardedPoints <= 0 {
 		return ""
 	}
+	in.bondPoints += res.AwardedPoints
+	metrics.BondPointsAwarded.WithLabelValues(ca.Network, ca.Channel).Add(float64(res.AwardedPoints))
 
 	// แนบข้อความสั้นๆ ให้รู้สึก rewarding
 	return fmt.Sprintf(" ✨ +%d BondPoints (Total: %d ::: BP Streak: %d)", res.AwardedPoints, res.TotalPoints, res.Streak)
 }
 
+// interaction is an interaction in progress and what it changed, for the
+// event log.
+type interaction struct {
+	action, player string
+	at             time.Time
+
+	loveBefore, loveAfter int
+	bondPoints            int // awarded during the interaction
+}
+
+func (ca *CatActions) beginInteraction(action, player string) *interaction {
+	return &interaction{action: action, player: player, at: ca.clock.Now()}
+}
+
+// changeLove adds delta to the player's love and returns the new love. The
+// love before and after come from the same atomic update; if it fails both
+// are the stored love, so the game carries on.
+func (ca *CatActions) changeLove(in *interaction, delta int) int {
+	before, after, err := ca.LoveMeter.Add(context.Background(), in.player, delta)
+	if err != nil {
+		ca.log.Error("failed to change love", logging.KeyNick, in.player, "action", in.action, "error", err)
+		before = ca.LoveMeter.Get(in.player)
+		after = before
+	}
+	in.loveBefore, in.loveAfter = before, after
+	return after
+}
+
+// keepLove records that in left the player's love as it was.
+func (ca *CatActions) keepLove(in *interaction) {
+	love := ca.LoveMeter.Get(in.player)
+	in.loveBefore, in.loveAfter = love, love
+}
+
+// finishInteraction records the outcome of in: pet/love/feed/laser/catnip
+// are counted and sampled, and everything goes to the event log.
+func (ca *CatActions) finishInteraction(in *interaction, outcome string) {
+	switch outcome {
+	case cat_event.OutcomeAccepted, cat_event.OutcomeRejected:
+		result := metrics.ResultRejected
+		if outcome == cat_event.OutcomeAccepted {
+			result = metrics.ResultAccepted
+		}
+		metrics.Interactions.WithLabelValues(ca.Network, ca.Channel, in.action, result).Inc()
+		metrics.LoveMeter.WithLabelValues(ca.Network, ca.Channel).Observe(float64(in.loveAfter))
+	}
+
+	ca.logEvent(&cat_event.CatEvent{
+		CreatedAt:  in.at,
+		Player:     in.player,
+		Action:     in.action,
+		Outcome:    outcome,
+		LoveBefore: in.loveBefore,
+		LoveAfter:  in.loveAfter,
+		BondPoints: in.bondPoints,
+	})
+}
+
+// RecordBondPoints counts and logs BondPoints awarded to a player at love
+// outside an interaction: catbot adds the bonded progress after
+// ExecuteAction has returned.
+func (ca *CatActions) RecordBondPoints(player string, love, points int) {
+	if points <= 0 {
+		return
+	}
+	metrics.BondPointsAwarded.WithLabelValues(ca.Network, ca.Channel).Add(float64(points))
+	ca.logEvent(&cat_event.CatEvent{
+		CreatedAt:  ca.clock.Now(),
+		Player:     player,
+		Action:     cat_event.ActionBond,
+		Outcome:    cat_event.OutcomeAwarded,
+		LoveBefore: love,
+		LoveAfter:  love,
+		BondPoints: points,
+	})
+}
+
+// RecordAdminSet logs an admin's !setlove or !setbp (action) on player.
+func (ca *CatActions) RecordAdminSet(player, action string, loveBefore, loveAfter, bondPoints int) {
+	ca.logEvent(&cat_event.CatEvent{
+		CreatedAt:  ca.clock.Now(),
+		Player:     player,
+		Action:     action,
+		Outcome:    cat_event.OutcomeSet,
+		LoveBefore: loveBefore,
+		LoveAfter:  loveAfter,
+		BondPoints: bondPoints,
+	})
+}
+
+// logEvent appends e to the event log, if there is one.
+func (ca *CatActions) logEvent(e *cat_event.CatEvent) {
+	if ca.eventLog == nil {
+		return
+	}
+	e.Network, e.Channel = ca.Network, ca.Channel
+	if err := ca.eventLog.Append(context.Background(), e); err != nil {
+		ca.log.Error("failed to log interaction", logging.KeyNick, e.Player, "action", e.Action, "error", err)
+	}
+}
+
+// advanceStreak moves the player's daily bonding streak forward after an
+// accepted interaction. Like tryAwardBondPoints it never breaks the game:
+// errors just produce no extra text.
+func (ca *CatActions) advanceStreak(player string) string {
+	if ca.Streaks == nil {
+		return ""
+	}
+
+	res, err := ca.Streaks.RecordInteraction(context.Background(), player, ca.Network, ca.Channel)
+	if err != nil || !res.Advanced {
+		return ""
+	}
+
+	out := fmt.Sprintf(" 🔥 Daily streak: %d day(s)", res.Current)
+	for _, u := range res.Unlocks {
+		out += fmt.Sprintf(" :: 😸🎁 %s unlocked", u.GiftName)
+	}
+	return out
+}
+
 func (ca *CatActions) HandleStatus(sender string, args []string) string {
 	target := sender // default: self
 
@@ -3,149 +3,27 @@ package cat_actions
 import (
 	"context"
 	"strings"
-	"sync"
 	"testing"
 	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+	"github.com/MyelinBots/catbot-go/internal/services/random"
+	"github.com/MyelinBots/catbot-go/internal/services/settings"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
-// mockCatPlayerRepo is a simple in-memory mock for testing
-type mockCatPlayerRepo struct {
-	mu      sync.RWMutex
-	players map[string]*cat_player.CatPlayer
-}
-
-func newMockRepo() *mockCatPlayerRepo {
-	return &mockCatPlayerRepo{
-		players: make(map[string]*cat_player.CatPlayer),
-	}
-}
-
-func (m *mockCatPlayerRepo) key(name, network, channel string) string {
-	return strings.ToLower(name) + "|" + strings.ToLower(network) + "|" + strings.ToLower(channel)
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByID(ctx context.Context, id string) (*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByName(ctx context.Context, name, network, channel string) (*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	p := m.players[m.key(name, network, channel)]
-	return p, nil
-}
-
-func (m *mockCatPlayerRepo) GetAllPlayers(ctx context.Context, network, channel string) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) UpsertPlayer(ctx context.Context, player *cat_player.CatPlayer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(player.Name, player.Network, player.Channel)
-	if existing, ok := m.players[k]; ok {
-		player.ID = existing.ID
-	}
-	m.players[k] = player
-	return nil
-}
-
-func (m *mockCatPlayerRepo) TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetDecayAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) ListPlayersAtOrAbove(ctx context.Context, network, channel string, minLove int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) SetPerfectDropWarned(ctx context.Context, name, network, channel string, warned bool) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddBondPoints(ctx context.Context, name, network, channel string, delta int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPoints += delta
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastBondPointsAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPointStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.HighestBondStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.GiftsUnlocked |= giftMask
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.GiftsUnlocked = giftsUnlocked
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(nick, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LoveMeter = love
-	}
-	return nil
+func newPlayerRepo() cat_player.CatPlayerRepository {
+	return cat_player.NewMemoryPlayerRepository()
 }
 
 // Tests
 
 func TestNewCatActions(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	if ca == nil {
@@ -159,7 +37,7 @@ func TestNewCatActions(t *testing.T) {
 }
 
 func TestIsHere_InitiallyTrue(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	if !ca.IsHere() {
@@ -168,7 +46,7 @@ func TestIsHere_InitiallyTrue(t *testing.T) {
 }
 
 func TestEnsureHere(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Purrito starts present
@@ -200,7 +78,7 @@ func TestEnsureHere(t *testing.T) {
 }
 
 func TestGatePresenceForAction_CatnipRequiresPresence(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Force Purrito to be absent
@@ -224,7 +102,7 @@ func TestGatePresenceForAction_CatnipRequiresPresence(t *testing.T) {
 }
 
 func TestGatePresenceForAction_OtherActionsBlocked(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Force Purrito to be absent
@@ -243,7 +121,7 @@ func TestGatePresenceForAction_OtherActionsBlocked(t *testing.T) {
 }
 
 func TestGatePresenceForAction_AllowedWhenHere(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	caImpl.EnsureHere(5 * time.Minute)
@@ -258,19 +136,20 @@ func TestGatePresenceForAction_AllowedWhenHere(t *testing.T) {
 }
 
 func TestExecuteAction_NotPurrito(t *testing.T) {
-	repo := newMockRepo()
-	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
+	repo := newPlayerRepo()
+	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		WithRand(random.Sequence(1)))
 
 	result := ca.ExecuteAction("pet", "player1", "someone_else")
 
-	if !strings.Contains(result, "not purrito") && !strings.Contains(result, "confused") &&
-		!strings.Contains(result, "Wrong target") && !strings.Contains(result, "tilts") {
-		t.Errorf("expected rejection message for non-purrito target, got: %s", result)
+	want := "🐾 Someone_else tilts its head in confusion... Why are you petting Someone_else?"
+	if result != want {
+		t.Errorf("ExecuteAction(pet, someone_else) = %q, want %q", result, want)
 	}
 }
 
 func TestExecuteAction_PetWhenNotHere(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Force Purrito to be absent
@@ -284,20 +163,25 @@ func TestExecuteAction_PetWhenNotHere(t *testing.T) {
 }
 
 func TestExecuteAction_PetWhenHere(t *testing.T) {
-	repo := newMockRepo()
-	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
-	caImpl.EnsureHere(5 * time.Minute)
-
-	result := caImpl.ExecuteAction("pet", "player1", "purrito")
-
-	// Should get either accept or reject message with love meter info
-	if !strings.Contains(result, "love meter") {
-		t.Errorf("expected love meter in response, got: %s", result)
+	// rolls below the 60% accept chance are accepted, the rest rejected
+	for roll, wantLove := range map[int64]int{0: 1, 59: 1, 60: 0, 99: 0} {
+		repo := newPlayerRepo()
+		caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+			WithRand(random.Sequence(roll))).(*CatActions)
+		caImpl.EnsureHere(5 * time.Minute)
+
+		result := caImpl.ExecuteAction("pet", "player1", "purrito")
+		if !strings.Contains(result, "love meter") {
+			t.Errorf("roll %d: expected love meter in response, got: %s", roll, result)
+		}
+		if love := caImpl.LoveMeter.Get("player1"); love != wantLove {
+			t.Errorf("roll %d: love = %d, want %d", roll, love, wantLove)
+		}
 	}
 }
 
 func TestExecuteAction_LaserWhenHere(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(5 * time.Minute)
 
@@ -313,7 +197,7 @@ func TestExecuteAction_LaserWhenHere(t *testing.T) {
 }
 
 func TestExecuteAction_FeedWhenHere(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(5 * time.Minute)
 
@@ -325,7 +209,7 @@ func TestExecuteAction_FeedWhenHere(t *testing.T) {
 }
 
 func TestExecuteAction_Status(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Status doesn't require presence
@@ -337,7 +221,7 @@ func TestExecuteAction_Status(t *testing.T) {
 }
 
 func TestExecuteAction_Catnip(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Force Purrito to be absent
@@ -358,7 +242,7 @@ func TestExecuteAction_Catnip(t *testing.T) {
 }
 
 func TestExecuteAction_CatnipCooldown(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(30 * time.Minute)
 
@@ -380,7 +264,7 @@ func TestExecuteAction_CatnipCooldown(t *testing.T) {
 }
 
 func TestExecuteAction_SlapWarning(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// First slap - warning only (various warning messages contain different emojis/text)
@@ -410,7 +294,7 @@ func TestExecuteAction_SlapWarning(t *testing.T) {
 }
 
 func TestExecuteAction_UnknownAction(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	result := ca.ExecuteAction("unknown_action", "player1", "purrito")
@@ -421,7 +305,7 @@ func TestExecuteAction_UnknownAction(t *testing.T) {
 }
 
 func TestCatnipRemaining(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(30 * time.Minute)
 
@@ -445,22 +329,14 @@ func TestCatnipRemaining(t *testing.T) {
 }
 
 func TestGetRandomAction(t *testing.T) {
-	repo := newMockRepo()
-	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
+	repo := newPlayerRepo()
+	ca := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		WithRand(random.Sequence(0, 2, int64(len(emotes)-1)))).(*CatActions)
 
-	// Run multiple times to check randomness
-	seen := make(map[string]bool)
-	for i := 0; i < 100; i++ {
-		action := ca.GetRandomAction()
-		if action == "" {
-			t.Error("GetRandomAction returned empty string")
+	for _, i := range []int{0, 2, len(emotes) - 1} {
+		if got := ca.GetRandomAction(); got != emotes[i] {
+			t.Errorf("GetRandomAction() = %q, want emotes[%d] %q", got, i, emotes[i])
 		}
-		seen[action] = true
-	}
-
-	// Should see at least a few different actions
-	if len(seen) < 3 {
-		t.Errorf("expected more variation in random actions, only saw %d different actions", len(seen))
 	}
 }
 
@@ -488,7 +364,7 @@ func TestNormalizeAction(t *testing.T) {
 }
 
 func TestCatnipRequiresPresence(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Force Purrito to be absent
@@ -517,7 +393,7 @@ func TestCatnipRequiresPresence(t *testing.T) {
 }
 
 func TestCaseInsensitiveTarget(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(5 * time.Minute)
 
@@ -533,7 +409,7 @@ func TestCaseInsensitiveTarget(t *testing.T) {
 // TestCatnipIndependentCooldowns tests that User A and User B have independent
 // 24-hour cooldowns for catnip usage.
 func TestCatnipIndependentCooldowns(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Ensure Purrito is present
@@ -606,7 +482,7 @@ func TestCatnipIndependentCooldowns(t *testing.T) {
 // TestCatnipCooldownDoesNotConsumePresence tests that when catnip is rejected due to
 // cooldown, Purrito stays (exception: only successful catnip causes Purrito to leave)
 func TestCatnipCooldownDoesNotConsumePresence(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 
 	// Make Purrito present
@@ -641,7 +517,7 @@ func TestCatnipCooldownDoesNotConsumePresence(t *testing.T) {
 
 // TestCatnipCooldownNickNormalization tests that nick prefixes don't create separate cooldowns
 func TestCatnipCooldownNickNormalization(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute).(*CatActions)
 	caImpl.EnsureHere(30 * time.Minute)
 
@@ -674,3 +550,218 @@ func TestCatnipCooldownNickNormalization(t *testing.T) {
 		t.Errorf("+Player1 should share cooldown with player1, got: %s", result4)
 	}
 }
+
+func TestApplySettings(t *testing.T) {
+	repo := newPlayerRepo()
+	s := settings.Defaults()
+	s.AcceptChance = 100
+	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute, WithSettings(s)).(*CatActions)
+
+	for i := 0; i < 5; i++ {
+		caImpl.EnsureHere(5 * time.Minute)
+		caImpl.ExecuteAction("pet", "player1", "purrito")
+		caImpl.ForceAbsent()
+	}
+	if love := caImpl.LoveMeter.Get("player1"); love != 5 {
+		t.Errorf("accept chance 100 should accept every pet, love=%d", love)
+	}
+
+	s.AcceptChance = 0
+	s.CatnipCooldown = 0
+	s.DailyDecay = 0
+	s.Timezone = "Asia/Bangkok"
+	caImpl.ApplySettings(s)
+	if tz := caImpl.Calendar().Location().String(); tz != "Asia/Bangkok" {
+		t.Errorf("calendar timezone = %s", tz)
+	}
+
+	caImpl.EnsureHere(5 * time.Minute)
+	caImpl.ExecuteAction("pet", "player1", "purrito")
+	if love := caImpl.LoveMeter.Get("player1"); love != 4 {
+		t.Errorf("accept chance 0 should reject every pet, love=%d", love)
+	}
+	caImpl.ExecuteAction("catnip", "player1", "purrito")
+	if caImpl.CatnipOnCooldown("player1") {
+		t.Error("a zero cooldown should allow catnip again right away")
+	}
+
+	// decay 0 reaches the love meter: bonded players don't fade
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{Name: "bonded", Network: "testnet", Channel: "#testchan", LoveMeter: 100, LastInteractedAt: &yesterday})
+	caImpl.LoveMeter.DailyDecayAll(context.Background())
+	if love := caImpl.LoveMeter.Get("bonded"); love != 100 {
+		t.Errorf("decay 0 should keep love at 100, got %d", love)
+	}
+}
+
+func TestSpawnTiming_FakeClock(t *testing.T) {
+	repo := newPlayerRepo()
+	fake := clock.NewFake(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	// leave message #0, then a respawn delay of min + 5m
+	r := random.Sequence(0, int64(5*time.Minute))
+	caImpl := NewCatActions(repo, "testnet", "#testchan", 10*time.Minute, 20*time.Minute, 40*time.Minute,
+		WithClock(fake), WithRand(r)).(*CatActions)
+
+	fake.Advance(10*time.Minute - time.Second)
+	if !caImpl.IsHere() {
+		t.Fatal("should stay for the whole spawn window")
+	}
+	fake.Advance(2 * time.Second)
+	if caImpl.IsHere() {
+		t.Fatal("should leave after the spawn window")
+	}
+	if msg := caImpl.PopLeaveMessage(); msg != timeoutLeaveMessage(random.Sequence(0)) {
+		t.Errorf("leave message = %q", msg)
+	}
+
+	fake.Advance(25*time.Minute - time.Second)
+	if caImpl.IsHere() {
+		t.Fatal("respawned before the 25 minute delay")
+	}
+	fake.Advance(time.Second)
+	if !caImpl.IsHere() || caImpl.PopSpawnMessage() == "" {
+		t.Fatal("should respawn exactly 25 minutes after leaving")
+	}
+}
+
+func TestWaitTimes_FakeClock(t *testing.T) {
+	repo := newPlayerRepo()
+	fake := clock.NewFake(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	// a respawn delay of min + 5m
+	caImpl := NewCatActions(repo, "testnet", "#testchan", 10*time.Minute, 20*time.Minute, 40*time.Minute,
+		WithClock(fake), WithRand(random.Sequence(int64(5*time.Minute)))).(*CatActions)
+
+	fake.Advance(4 * time.Minute)
+	if msg := caImpl.ExecuteAction("status", "player1", ""); !strings.Contains(msg, "HERE\x0F (leaves in 6m 0s)") {
+		t.Errorf("status while here = %q", msg)
+	}
+
+	caImpl.DespawnAfterInteraction() // back 25 minutes from now
+	fake.Advance(9 * time.Minute)
+	if msg := caImpl.ExecuteAction("pet", "player1", "purrito"); msg != "🐾 Purrito is not here right now... he will be back in 16m 0s..." {
+		t.Errorf("pet while away = %q", msg)
+	}
+	if msg := caImpl.ExecuteAction("status", "player1", ""); !strings.Contains(msg, "AWAY\x0F (back in 16m 0s)") {
+		t.Errorf("status while away = %q", msg)
+	}
+}
+
+func TestCatnipCooldown_FakeClock(t *testing.T) {
+	repo := newPlayerRepo()
+	fake := clock.NewFake(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	caImpl := NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		WithClock(fake), WithRand(random.Sequence(0))).(*CatActions)
+
+	caImpl.ExecuteAction("catnip", "player1", "purrito")
+	if love := caImpl.LoveMeter.Get("player1"); love != 3 {
+		t.Errorf("accepted catnip should give 3 love, got %d", love)
+	}
+
+	fake.Advance(23*time.Hour + 59*time.Minute)
+	if rem := caImpl.CatnipRemaining("player1"); rem != time.Minute {
+		t.Errorf("remaining = %v, want 1m", rem)
+	}
+	fake.Advance(time.Minute)
+	if caImpl.CatnipOnCooldown("player1") {
+		t.Error("cooldown should end after exactly 24h")
+	}
+}
+
+func TestMetrics_PresenceAndInteractions(t *testing.T) {
+	repo := newPlayerRepo()
+	fake := clock.NewFake(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	caImpl := NewCatActions(repo, "testnet", "#metrics", 10*time.Minute, 20*time.Minute, 20*time.Minute,
+		WithClock(fake), WithRand(random.Sequence(0))).(*CatActions)
+
+	// the counters are process-wide, so compare against where they started
+	counters := map[string]struct {
+		c    prometheus.Counter
+		want float64
+	}{
+		"spawns":           {metrics.Spawns.WithLabelValues("testnet", "#metrics"), 1},
+		"timeout leaves":   {metrics.Leaves.WithLabelValues("testnet", "#metrics", metrics.LeaveTimeout), 1},
+		"left after a pet": {metrics.Leaves.WithLabelValues("testnet", "#metrics", metrics.LeaveInteraction), 1},
+		"accepted pets":    {metrics.Interactions.WithLabelValues("testnet", "#metrics", "pet", metrics.ResultAccepted), 1},
+		"rejected pets":    {metrics.Interactions.WithLabelValues("testnet", "#metrics", "pet", metrics.ResultRejected), 0},
+	}
+	before := make(map[string]float64, len(counters))
+	for name, c := range counters {
+		before[name] = testutil.ToFloat64(c.c)
+	}
+
+	fake.Advance(11 * time.Minute)
+	caImpl.TickPresence() // times out
+	fake.Advance(20 * time.Minute)
+	caImpl.TickPresence() // respawns
+	caImpl.ExecuteAction("pet", "player1", "purrito")
+
+	for name, c := range counters {
+		if got := testutil.ToFloat64(c.c) - before[name]; got != c.want {
+			t.Errorf("%s = %v, want %v", name, got, c.want)
+		}
+	}
+}
+
+func TestWithEventLog_RecordsInteractions(t *testing.T) {
+	repo := newPlayerRepo()
+	log := cat_event.NewMemoryCatEventRepository()
+	fake := clock.NewFake(time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC))
+	ca := NewCatActions(repo, "testnet", "#eventlog", 10*time.Minute, 20*time.Minute, 20*time.Minute,
+		WithClock(fake), WithRand(random.Sequence(99)), WithEventLog(log)).(*CatActions)
+	ca.LoveMeter.Increase("player1", 10)
+
+	ca.ExecuteAction("pet", "player1", "purrito") // roll 99: rejected
+	fake.Advance(time.Minute)
+	ca.ExecuteAction("slap", "player1", "purrito")
+	fake.Advance(time.Minute)
+	ca.ExecuteAction("kick", "player1", "purrito")
+	ca.ForceAbsent()
+	ca.ExecuteAction("feed", "player1", "purrito") // not here: nothing happened, nothing logged
+
+	got, err := log.History(context.Background(), "testnet", "#eventlog", cat_event.Query{Player: "player1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		action, outcome   string
+		before, after     int
+		minutesAfterStart int
+	}{
+		{"kick", cat_event.OutcomePunished, 9, 8, 2},
+		{"slap", cat_event.OutcomeWarned, 9, 9, 1},
+		{"pet", cat_event.OutcomeRejected, 10, 9, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(got), len(want), got)
+	}
+	start := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for i, w := range want {
+		e := got[i]
+		if e.Action != w.action || e.Outcome != w.outcome || e.LoveBefore != w.before || e.LoveAfter != w.after ||
+			!e.CreatedAt.Equal(start.Add(time.Duration(w.minutesAfterStart)*time.Minute)) {
+			t.Errorf("event %d = %+v, want %+v", i, e, w)
+		}
+	}
+}
+
+func TestWithEventLog_BondPointsAwarded(t *testing.T) {
+	repo := newPlayerRepo()
+	log := cat_event.NewMemoryCatEventRepository()
+	ca := NewCatActions(repo, "testnet", "#eventlog", 10*time.Minute, 20*time.Minute, 20*time.Minute,
+		WithRand(random.Sequence(0)), WithEventLog(log)).(*CatActions)
+	// a Forever Human earns BondPoints on their first accepted interaction of the day
+	_ = repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
+		Name: "player1", Network: "testnet", Channel: "#eventlog", LoveMeter: 100, HighestStreak: 100,
+	})
+
+	ca.ExecuteAction("pet", "player1", "purrito") // roll 0: accepted
+
+	got, _ := log.History(context.Background(), "testnet", "#eventlog", cat_event.Query{})
+	if len(got) != 1 || got[0].Outcome != cat_event.OutcomeAccepted || got[0].LoveBefore != 100 || got[0].LoveAfter != 100 {
+		t.Fatalf("unexpected events %+v", got)
+	}
+	p, _ := repo.GetPlayerByName(context.Background(), "player1", "testnet", "#eventlog")
+	if p == nil || p.BondPoints == 0 || got[0].BondPoints != p.BondPoints {
+		t.Errorf("bond points: event %d, player %+v", got[0].BondPoints, p)
+	}
+}
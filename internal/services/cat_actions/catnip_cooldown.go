@@ -6,13 +6,18 @@ import (
 	"time"
 )
 
+// catnipCooldown is the default; channels can change it with !purrito set.
 const catnipCooldown = 24 * time.Hour
 
 func remainingCatnip(now, lastUsed time.Time) time.Duration {
+	return remainingCatnipAfter(now, lastUsed, catnipCooldown)
+}
+
+func remainingCatnipAfter(now, lastUsed time.Time, cooldown time.Duration) time.Duration {
 	if lastUsed.IsZero() {
 		return 0
 	}
-	next := lastUsed.Add(catnipCooldown)
+	next := lastUsed.Add(cooldown)
 	if now.Before(next) {
 		return next.Sub(now)
 	}
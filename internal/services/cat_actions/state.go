@@ -0,0 +1,171 @@
+package cat_actions
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+)
+
+// --------------------
+// Persisted channel state
+// Presence timers, catnip cooldowns and slap warnings are mirrored to the
+// channel_state repository so they survive restarts. A change is snapshotted
+// under mu, right after the in-memory change, and written once mu is released
+// (ca.unlock), so a slow database never stalls presence ticks or commands.
+// Every snapshot is numbered; a write older than the last one stored for the
+// same key is dropped, so the stored copy never goes back in time.
+// Storage errors are logged; the game keeps running on the in-memory state.
+// --------------------
+
+// stateWrite is one snapshot waiting to be stored.
+type stateWrite struct {
+	seq   uint64
+	key   string // "presence", "catnip|<nick>" or "slap|<nick>"
+	write func(ctx context.Context) error
+	fail  func(err error)
+}
+
+// stateWriter orders the writes of one channel.
+type stateWriter struct {
+	pending []stateWrite // guarded by CatActions.mu
+	seq     uint64       // guarded by CatActions.mu
+
+	mu     sync.Mutex // serializes writes
+	stored map[string]uint64
+}
+
+// queueLocked snapshots a write. Caller holds mu.
+func (ca *CatActions) queueLocked(key string, write func(ctx context.Context) error, fail func(err error)) {
+	ca.writes.seq++
+	ca.writes.pending = append(ca.writes.pending, stateWrite{seq: ca.writes.seq, key: key, write: write, fail: fail})
+}
+
+// unlock releases mu, then stores what was queued while it was held.
+func (ca *CatActions) unlock() {
+	pending := ca.writes.pending
+	ca.writes.pending = nil
+	ca.mu.Unlock()
+
+	ca.flush(pending)
+}
+
+func (ca *CatActions) flush(pending []stateWrite) {
+	if len(pending) == 0 {
+		return
+	}
+	w := &ca.writes
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.stored == nil {
+		w.stored = make(map[string]uint64)
+	}
+	for _, sw := range pending {
+		if sw.seq <= w.stored[sw.key] {
+			continue // a newer snapshot is already stored
+		}
+		w.stored[sw.key] = sw.seq
+		if err := sw.write(context.Background()); err != nil {
+			sw.fail(err)
+		}
+	}
+}
+
+// loadState restores the channel's state. It returns false when there is no
+// repository or the channel has never been saved (fresh start).
+func (ca *CatActions) loadState() bool {
+	if ca.state == nil {
+		return false
+	}
+	ctx := context.Background()
+
+	players, err := ca.state.GetPlayerStates(ctx, ca.Network, ca.Channel)
+	if err != nil {
+		ca.log.Error("failed to load player state", "error", err)
+	}
+	for _, p := range players {
+		if p.CatnipUsedAt != nil {
+			ca.catnipUsedAt[p.Name] = *p.CatnipUsedAt
+		}
+		if p.SlapWarned {
+			ca.slapWarned[p.Name] = true
+		}
+	}
+
+	s, err := ca.state.GetChannelState(ctx, ca.Network, ca.Channel)
+	if err != nil {
+		ca.log.Error("failed to load presence", "error", err)
+		return false
+	}
+	if s == nil {
+		return false
+	}
+	if s.PresentUntil != nil {
+		ca.presentUntil = *s.PresentUntil
+	}
+	if s.NextSpawnAt != nil {
+		ca.nextSpawnAt = *s.NextSpawnAt
+	}
+	return true
+}
+
+// savePresenceLocked queues presentUntil and nextSpawnAt. Caller holds mu
+// and releases it with ca.unlock.
+func (ca *CatActions) savePresenceLocked() {
+	if ca.state == nil {
+		return
+	}
+	until, next := ca.presentUntil, ca.nextSpawnAt
+	ca.queueLocked("presence", func(ctx context.Context) error {
+		return ca.state.SetPresence(ctx, ca.Network, ca.Channel, until, next)
+	}, func(err error) {
+		ca.log.Error("failed to save presence", "error", err)
+	})
+}
+
+// saveCatnipLocked queues when key last used catnip. Caller holds mu and
+// releases it with ca.unlock.
+func (ca *CatActions) saveCatnipLocked(key string) {
+	if ca.state == nil {
+		return
+	}
+	usedAt := ca.catnipUsedAt[key]
+	ca.queueLocked("catnip|"+key, func(ctx context.Context) error {
+		return ca.state.SetCatnipUsedAt(ctx, key, ca.Network, ca.Channel, usedAt)
+	}, func(err error) {
+		ca.log.Error("failed to save catnip cooldown", logging.KeyNick, key, "error", err)
+	})
+}
+
+// saveSlapWarnedLocked queues key's slap warning. Caller holds mu and
+// releases it with ca.unlock.
+func (ca *CatActions) saveSlapWarnedLocked(key string) {
+	if ca.state == nil {
+		return
+	}
+	warned := ca.slapWarned[key]
+	ca.queueLocked("slap|"+key, func(ctx context.Context) error {
+		return ca.state.SetSlapWarned(ctx, key, ca.Network, ca.Channel, warned)
+	}, func(err error) {
+		ca.log.Error("failed to save slap warning", logging.KeyNick, key, "error", err)
+	})
+}
+
+// ResetPlayer forgets player's catnip cooldown and slap warning (admin !reset).
+func (ca *CatActions) ResetPlayer(player string) {
+	ca.mu.Lock()
+	defer ca.unlock()
+
+	keys := []string{normalizeNick(player)}
+	if k := strings.ToLower(strings.TrimSpace(player)); k != keys[0] {
+		keys = append(keys, k)
+	}
+	for _, key := range keys {
+		delete(ca.catnipUsedAt, key)
+		delete(ca.slapWarned, key)
+		ca.saveCatnipLocked(key)
+		ca.saveSlapWarnedLocked(key)
+	}
+}
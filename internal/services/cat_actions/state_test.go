@@ -0,0 +1,122 @@
+package cat_actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_state"
+)
+
+// restart builds a fresh CatActions over the same repositories, like a redeploy would.
+func restart(repo cat_player.CatPlayerRepository, state channel_state.ChannelStateRepository) *CatActions {
+	return NewCatActions(repo, "testnet", "#testchan", 30*time.Minute, time.Hour, time.Hour, WithStateRepository(state)).(*CatActions)
+}
+
+func TestState_FirstRunStartsPresentAndSaves(t *testing.T) {
+	state := channel_state.NewMemoryChannelStateRepository()
+	ca := restart(newPlayerRepo(), state)
+
+	if !ca.IsHere() {
+		t.Fatal("a channel without saved state should start with Purrito present")
+	}
+	s, _ := state.GetChannelState(context.Background(), "testnet", "#testchan")
+	if s == nil || s.PresentUntil == nil {
+		t.Fatalf("initial presence should be saved, got %+v", s)
+	}
+}
+
+func TestState_PresenceSurvivesRestart(t *testing.T) {
+	repo, state := newPlayerRepo(), channel_state.NewMemoryChannelStateRepository()
+
+	ca := restart(repo, state)
+	ca.DespawnAfterInteraction()
+	next := ca.nextSpawnAt
+
+	ca = restart(repo, state)
+	if ca.IsHere() {
+		t.Error("Purrito left before the restart and should not reappear instantly")
+	}
+	if !ca.nextSpawnAt.Equal(next) {
+		t.Errorf("respawn timer = %v, want %v", ca.nextSpawnAt, next)
+	}
+}
+
+func TestState_CatnipCooldownAndSlapWarningSurviveRestart(t *testing.T) {
+	repo, state := newPlayerRepo(), channel_state.NewMemoryChannelStateRepository()
+
+	ca := restart(repo, state)
+	ca.ExecuteAction("catnip", "Alice", "purrito")
+	ca.ExecuteAction("slap", "Bob", "purrito")
+
+	ca = restart(repo, state)
+	if !ca.CatnipOnCooldown("alice") {
+		t.Error("catnip cooldown should be restored")
+	}
+
+	ca.EnsureHere(5 * time.Minute)
+	if got := ca.ExecuteAction("slap", "bob", "purrito"); !strings.Contains(got, "love meter decreased") {
+		t.Errorf("bob was already warned, the second slap should punish: %s", got)
+	}
+}
+
+func TestState_NilRepositoryKeepsMemoryOnly(t *testing.T) {
+	ca := NewCatActions(newPlayerRepo(), "testnet", "#testchan", 30*time.Minute, time.Hour, time.Hour, WithStateRepository(nil)).(*CatActions)
+	ca.DespawnAfterInteraction()
+	ca.ExecuteAction("slap", "bob", "purrito")
+
+	if ca.IsHere() || !ca.slapWarned["bob"] {
+		t.Error("state should still be tracked in memory without a repository")
+	}
+}
+
+// slowState blocks SetPresence until release is closed.
+type slowState struct {
+	channel_state.ChannelStateRepository
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *slowState) SetPresence(ctx context.Context, network, channel string, presentUntil, nextSpawnAt time.Time) error {
+	select {
+	case s.entered <- struct{}{}:
+	default:
+	}
+	<-s.release
+	return s.ChannelStateRepository.SetPresence(ctx, network, channel, presentUntil, nextSpawnAt)
+}
+
+func TestState_SlowDatabaseDoesNotHoldTheLock(t *testing.T) {
+	mem := channel_state.NewMemoryChannelStateRepository()
+	ca := restart(newPlayerRepo(), mem) // first presence saved before the slow repo is in place
+	slow := &slowState{ChannelStateRepository: mem, entered: make(chan struct{}, 1), release: make(chan struct{})}
+	ca.state = slow
+
+	done := make(chan struct{})
+	go func() {
+		ca.DespawnAfterInteraction()
+		close(done)
+	}()
+	<-slow.entered
+
+	// the write is stuck in the database, the game isn't
+	here := make(chan bool)
+	go func() { here <- ca.IsHere() }()
+	select {
+	case got := <-here:
+		if got {
+			t.Error("Purrito should be gone after the interaction")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("IsHere blocked behind a slow presence write")
+	}
+
+	close(slow.release)
+	<-done
+	s, _ := mem.GetChannelState(context.Background(), "testnet", "#testchan")
+	if s == nil || s.PresentUntil != nil || s.NextSpawnAt == nil {
+		t.Errorf("the despawn should be stored once the database answers, got %+v", s)
+	}
+}
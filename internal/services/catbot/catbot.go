@@ -3,16 +3,19 @@ package catbot
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/logging"
 	"github.com/MyelinBots/catbot-go/internal/services/bondpoints"
 	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
 	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
 	"github.com/MyelinBots/catbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/catbot-go/internal/services/scheduler"
 )
 
 // --------------------------------------------------
@@ -21,10 +24,11 @@ import (
 
 type IRCClient interface {
 	Privmsg(channel, message string)
+	Notice(target, message string)
 }
 
-type dailyDecayerWithWarning interface {
-	DailyDecayWithWarning(ctx context.Context) ([]string, error)
+type dayDecayer interface {
+	DecayDay(ctx context.Context, day time.Time) ([]string, error)
 }
 type dailyDecayer interface {
 	DailyDecayAll(ctx context.Context) error
@@ -51,6 +55,10 @@ type CatBot struct {
 
 	// bonded endgame
 	BondPoints bondpoints.Service
+
+	clock clock.Clock
+	decay *scheduler.Daily
+	log   *slog.Logger
 }
 
 // --------------------------------------------------
@@ -62,14 +70,24 @@ func NewCatBot(
 	catPlayerRepo cat_player.CatPlayerRepository,
 	network, channel string,
 	spawnWindow, minRespawn, maxRespawn time.Duration,
+	opts ...cat_actions.Option,
 ) *CatBot {
 	cb := &CatBot{
 		IrcClient:     client,
-		CatActions:    cat_actions.NewCatActions(catPlayerRepo, network, channel, spawnWindow, minRespawn, maxRespawn),
+		CatActions:    cat_actions.NewCatActions(catPlayerRepo, network, channel, spawnWindow, minRespawn, maxRespawn, opts...),
 		Channel:       channel,
 		Network:       network,
 		CatPlayerRepo: catPlayerRepo,
-		BondPoints:    bondpoints.New(catPlayerRepo),
+		clock:         clock.Real{},
+		log:           slog.Default().With(logging.KeyNetwork, network, logging.KeyChannel, channel),
+	}
+	// share the channel's BondPoints and clock so both agree on the time
+	if ca, ok := cb.CatActions.(*cat_actions.CatActions); ok {
+		cb.BondPoints = ca.BondPoints
+		cb.clock = ca.Clock()
+		cb.log = ca.Logger()
+	} else {
+		cb.BondPoints = bondpoints.New(catPlayerRepo)
 	}
 	return cb
 }
@@ -82,8 +100,9 @@ func (cb *CatBot) ConsumePresence() bool {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
-	if time.Now().Before(cb.presentUntil) {
-		cb.presentUntil = time.Now()
+	now := cb.clock.Now()
+	if now.Before(cb.presentUntil) {
+		cb.presentUntil = now
 		cb.interacted = true
 		return true
 	}
@@ -93,9 +112,12 @@ func (cb *CatBot) ConsumePresence() bool {
 func (cb *CatBot) IsPresent() bool {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
-	return time.Now().Before(cb.presentUntil)
+	return cb.clock.Now().Before(cb.presentUntil)
 }
 
+// Logger is the channel's logger (tagged with network and channel).
+func (cb *CatBot) Logger() *slog.Logger { return cb.log }
+
 func (cb *CatBot) AppearTimes() (last, next time.Time) {
 	cb.mu.RLock()
 	defer cb.mu.RUnlock()
@@ -148,8 +170,14 @@ func (cb *CatBot) HandleCatCommand(ctx context.Context, args ...string) error {
 // --------------------------------------------------
 
 func (cb *CatBot) Start(ctx context.Context) {
-	decayTicker := time.NewTicker(24 * time.Hour)
-	defer decayTicker.Stop()
+	if cb.decay == nil {
+		if err := cb.ScheduleDecay(""); err != nil {
+			cb.log.Error("daily decay disabled", "error", err)
+		}
+	}
+	if cb.decay != nil {
+		go cb.decay.Start(ctx)
+	}
 
 	// Presence ticker checks for spawn/leave messages every 10 seconds
 	presenceTicker := time.NewTicker(10 * time.Second)
@@ -171,26 +199,41 @@ func (cb *CatBot) Start(ctx context.Context) {
 					cb.IrcClient.Privmsg(cb.Channel, spawnMsg)
 				}
 			}
+		}
+	}
+}
 
-		case <-decayTicker.C:
-			if ca, ok := cb.CatActions.(*cat_actions.CatActions); ok {
-				if d, ok := any(ca.LoveMeter).(dailyDecayerWithWarning); ok {
-					msgs, err := d.DailyDecayWithWarning(context.Background())
-					if err == nil {
-						for _, m := range msgs {
-							cb.IrcClient.Privmsg(cb.Channel, m)
-						}
-					}
-					continue
-				}
-				if d, ok := any(ca.LoveMeter).(dailyDecayer); ok {
-					if err := d.DailyDecayAll(context.Background()); err != nil {
-						log.Printf("daily decay error: %v", err)
-					}
-				}
+// ScheduleDecay runs the daily love decay at at ("HH:MM", "" = midnight) in
+// the channel's game timezone. Each run decays the game day that just ended;
+// Start catches up every run missed while the bot was down.
+func (cb *CatBot) ScheduleDecay(at string, opts ...scheduler.Option) error {
+	ca, ok := cb.CatActions.(*cat_actions.CatActions)
+	if !ok {
+		return fmt.Errorf("no love meter to decay")
+	}
+	cal := ca.Calendar()
+	name := fmt.Sprintf("decay/%s/%s", strings.ToLower(cb.Network), strings.ToLower(cb.Channel))
+
+	opts = append([]scheduler.Option{scheduler.WithLogger(cb.log)}, opts...)
+	d, err := scheduler.NewDaily(name, at, cal, func(ctx context.Context, due time.Time) error {
+		day := cal.StartOfDay(due).Add(-time.Nanosecond)
+		if d, ok := any(ca.LoveMeter).(dayDecayer); ok {
+			msgs, err := d.DecayDay(ctx, day)
+			for _, m := range msgs {
+				cb.IrcClient.Privmsg(cb.Channel, m)
 			}
+			return err
 		}
+		if d, ok := any(ca.LoveMeter).(dailyDecayer); ok {
+			return d.DailyDecayAll(ctx)
+		}
+		return nil
+	}, opts...)
+	if err != nil {
+		return err
 	}
+	cb.decay = d
+	return nil
 }
 
 // --------------------------------------------------
@@ -236,6 +279,7 @@ func (cb *CatBot) appendBondProgress(ctx context.Context, nick string, msg strin
 	if err != nil {
 		return msg
 	}
+	ca.RecordBondPoints(normalizedNick, 100, res.AwardedPoints)
 
 	unlocks := bondrewards.GiftUnlocks(oldHighest, res.HighestStreak)
 	if len(unlocks) > 0 {
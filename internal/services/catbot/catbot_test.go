@@ -7,11 +7,18 @@ import (
 	"testing"
 	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
 	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
 	"github.com/MyelinBots/catbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/catbot-go/internal/services/random"
 )
 
+func newPlayerRepo() cat_player.CatPlayerRepository {
+	return cat_player.NewMemoryPlayerRepository()
+}
+
 // mockIRCClient records messages sent
 type mockIRCClient struct {
 	mu       sync.Mutex
@@ -24,6 +31,12 @@ func (m *mockIRCClient) Privmsg(channel, message string) {
 	m.messages = append(m.messages, message)
 }
 
+func (m *mockIRCClient) Notice(target, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+}
+
 func (m *mockIRCClient) LastMessage() string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -39,132 +52,11 @@ func (m *mockIRCClient) Clear() {
 	m.messages = nil
 }
 
-// mockCatPlayerRepo is a simple in-memory mock
-type mockCatPlayerRepo struct {
-	mu      sync.RWMutex
-	players map[string]*cat_player.CatPlayer
-}
-
-func newMockRepo() *mockCatPlayerRepo {
-	return &mockCatPlayerRepo{
-		players: make(map[string]*cat_player.CatPlayer),
-	}
-}
-
-func (m *mockCatPlayerRepo) key(name, network, channel string) string {
-	return strings.ToLower(name) + "|" + strings.ToLower(network) + "|" + strings.ToLower(channel)
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByID(ctx context.Context, id string) (*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByName(ctx context.Context, name, network, channel string) (*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	p := m.players[m.key(name, network, channel)]
-	if p != nil {
-		cp := *p
-		return &cp, nil
-	}
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetAllPlayers(ctx context.Context, network, channel string) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) UpsertPlayer(ctx context.Context, player *cat_player.CatPlayer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(player.Name, player.Network, player.Channel)
-	m.players[k] = player
-	return nil
-}
-
-func (m *mockCatPlayerRepo) TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetDecayAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) ListPlayersAtOrAbove(ctx context.Context, network, channel string, minLove int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) SetPerfectDropWarned(ctx context.Context, name, network, channel string, warned bool) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddBondPoints(ctx context.Context, name, network, channel string, delta int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPoints += delta
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastBondPointsAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPointStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.HighestBondStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(nick, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LoveMeter = love
-	}
-	return nil
-}
-
 // Tests
 
 func TestNewCatBot(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
@@ -181,7 +73,7 @@ func TestNewCatBot(t *testing.T) {
 
 func TestIsPresent_InitiallyFalse(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	if cb.IsPresent() {
@@ -191,7 +83,7 @@ func TestIsPresent_InitiallyFalse(t *testing.T) {
 
 func TestConsumePresence_WhenNotPresent(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	consumed := cb.ConsumePresence()
@@ -202,7 +94,7 @@ func TestConsumePresence_WhenNotPresent(t *testing.T) {
 
 func TestConsumePresence_WhenPresent(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Simulate presence
@@ -223,7 +115,7 @@ func TestConsumePresence_WhenPresent(t *testing.T) {
 
 func TestAppearTimes(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	now := time.Now()
@@ -245,7 +137,7 @@ func TestAppearTimes(t *testing.T) {
 
 func TestHandleCatCommand_NoArgs(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -264,7 +156,7 @@ func TestHandleCatCommand_NoArgs(t *testing.T) {
 
 func TestHandleCatCommand_InsufficientArgs(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -283,7 +175,7 @@ func TestHandleCatCommand_InsufficientArgs(t *testing.T) {
 
 func TestHandleCatCommand_PetWhenNotHere(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Force Purrito to be absent
@@ -306,7 +198,7 @@ func TestHandleCatCommand_PetWhenNotHere(t *testing.T) {
 
 func TestHandleCatCommand_PetWhenHere(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Make Purrito present via CatActions
@@ -329,7 +221,7 @@ func TestHandleCatCommand_PetWhenHere(t *testing.T) {
 
 func TestHandleCatCommand_Status(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -349,7 +241,7 @@ func TestHandleCatCommand_Status(t *testing.T) {
 
 func TestHandleCatCommand_Catnip(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -401,7 +293,7 @@ func TestNormalizeNick(t *testing.T) {
 
 func TestAppendBondProgress_NotBonded(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -425,7 +317,7 @@ func TestAppendBondProgress_NotBonded(t *testing.T) {
 
 func TestAppendBondProgress_Bonded(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -449,7 +341,7 @@ func TestAppendBondProgress_Bonded(t *testing.T) {
 
 func TestHandleCatCommand_Feed(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Make Purrito present via CatActions
@@ -472,7 +364,7 @@ func TestHandleCatCommand_Feed(t *testing.T) {
 
 func TestHandleCatCommand_Love(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ca := cb.CatActions.(*cat_actions.CatActions)
@@ -494,7 +386,7 @@ func TestHandleCatCommand_Love(t *testing.T) {
 
 func TestHandleCatCommand_Laser(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ca := cb.CatActions.(*cat_actions.CatActions)
@@ -516,7 +408,7 @@ func TestHandleCatCommand_Laser(t *testing.T) {
 
 func TestHandleCatCommand_Slap(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -537,7 +429,7 @@ func TestHandleCatCommand_Slap(t *testing.T) {
 
 func TestHandleCatCommand_NotPurrito(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ca := cb.CatActions.(*cat_actions.CatActions)
@@ -560,7 +452,7 @@ func TestHandleCatCommand_NotPurrito(t *testing.T) {
 
 func TestHandleCatCommand_UnknownAction(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ca := cb.CatActions.(*cat_actions.CatActions)
@@ -582,17 +474,21 @@ func TestHandleCatCommand_UnknownAction(t *testing.T) {
 
 func TestHandleCatCommand_BondedPlayer(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
-	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
+	repo := newPlayerRepo()
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	// every roll is 0: the pet is accepted with the first emote
+	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		cat_actions.WithClock(clock.NewFake(now)), cat_actions.WithRand(random.Sequence(0)))
 
 	ctx := context.Background()
 
 	// Setup bonded player
 	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
-		Name:      "player1",
-		Network:   "testnet",
-		Channel:   "#testchan",
-		LoveMeter: 100,
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		LoveMeter:     100,
+		HighestStreak: 100,
 	})
 
 	// Purrito starts present (no need for EnsureHere)
@@ -604,23 +500,56 @@ func TestHandleCatCommand_BondedPlayer(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	msg := client.LastMessage()
-	// Pet has 60% accept / 40% reject due to randomness.
-	// On accept: love stays at 100 (capped), bond progress is appended.
-	// On reject: love drops to 99, bond progress is skipped.
-	if strings.Contains(msg, "not here") {
-		t.Errorf("should not get 'not here' when purrito is present, got %q", msg)
-	}
-	if strings.Contains(msg, "100%") {
-		if !strings.Contains(msg, "Streak") || !strings.Contains(msg, "BondPoints") {
-			t.Errorf("expected bond progress when love is still 100%%, got %q", msg)
-		}
+	want := "meows happily (=^･^=) at player1 and your love meter is now 100% and purrito is now \x0306loves you 😻\x0f [❤️✨❤️✨❤️✨❤️✨❤️]" +
+		" ✨ +2 BondPoints (Total: 2 ::: BP Streak: 1) 🔥 Daily streak: 1 day(s)" +
+		" :: Streak: 1 day(s) :: already bonded today :: Total: 2 :: Title: \x0309Just Met Purrito 🐾\x0f"
+	if msg := client.LastMessage(); msg != want {
+		t.Errorf("!pet purrito = %q, want %q", msg, want)
+	}
+}
+
+func TestHandleCatCommand_BondPointsAreLogged(t *testing.T) {
+	client := &mockIRCClient{}
+	repo := newPlayerRepo()
+	log := cat_event.NewMemoryCatEventRepository()
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		cat_actions.WithClock(clock.NewFake(now)), cat_actions.WithRand(random.Sequence(0)), cat_actions.WithEventLog(log))
+
+	ctx := context.Background()
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		LoveMeter:     100,
+		HighestStreak: 100,
+	})
+
+	// feed awards nothing itself; the bonded progress added afterwards does
+	if err := cb.HandleCatCommand(context_manager.SetNickContext(ctx, "player1"), "!feed purrito"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := log.History(ctx, "testnet", "#testchan", cat_event.Query{Player: "player1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(got), got)
+	}
+	// same time, newest (appended last) first
+	bond, feed := got[0], got[1]
+	if bond.Action != cat_event.ActionBond || bond.Outcome != cat_event.OutcomeAwarded || bond.BondPoints != 2 || !bond.CreatedAt.Equal(now) {
+		t.Errorf("bond event = %+v", bond)
+	}
+	if feed.Action != "feed" || feed.Outcome != cat_event.OutcomeAccepted || feed.LoveBefore != 100 || feed.LoveAfter != 100 || feed.BondPoints != 0 {
+		t.Errorf("feed event = %+v", feed)
 	}
 }
 
 func TestAppendBondProgress_NilCatActions(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Replace CatActions with nil-like behavior
@@ -645,7 +574,7 @@ func TestAppendBondProgress_NilCatActions(t *testing.T) {
 
 func TestAppendBondProgress_WithGiftUnlock(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ctx := context.Background()
@@ -673,7 +602,7 @@ func TestAppendBondProgress_WithGiftUnlock(t *testing.T) {
 
 func TestHandleCatCommand_WithActionPrefix(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	ca := cb.CatActions.(*cat_actions.CatActions)
@@ -696,7 +625,7 @@ func TestHandleCatCommand_WithActionPrefix(t *testing.T) {
 
 func TestHandleCatCommand_ActionsRequirePresence(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Force Purrito to be absent
@@ -749,7 +678,7 @@ func TestNormalizeNick_OnlyPrefixes(t *testing.T) {
 
 func TestStart_QuickCancel(t *testing.T) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 
 	// Use a context that cancels quickly
@@ -774,3 +703,37 @@ func TestStart_QuickCancel(t *testing.T) {
 	_ = client // Start exits cleanly, no messages expected in short timeout
 }
 
+func TestScheduleDecay_DecaysTheDayThatEnded(t *testing.T) {
+	ctx := context.Background()
+	repo := cat_player.NewMemoryPlayerRepository()
+	client := &mockIRCClient{}
+
+	// Jan 16 00:00 in New York
+	fake := clock.NewFake(time.Date(2024, 1, 16, 5, 0, 0, 0, time.UTC))
+	cb := NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		cat_actions.WithClock(fake))
+
+	lastVisit := fake.Now().AddDate(0, 0, -2)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name: "player1", Network: "testnet", Channel: "#testchan",
+		LoveMeter: 100, LastInteractedAt: &lastVisit,
+	})
+
+	if err := cb.ScheduleDecay("bogus"); err == nil {
+		t.Error("invalid decay time should be rejected")
+	}
+	if err := cb.ScheduleDecay("00:00"); err != nil {
+		t.Fatal(err)
+	}
+	if ran, err := cb.decay.RunDue(ctx, cb.decay.Last(fake.Now())); !ran || err != nil {
+		t.Fatalf("ran=%v err=%v", ran, err)
+	}
+
+	p, _ := repo.GetPlayerByName(ctx, "player1", "testnet", "#testchan")
+	if p == nil || p.LoveMeter != 95 {
+		t.Fatalf("expected love 95 after skipping Jan 15, got %+v", p)
+	}
+	if msg := client.LastMessage(); !strings.Contains(msg, "player1 did not come yesterday") {
+		t.Errorf("expected the fade warning, got %q", msg)
+	}
+}
@@ -0,0 +1,270 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	"github.com/MyelinBots/catbot-go/internal/services/lovemeter"
+)
+
+// --------------------------------------------------
+// Admin commands
+// Need PrivilegeAdmin (see WithAdmin), are hidden from !purrito and act on the
+// channel they are used in. Replies (and failures, see renderError) go to the
+// admin as a NOTICE; every use is audit-logged by HandleCommand.
+// --------------------------------------------------
+
+// ChannelConn joins and parts channels (*irc.Conn satisfies it).
+type ChannelConn interface {
+	Join(channel string, key ...string)
+	Part(channel string, message ...string)
+}
+
+type AdminDeps struct {
+	Conn   ChannelConn
+	Out    catbot.IRCClient // replies and !say
+	ACL    *admin.ACL       // for !ban / !unban
+	Reload func() error     // re-reads the ACL from config, nil disables !reload
+}
+
+// RegisterAdmin registers join, part, say, reload, forcespawn, despawn,
+// setlove, setbp, reset, ban and unban.
+func (c *CommandControllerImpl) RegisterAdmin(deps AdminDeps) {
+	a := &adminCommands{c: c, deps: deps}
+
+	for _, cmd := range []Command{
+		{Name: "join", Usage: "!join #channel", Params: []Param{{Name: "#channel", Type: ArgChannel}}, Handler: a.join},
+		{Name: "part", Usage: "!part [#channel] [reason]", Params: []Param{{Name: "#channel", Type: ArgChannel, Optional: true}, {Name: "reason", Type: ArgText, Optional: true}}, Handler: a.part},
+		{Name: "say", Usage: "!say <#channel|nick> <text>", Params: []Param{{Name: "target"}, {Name: "text", Type: ArgText}}, Handler: a.say},
+		{Name: "reload", Usage: "!reload", Params: []Param{}, Handler: a.reload},
+		{Name: "forcespawn", Usage: "!forcespawn", Params: []Param{}, Handler: a.forceSpawn},
+		{Name: "despawn", Usage: "!despawn", Params: []Param{}, Handler: a.despawn},
+		{Name: "setlove", Usage: "!setlove <nick> <0-100>", Params: []Param{{Name: "nick", Type: ArgNick}, {Name: "love", Type: ArgInt}}, Handler: a.setLove},
+		{Name: "setbp", Aliases: []string{"setbondpoints"}, Usage: "!setbp <nick> <points>", Params: []Param{{Name: "nick", Type: ArgNick}, {Name: "points", Type: ArgInt}}, Handler: a.setBondPoints},
+		{Name: "reset", Usage: "!reset <nick>", Params: []Param{{Name: "nick", Type: ArgNick}}, Handler: a.reset},
+		{Name: "ban", Usage: "!ban [nick|mask]", Params: []Param{{Name: "mask", Optional: true}}, Handler: a.ban},
+		{Name: "unban", Usage: "!unban <nick|mask>", Params: []Param{{Name: "mask"}}, Handler: a.unban},
+	} {
+		cmd.Privilege = PrivilegeAdmin
+		cmd.Hidden = true
+		c.Register(cmd)
+	}
+}
+
+type adminCommands struct {
+	c    *CommandControllerImpl
+	deps AdminDeps
+}
+
+var errNoGame = errors.New("no game in this channel")
+
+func (a *adminCommands) reply(inv *Invocation, format string, args ...any) {
+	out := a.deps.Out
+	if out == nil {
+		out = a.c.replyClient()
+	}
+	out.Notice(inv.Nick, fmt.Sprintf(format, args...))
+}
+
+func (a *adminCommands) catActions() (*cat_actions.CatActions, error) {
+	ca, ok := a.c.game.CatActions.(*cat_actions.CatActions)
+	if !ok {
+		return nil, errNoGame
+	}
+	return ca, nil
+}
+
+/* CHANNELS */
+
+func (a *adminCommands) join(_ context.Context, inv *Invocation) error {
+	channel := inv.Args.String(0)
+	a.deps.Conn.Join(channel)
+	a.reply(inv, "✅ joining %s", channel)
+	return nil
+}
+
+func (a *adminCommands) part(_ context.Context, inv *Invocation) error {
+	channel, reason := inv.Args.String(0), inv.Args.String(1)
+	if channel == "" {
+		channel = inv.Channel
+	}
+	if reason == "" {
+		reason = "Purrito wanders off 🐾"
+	}
+	a.deps.Conn.Part(channel, reason)
+	a.reply(inv, "✅ leaving %s", channel)
+	return nil
+}
+
+func (a *adminCommands) say(_ context.Context, inv *Invocation) error {
+	out := a.deps.Out
+	if out == nil {
+		out = a.c.game.IrcClient
+	}
+	out.Privmsg(inv.Args.String(0), inv.Args.String(1))
+	return nil
+}
+
+func (a *adminCommands) reload(_ context.Context, inv *Invocation) error {
+	if a.deps.Reload == nil {
+		return errors.New("reload not configured")
+	}
+	if err := a.deps.Reload(); err != nil {
+		return err
+	}
+	a.reply(inv, "✅ admin list reloaded")
+	return nil
+}
+
+/* PRESENCE */
+
+func (a *adminCommands) forceSpawn(_ context.Context, inv *Invocation) error {
+	ca, err := a.catActions()
+	if err != nil {
+		return err
+	}
+	// clear any pending respawn first, EnsureHere respects it otherwise
+	ca.ForceAbsent()
+	ca.EnsureHere(0)
+
+	a.c.game.IrcClient.Privmsg(a.c.game.Channel, "🐈 meowww ... Purrito pops out of nowhere (=^･ω･^=)")
+	return nil
+}
+
+// despawn sends Purrito away and starts the normal respawn timer
+// (ForceAbsent alone would keep him away until the next !forcespawn).
+func (a *adminCommands) despawn(_ context.Context, inv *Invocation) error {
+	ca, err := a.catActions()
+	if err != nil {
+		return err
+	}
+	ca.DespawnAfterInteraction()
+	a.reply(inv, "✅ Purrito left %s", a.c.game.Channel)
+	return nil
+}
+
+/* PLAYERS */
+
+// player loads nick in this channel, creating the row when create is set.
+func (a *adminCommands) player(ctx context.Context, ca *cat_actions.CatActions, nick string, create bool) (*cat_player.CatPlayer, error) {
+	p, err := ca.CatPlayerRepo.GetPlayerByName(ctx, nick, ca.Network, ca.Channel)
+	if err != nil || p != nil || !create {
+		return p, err
+	}
+	if err := ca.CatPlayerRepo.UpsertPlayer(ctx, &cat_player.CatPlayer{Name: nick, Network: ca.Network, Channel: ca.Channel}); err != nil {
+		return nil, err
+	}
+	return ca.CatPlayerRepo.GetPlayerByName(ctx, nick, ca.Network, ca.Channel)
+}
+
+func (a *adminCommands) setLove(ctx context.Context, inv *Invocation) error {
+	ca, err := a.catActions()
+	if err != nil {
+		return err
+	}
+	nick, love := inv.Args.String(0), lovemeter.ClampLove(inv.Args.Int(1))
+
+	p, err := a.player(ctx, ca, nick, true)
+	if err != nil {
+		return Unavailable("load "+nick, err)
+	}
+	if err := ca.CatPlayerRepo.SetLoveMeter(ctx, nick, ca.Network, ca.Channel, love); err != nil {
+		return Unavailable("set love of "+nick, err)
+	}
+	ca.RecordAdminSet(nick, cat_event.ActionSetLove, p.LoveMeter, love, 0)
+	a.reply(inv, "✅ %s's love meter is now %d%%", nick, love)
+	return nil
+}
+
+func (a *adminCommands) setBondPoints(ctx context.Context, inv *Invocation) error {
+	ca, err := a.catActions()
+	if err != nil {
+		return err
+	}
+	nick, points := inv.Args.String(0), inv.Args.Int(1)
+	if points < 0 {
+		points = 0
+	}
+
+	p, err := a.player(ctx, ca, nick, true)
+	if err != nil {
+		return Unavailable("load "+nick, err)
+	}
+	if err := ca.CatPlayerRepo.SetBondPoints(ctx, nick, ca.Network, ca.Channel, points); err != nil {
+		return Unavailable("set BondPoints of "+nick, err)
+	}
+	ca.RecordAdminSet(nick, cat_event.ActionSetBondPoints, p.LoveMeter, p.LoveMeter, points)
+	a.reply(inv, "✅ %s now has %d BondPoints", nick, points)
+	return nil
+}
+
+// reset wipes a player's progress (love, bond, streaks, gifts) and their catnip/slap state.
+func (a *adminCommands) reset(ctx context.Context, inv *Invocation) error {
+	ca, err := a.catActions()
+	if err != nil {
+		return err
+	}
+	nick := inv.Args.String(0)
+
+	p, err := a.player(ctx, ca, nick, false)
+	if err != nil {
+		return Unavailable("load "+nick, err)
+	}
+	if p == nil {
+		a.reply(inv, "no player %s in %s", nick, ca.Channel)
+		return nil
+	}
+	fresh := &cat_player.CatPlayer{Name: p.Name, Network: p.Network, Channel: p.Channel, CreatedAt: p.CreatedAt}
+	if err := ca.CatPlayerRepo.UpsertPlayer(ctx, fresh); err != nil {
+		return Unavailable("reset "+nick, err)
+	}
+	ca.ResetPlayer(nick)
+
+	a.reply(inv, "✅ %s has been reset", nick)
+	return nil
+}
+
+/* BANS */
+
+func (a *adminCommands) ban(_ context.Context, inv *Invocation) error {
+	if a.deps.ACL == nil {
+		return errors.New("no ACL configured")
+	}
+	if inv.Args.Len() == 0 {
+		bans := a.deps.ACL.Bans()
+		if len(bans) == 0 {
+			a.reply(inv, "nobody is banned")
+			return nil
+		}
+		a.reply(inv, "banned: %s", strings.Join(bans, ", "))
+		return nil
+	}
+
+	mask := admin.HostmaskFor(inv.Args.String(0))
+	if !a.deps.ACL.Ban(mask) {
+		a.reply(inv, "%s is already banned", mask)
+		return nil
+	}
+	a.reply(inv, "✅ banned %s", mask)
+	return nil
+}
+
+func (a *adminCommands) unban(_ context.Context, inv *Invocation) error {
+	if a.deps.ACL == nil {
+		return errors.New("no ACL configured")
+	}
+	mask := admin.HostmaskFor(inv.Args.String(0))
+	if !a.deps.ACL.Unban(mask) {
+		a.reply(inv, "%s is not banned", mask)
+		return nil
+	}
+	a.reply(inv, "✅ unbanned %s", mask)
+	return nil
+}
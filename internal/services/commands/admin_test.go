@@ -0,0 +1,203 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	irc "github.com/fluffle/goirc/client"
+)
+
+// mockConn records joins and parts
+type mockConn struct {
+	joined, parted []string
+}
+
+func (m *mockConn) Join(channel string, _ ...string) { m.joined = append(m.joined, channel) }
+func (m *mockConn) Part(channel string, _ ...string) { m.parted = append(m.parted, channel) }
+
+type adminSetup struct {
+	client *mockIRCClient
+	repo   cat_player.CatPlayerRepository
+	cc     CommandController
+	conn   *mockConn
+	acl    *admin.ACL
+	audit  *bytes.Buffer
+	ca     *cat_actions.CatActions
+}
+
+func setupAdmin(cfg admin.Config, level Privilege) *adminSetup {
+	client, repo, cb, _ := setupTest()
+	s := &adminSetup{client: client, repo: repo, conn: &mockConn{}, acl: admin.NewACL(cfg), audit: &bytes.Buffer{}}
+	s.ca = cb.CatActions.(*cat_actions.CatActions)
+
+	cc := NewCommandController(cb,
+		WithPrivilegeChecker(func(context.Context, *irc.Line) Privilege { return level }),
+		WithAdmin(s.acl, admin.NewAuditor(s.audit)),
+	)
+	cc.(*CommandControllerImpl).RegisterAdmin(AdminDeps{Conn: s.conn, Out: client, ACL: s.acl})
+	s.cc = cc
+	return s
+}
+
+func (s *adminSetup) as(nick, host, msg string) {
+	s.cc.HandleCommand(context.Background(), &irc.Line{Nick: nick, Ident: nick, Host: host, Args: []string{"#testchan", msg}})
+}
+
+func TestAdmin_ACLGrantsAccess(t *testing.T) {
+	s := setupAdmin(admin.Config{Masks: []string{"*!*@staff.host"}}, PrivilegeNone)
+
+	s.as("eve", "evil.host", "!join #elsewhere")
+	if len(s.conn.joined) != 0 {
+		t.Fatal("non-admin should not run admin commands")
+	}
+	if len(s.client.messages) != 0 {
+		t.Errorf("hidden admin commands should be denied silently, got %q", s.client.messages)
+	}
+
+	s.as("alice", "staff.host", "!join #elsewhere")
+	if len(s.conn.joined) != 1 || s.conn.joined[0] != "#elsewhere" {
+		t.Fatalf("admin join failed: %v", s.conn.joined)
+	}
+
+	audit := s.audit.String()
+	if !strings.Contains(audit, "eve!eve@evil.host #testchan !join #elsewhere => denied") {
+		t.Errorf("denied attempt should be audited, got %q", audit)
+	}
+	if !strings.Contains(audit, "alice!alice@staff.host #testchan !join #elsewhere => ok") {
+		t.Errorf("admin action should be audited, got %q", audit)
+	}
+}
+
+func TestAdmin_AccountTagAndRequireOp(t *testing.T) {
+	s := setupAdmin(admin.Config{Accounts: []string{"boss"}, RequireOp: true}, PrivilegeVoice)
+	line := &irc.Line{Nick: "b", Host: "h", Tags: map[string]string{"account": "Boss"}, Args: []string{"#testchan", "!part"}}
+
+	s.cc.HandleCommand(context.Background(), line)
+	if len(s.conn.parted) != 0 {
+		t.Fatal("RequireOp: a voiced admin should be denied")
+	}
+
+	s = setupAdmin(admin.Config{Accounts: []string{"boss"}, RequireOp: true}, PrivilegeOp)
+	s.cc.HandleCommand(context.Background(), line)
+	if len(s.conn.parted) != 1 || s.conn.parted[0] != "#testchan" {
+		t.Errorf("opped admin should part the current channel, got %v", s.conn.parted)
+	}
+}
+
+func TestAdmin_SetLoveSetBondPointsAndReset(t *testing.T) {
+	s := setupAdmin(admin.Config{Masks: []string{"alice!*@*"}}, PrivilegeNone)
+
+	s.as("alice", "h", "!setlove Bob 150")
+	s.as("alice", "h", "!setbp bob 42")
+
+	p, _ := s.repo.GetPlayerByName(context.Background(), "bob", "testnet", "#testchan")
+	if p == nil || p.LoveMeter != 100 || p.BondPoints != 42 {
+		t.Fatalf("expected love clamped to 100 and 42 BondPoints, got %+v", p)
+	}
+
+	s.ca.EnsureHere(0)
+	s.ca.ExecuteAction("slap", "bob", "purrito")
+	s.as("alice", "h", "!reset bob")
+
+	p, _ = s.repo.GetPlayerByName(context.Background(), "bob", "testnet", "#testchan")
+	if p == nil || p.LoveMeter != 0 || p.BondPoints != 0 {
+		t.Errorf("reset should wipe progress, got %+v", p)
+	}
+	if got := s.ca.ExecuteAction("slap", "bob", "purrito"); strings.Contains(got, "love meter decreased") {
+		t.Errorf("reset should forget the slap warning, got %q", got)
+	}
+}
+
+func TestAdmin_SetLoveAndSetBondPointsAreLogged(t *testing.T) {
+	client := &mockIRCClient{}
+	repo := newPlayerRepo()
+	events := cat_event.NewMemoryCatEventRepository()
+	cb := catbot.NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		cat_actions.WithEventLog(events))
+	cc := NewCommandController(cb, WithPrivilegeChecker(func(context.Context, *irc.Line) Privilege { return PrivilegeAdmin }))
+	cc.(*CommandControllerImpl).RegisterAdmin(AdminDeps{Conn: &mockConn{}, Out: client})
+
+	ctx := context.Background()
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{Name: "bob", Network: "testnet", Channel: "#testchan", LoveMeter: 30, BondPoints: 10})
+	for _, msg := range []string{"!setlove bob 80", "!setbp bob 4"} {
+		cc.HandleCommand(ctx, &irc.Line{Nick: "alice", Args: []string{"#testchan", msg}})
+	}
+
+	if p, _ := repo.GetPlayerByName(ctx, "bob", "testnet", "#testchan"); p.BondPoints != 4 {
+		t.Errorf("expected 4 BondPoints, got %d", p.BondPoints)
+	}
+	got, err := events.History(ctx, "testnet", "#testchan", cat_event.Query{Player: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("want 2 events, got %+v", got)
+	}
+	setBP, setLove := got[0], got[1] // newest first
+	if setLove.Action != cat_event.ActionSetLove || setLove.Outcome != cat_event.OutcomeSet || setLove.LoveBefore != 30 || setLove.LoveAfter != 80 {
+		t.Errorf("unexpected !setlove event %+v", setLove)
+	}
+	if setBP.Action != cat_event.ActionSetBondPoints || setBP.Outcome != cat_event.OutcomeSet || setBP.BondPoints != 4 {
+		t.Errorf("unexpected !setbp event %+v", setBP)
+	}
+	if s := formatEvent(setBP, setBP.CreatedAt); !strings.Contains(s, "(80→80%, 4 BP)") {
+		t.Errorf("!setbp should show the new total, got %q", s)
+	}
+}
+
+func TestAdmin_ForceSpawnAndDespawn(t *testing.T) {
+	s := setupAdmin(admin.Config{Masks: []string{"alice!*@*"}}, PrivilegeNone)
+
+	s.as("alice", "h", "!despawn")
+	if s.ca.IsHere() {
+		t.Fatal("despawn should send Purrito away")
+	}
+	s.as("alice", "h", "!forcespawn")
+	if !s.ca.IsHere() {
+		t.Error("forcespawn should bring Purrito back despite the respawn timer")
+	}
+}
+
+func TestAdmin_BanIgnoresEveryCommand(t *testing.T) {
+	s := setupAdmin(admin.Config{Masks: []string{"alice!*@*"}}, PrivilegeNone)
+
+	called := 0
+	s.cc.Register(Command{Name: "pet", Handler: func(context.Context, *Invocation) error { called++; return nil }})
+
+	s.as("alice", "h", "!ban troll")
+	s.as("troll", "anywhere", "!pet")
+	if called != 0 {
+		t.Fatal("banned nick should be ignored")
+	}
+
+	s.as("alice", "h", "!unban troll")
+	s.as("troll", "anywhere", "!pet")
+	if called != 1 {
+		t.Error("unbanned nick should be served again")
+	}
+	if _, ok := s.client.notices["alice"]; !ok {
+		t.Error("admin replies should be sent as notices")
+	}
+}
+
+func TestAdmin_SayAndUsage(t *testing.T) {
+	s := setupAdmin(admin.Config{Masks: []string{"alice!*@*"}}, PrivilegeNone)
+
+	s.as("alice", "h", "!say #cats hello there")
+	if s.client.LastMessage() != "hello there" {
+		t.Errorf("got %q", s.client.LastMessage())
+	}
+
+	s.as("alice", "h", "!setlove bob lots")
+	if !strings.Contains(s.client.LastMessage(), "Usage: !setlove") {
+		t.Errorf("expected usage, got %q", s.client.LastMessage())
+	}
+}
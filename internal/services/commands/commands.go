@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+	"github.com/MyelinBots/catbot-go/internal/services/admin"
 	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
 	"github.com/MyelinBots/catbot-go/internal/services/catbot"
 	"github.com/MyelinBots/catbot-go/internal/services/context_manager"
+	"github.com/MyelinBots/catbot-go/internal/services/throttle"
 	irc "github.com/fluffle/goirc/client"
 )
 
@@ -18,21 +23,83 @@ import (
 type CommandController interface {
 	HandleCommand(ctx context.Context, line *irc.Line) error
 	AddCommand(command string, handler func(ctx context.Context, message string) error)
+	Register(cmd Command)
+	Commands() []Command
 }
 
+// PrivilegeChecker resolves the privilege of the sender of line in its channel.
+type PrivilegeChecker func(ctx context.Context, line *irc.Line) Privilege
+
 // --------------------------------------------------
 // Controller
 // --------------------------------------------------
 
 type CommandControllerImpl struct {
-	game     *catbot.CatBot
-	commands map[string]func(ctx context.Context, message string) error
+	game      *catbot.CatBot
+	router    *Router
+	privilege PrivilegeChecker
+
+	helpDelivery string
+	helpPageSize int
+	helpClient   catbot.IRCClient
+	locale       Locale
+
+	throttle *throttle.Throttler
+
+	acl   *admin.ACL
+	audit *admin.Auditor
+}
+
+type Option func(c *CommandControllerImpl)
+
+// WithPrefixes sets the command prefixes (default "!").
+func WithPrefixes(prefixes ...string) Option {
+	return func(c *CommandControllerImpl) { c.router.SetPrefixes(prefixes...) }
+}
+
+// WithNames sets the names that address the bot, e.g. "Purrito: pet".
+func WithNames(names ...string) Option {
+	return func(c *CommandControllerImpl) { c.router.SetNames(names...) }
 }
 
-func NewCommandController(gameinstance *catbot.CatBot) CommandController {
-	return &CommandControllerImpl{
-		game:     gameinstance,
-		commands: make(map[string]func(ctx context.Context, message string) error),
+// WithPrivilegeChecker sets how privileged commands check the sender.
+func WithPrivilegeChecker(check PrivilegeChecker) Option {
+	return func(c *CommandControllerImpl) { c.privilege = check }
+}
+
+// WithThrottle rate limits commands per nick, host and channel.
+// Share one Throttler between channels so host limits apply network wide.
+func WithThrottle(t *throttle.Throttler) Option {
+	return func(c *CommandControllerImpl) { c.throttle = t }
+}
+
+// WithAdmin grants PrivilegeAdmin to senders on the ACL, ignores banned hostmasks
+// and audit-logs every admin command.
+func WithAdmin(acl *admin.ACL, audit *admin.Auditor) Option {
+	return func(c *CommandControllerImpl) {
+		c.acl = acl
+		c.audit = audit
+	}
+}
+
+func NewCommandController(gameinstance *catbot.CatBot, opts ...Option) CommandController {
+	c := &CommandControllerImpl{
+		game:         gameinstance,
+		router:       NewRouter([]string{"!"}, nil),
+		helpDelivery: HelpNotice,
+		helpPageSize: defaultHelpPageSize,
+		locale:       NewLocale(defaultLanguage),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// MessageHandler adapts a handler that parses the canonical message itself ("!pet purrito").
+func MessageHandler(h func(ctx context.Context, message string) error) HandlerFunc {
+	return func(ctx context.Context, inv *Invocation) error {
+		return h(ctx, inv.Message)
 	}
 }
 
@@ -40,29 +107,150 @@ func NewCommandController(gameinstance *catbot.CatBot) CommandController {
 // Core dispatcher
 // --------------------------------------------------
 
+// HandleCommand runs the command in line, if any. Failures are answered and
+// logged by renderError rather than returned.
 func (c *CommandControllerImpl) HandleCommand(ctx context.Context, line *irc.Line) error {
 	if len(line.Args) < 2 {
 		return nil
 	}
 
-	message := line.Args[1]
-	fields := strings.Fields(message)
-	if len(fields) == 0 {
+	inv, ok := c.router.Match(line.Args[1])
+	if !ok {
+		return nil
+	}
+	inv.Nick = line.Nick
+	inv.Source = fmt.Sprintf("%s!%s@%s", line.Nick, line.Ident, line.Host)
+	inv.Account = line.Tags["account"]
+	inv.Channel = line.Args[0]
+	cmd := inv.Command
+
+	if c.acl != nil && c.acl.Banned(inv.Source, inv.Account) {
 		return nil
 	}
 
-	cmd := fields[0]
-	handler, exists := c.commands[cmd]
-	if !exists {
+	// commands turned off with !purrito set disabled ...
+	if ca, ok := c.game.CatActions.(*cat_actions.CatActions); ok && !ca.Settings().Enabled(cmd.Name) {
 		return nil
 	}
 
 	ctx = context_manager.SetNickContext(ctx, line.Nick)
-	return handler(ctx, message)
+
+	// everything logged for this command carries who ran what
+	log := c.game.Logger().With(logging.KeyNick, line.Nick, logging.KeyCommand, cmd.Name)
+	ctx = logging.WithLogger(ctx, log)
+
+	start := time.Now()
+	err := c.dispatch(ctx, line, inv)
+	metrics.CommandsHandled.WithLabelValues(cmd.Name, Outcome(err)).Inc()
+	if err != nil {
+		c.renderError(ctx, inv, err, start)
+		return nil
+	}
+	log.Debug("command handled", logging.Since(start))
+	return nil
 }
 
+// dispatch checks limits, privilege and arguments, then runs the handler.
+// Whatever goes wrong comes back as an error for renderError.
+func (c *CommandControllerImpl) dispatch(ctx context.Context, line *irc.Line, inv *Invocation) error {
+	cmd := inv.Command
+
+	if c.throttle != nil {
+		switch c.throttle.Check(cmd.Name, line.Nick, line.Host, inv.Channel) {
+		case throttle.Allow:
+		case throttle.Warn:
+			return &RateLimitedError{Command: cmd.Name, Warn: true}
+		default:
+			return &RateLimitedError{Command: cmd.Name}
+		}
+	}
+
+	if cmd.Privilege > PrivilegeNone {
+		if have := c.privilegeOf(ctx, line, inv); have < cmd.Privilege {
+			c.auditAdmin(inv, "denied")
+			return &NotPermittedError{Command: cmd.Name, Needs: cmd.Privilege, Hidden: cmd.Hidden}
+		}
+	}
+
+	args, err := ParseArgs(cmd.Params, inv.Args)
+	if err != nil {
+		return &UsageError{Usage: usageOf(cmd), Reason: err.Error()}
+	}
+	inv.Args = args
+
+	if !c.router.Allow(cmd, line.Nick) {
+		return &RateLimitedError{Command: cmd.Name}
+	}
+
+	if cmd.Handler == nil {
+		return nil
+	}
+	err = cmd.Handler(ctx, inv)
+
+	result := "ok"
+	if err != nil {
+		result = err.Error()
+	}
+	c.auditAdmin(inv, result)
+	return err
+}
+
+// privilegeOf is the sender's channel privilege, raised to PrivilegeAdmin when
+// they are on the ACL (and, if the ACL requires it, opped in the channel).
+func (c *CommandControllerImpl) privilegeOf(ctx context.Context, line *irc.Line, inv *Invocation) Privilege {
+	have := PrivilegeNone
+	if c.privilege != nil {
+		have = c.privilege(ctx, line)
+	}
+	if c.acl != nil && c.acl.IsAdmin(inv.Source, inv.Account) {
+		if !c.acl.RequireOp() || have >= PrivilegeOp {
+			return PrivilegeAdmin
+		}
+	}
+	return have
+}
+
+// auditAdmin records admin commands; other commands are not logged.
+func (c *CommandControllerImpl) auditAdmin(inv *Invocation, result string) {
+	if c.audit == nil || inv.Command.Privilege < PrivilegeAdmin {
+		return
+	}
+	c.audit.Record(admin.Entry{
+		Who:     inv.Source,
+		Account: inv.Account,
+		Channel: inv.Channel,
+		Command: inv.Command.Name,
+		Args:    inv.Args,
+		Result:  result,
+	})
+}
+
+// AddCommand registers a handler without metadata, e.g. AddCommand("!test", h).
 func (c *CommandControllerImpl) AddCommand(command string, handler func(ctx context.Context, message string) error) {
-	c.commands[command] = handler
+	c.router.Register(Command{Name: command, Handler: MessageHandler(handler)})
+}
+
+func (c *CommandControllerImpl) Register(cmd Command) {
+	c.router.Register(cmd)
+}
+
+func (c *CommandControllerImpl) Commands() []Command {
+	return c.router.Commands()
+}
+
+// replyClient is where private replies (help, notices) go.
+func (c *CommandControllerImpl) replyClient() catbot.IRCClient {
+	if c.helpClient != nil {
+		return c.helpClient
+	}
+	return c.game.IrcClient
+}
+
+func usageOf(cmd *Command) string {
+	if cmd.Usage != "" {
+		return cmd.Usage
+	}
+	return "!" + cmd.Name
 }
 
 // --------------------------------------------------
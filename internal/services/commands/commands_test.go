@@ -14,10 +14,15 @@ import (
 	irc "github.com/fluffle/goirc/client"
 )
 
+func newPlayerRepo() cat_player.CatPlayerRepository {
+	return cat_player.NewMemoryPlayerRepository()
+}
+
 // mockIRCClient records messages sent
 type mockIRCClient struct {
 	mu       sync.Mutex
 	messages []string
+	notices  map[string][]string // target -> notices
 }
 
 func (m *mockIRCClient) Privmsg(channel, message string) {
@@ -26,6 +31,16 @@ func (m *mockIRCClient) Privmsg(channel, message string) {
 	m.messages = append(m.messages, message)
 }
 
+func (m *mockIRCClient) Notice(target, message string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, message)
+	if m.notices == nil {
+		m.notices = make(map[string][]string)
+	}
+	m.notices[target] = append(m.notices[target], message)
+}
+
 func (m *mockIRCClient) LastMessage() string {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -39,156 +54,13 @@ func (m *mockIRCClient) Clear() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.messages = nil
-}
-
-// mockCatPlayerRepo is a simple in-memory mock
-type mockCatPlayerRepo struct {
-	mu      sync.RWMutex
-	players map[string]*cat_player.CatPlayer
-}
-
-func newMockRepo() *mockCatPlayerRepo {
-	return &mockCatPlayerRepo{
-		players: make(map[string]*cat_player.CatPlayer),
-	}
-}
-
-func (m *mockCatPlayerRepo) key(name, network, channel string) string {
-	return strings.ToLower(name) + "|" + strings.ToLower(network) + "|" + strings.ToLower(channel)
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByID(ctx context.Context, id string) (*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByName(ctx context.Context, name, network, channel string) (*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	p := m.players[m.key(name, network, channel)]
-	if p != nil {
-		cp := *p
-		return &cp, nil
-	}
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetAllPlayers(ctx context.Context, network, channel string) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) UpsertPlayer(ctx context.Context, player *cat_player.CatPlayer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(player.Name, player.Network, player.Channel)
-	m.players[k] = player
-	return nil
-}
-
-func (m *mockCatPlayerRepo) TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	var result []*cat_player.CatPlayer
-	for _, p := range m.players {
-		if strings.EqualFold(p.Network, network) && strings.EqualFold(p.Channel, channel) {
-			cp := *p
-			result = append(result, &cp)
-		}
-	}
-
-	// Sort by love meter (descending) - simple bubble sort for test
-	for i := 0; i < len(result); i++ {
-		for j := i + 1; j < len(result); j++ {
-			if result[j].LoveMeter > result[i].LoveMeter {
-				result[i], result[j] = result[j], result[i]
-			}
-		}
-	}
-
-	if len(result) > limit {
-		result = result[:limit]
-	}
-	return result, nil
-}
-
-func (m *mockCatPlayerRepo) TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetDecayAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) ListPlayersAtOrAbove(ctx context.Context, network, channel string, minLove int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) SetPerfectDropWarned(ctx context.Context, name, network, channel string, warned bool) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddBondPoints(ctx context.Context, name, network, channel string, delta int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPoints += delta
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastBondPointsAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPointStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.HighestBondStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(nick, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LoveMeter = love
-	}
-	return nil
+	m.notices = nil
 }
 
 // Helper to create a test setup
-func setupTest() (*mockIRCClient, *mockCatPlayerRepo, *catbot.CatBot, CommandController) {
+func setupTest() (*mockIRCClient, cat_player.CatPlayerRepository, *catbot.CatBot, CommandController) {
 	client := &mockIRCClient{}
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	cb := catbot.NewCatBot(client, repo, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
 	cc := NewCommandController(cb)
 	return client, repo, cb, cc
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+)
+
+// --------------------------------------------------
+// Errors
+// Handlers return these instead of answering failures themselves;
+// HandleCommand renders them for the user (see renderError) and logs the
+// details, so raw errors never reach the channel.
+// --------------------------------------------------
+
+// UsageError means the command was called with the wrong arguments.
+type UsageError struct {
+	Usage  string // e.g. "!invite purrito #channel"
+	Reason string // what was wrong, for the log
+}
+
+func (e *UsageError) Error() string {
+	if e.Reason == "" {
+		return "usage: " + e.Usage
+	}
+	return fmt.Sprintf("usage: %s (%s)", e.Usage, e.Reason)
+}
+
+// NotPermittedError means the sender lacks the command's privilege.
+type NotPermittedError struct {
+	Command string
+	Needs   Privilege
+	Hidden  bool // hidden commands are refused silently
+}
+
+func (e *NotPermittedError) Error() string {
+	return fmt.Sprintf("!%s needs %s", e.Command, e.Needs)
+}
+
+// RateLimitedError means a throttle or cooldown refused the command.
+type RateLimitedError struct {
+	Command string
+	Warn    bool // tell the sender once; false drops the command silently
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("!%s rate limited", e.Command)
+}
+
+// UnavailableError means the storage behind the command failed.
+type UnavailableError struct {
+	Op  string // what was being done, e.g. "load top lovers"
+	Err error
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("%s: storage unavailable: %v", e.Op, e.Err)
+}
+
+func (e *UnavailableError) Unwrap() error { return e.Err }
+
+// Unavailable wraps a storage error (nil stays nil).
+func Unavailable(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &UnavailableError{Op: op, Err: err}
+}
+
+// Outcome is the metrics label for how a command ended (nil = ok).
+func Outcome(err error) string {
+	var (
+		usage       *UsageError
+		denied      *NotPermittedError
+		limited     *RateLimitedError
+		unavailable *UnavailableError
+	)
+	switch {
+	case err == nil:
+		return metrics.OutcomeOK
+	case errors.As(err, &usage):
+		return metrics.OutcomeUsage
+	case errors.As(err, &denied):
+		return metrics.OutcomeDenied
+	case errors.As(err, &limited):
+		return metrics.OutcomeRateLimited
+	case errors.As(err, &unavailable):
+		return metrics.OutcomeUnavailable
+	}
+	return metrics.OutcomeError
+}
+
+// renderError answers a failed command in character and logs what happened.
+// Usage, permission and rate-limit errors are expected and logged at debug;
+// everything else is logged with its full details.
+func (c *CommandControllerImpl) renderError(ctx context.Context, inv *Invocation, err error, start time.Time) {
+	log := logging.FromContextOr(ctx, c.game.Logger())
+	var (
+		usage       *UsageError
+		denied      *NotPermittedError
+		limited     *RateLimitedError
+		unavailable *UnavailableError
+	)
+	switch {
+	case errors.As(err, &usage):
+		log.Debug("command refused", "reason", err.Error())
+		c.game.IrcClient.Privmsg(c.game.Channel, c.locale.T("error.usage", usage.Usage))
+	case errors.As(err, &denied):
+		log.Debug("command refused", "reason", err.Error())
+		if !denied.Hidden { // don't advertise hidden commands
+			c.game.IrcClient.Privmsg(c.game.Channel, c.locale.T("error.denied", inv.Nick, denied.Command, denied.Needs))
+		}
+	case errors.As(err, &limited):
+		log.Debug("command refused", "reason", err.Error())
+		if limited.Warn {
+			c.replyClient().Notice(inv.Nick, c.locale.T("throttle.slowdown", inv.Nick))
+		}
+	case errors.As(err, &unavailable):
+		log.Error("command failed: storage unavailable", "error", err, logging.Since(start))
+		c.game.IrcClient.Privmsg(c.game.Channel, c.locale.T("error.unavailable", inv.Nick))
+	default:
+		log.Error("command failed", "error", err, logging.Since(start))
+		if inv.Command.Privilege >= PrivilegeAdmin {
+			// admins get the details, everyone else a shrug
+			c.replyClient().Notice(inv.Nick, fmt.Sprintf("❌ !%s failed: %v", inv.Command.Name, err))
+			return
+		}
+		c.game.IrcClient.Privmsg(c.game.Channel, c.locale.T("error.internal", inv.Nick))
+	}
+}
@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/metrics"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	irc "github.com/fluffle/goirc/client"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// brokenRepo fails like a database that went away
+type brokenRepo struct{ cat_player.CatPlayerRepository }
+
+func (brokenRepo) TopLoveMeter(context.Context, string, string, int) ([]*cat_player.CatPlayer, error) {
+	return nil, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func TestRenderError_StorageUnavailable(t *testing.T) {
+	client := &mockIRCClient{}
+	cb := catbot.NewCatBot(client, brokenRepo{newPlayerRepo()}, "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute)
+	cc := NewCommandController(cb).(*CommandControllerImpl)
+	cc.Register(Command{Name: "toplove", Handler: MessageHandler(func(ctx context.Context, msg string) error {
+		return cc.TopLove10Handler()(ctx, msg)
+	})})
+
+	if err := cc.HandleCommand(context.Background(), &irc.Line{Nick: "player1", Args: []string{"#testchan", "!toplove"}}); err != nil {
+		t.Fatalf("rendered errors should not be returned, got %v", err)
+	}
+	msg := client.LastMessage()
+	if !strings.Contains(msg, "Sorry player1") || strings.Contains(msg, "connection refused") {
+		t.Errorf("expected a friendly reply without the raw error, got %q", msg)
+	}
+}
+
+func TestRenderError_Kinds(t *testing.T) {
+	client, _, _, cc := setupTest()
+	impl := cc.(*CommandControllerImpl)
+	inv := func(cmd Command) *Invocation {
+		return &Invocation{Command: &cmd, Nick: "player1", Source: "player1!p@host", Channel: "#testchan"}
+	}
+
+	impl.renderError(context.Background(), inv(Command{Name: "invite"}), &UsageError{Usage: "!invite purrito #channel", Reason: "missing #channel"}, time.Now())
+	if msg := client.LastMessage(); !strings.Contains(msg, "Usage: !invite purrito #channel") {
+		t.Errorf("usage: got %q", msg)
+	}
+
+	client.Clear()
+	impl.renderError(context.Background(), inv(Command{Name: "secret"}), &NotPermittedError{Command: "secret", Needs: PrivilegeAdmin, Hidden: true}, time.Now())
+	impl.renderError(context.Background(), inv(Command{Name: "pet"}), &RateLimitedError{Command: "pet"}, time.Now())
+	if len(client.messages) != 0 {
+		t.Errorf("hidden denials and silent limits say nothing, got %q", client.messages)
+	}
+
+	impl.renderError(context.Background(), inv(Command{Name: "pet"}), &RateLimitedError{Command: "pet", Warn: true}, time.Now())
+	if len(client.notices["player1"]) != 1 {
+		t.Errorf("expected one slow-down notice, got %q", client.notices)
+	}
+
+	client.Clear()
+	impl.renderError(context.Background(), inv(Command{Name: "pet"}), errors.New("boom"), time.Now())
+	if msg := client.LastMessage(); strings.Contains(msg, "boom") || !strings.Contains(msg, "player1") {
+		t.Errorf("unexpected errors stay internal, got %q", msg)
+	}
+
+	client.Clear()
+	impl.renderError(context.Background(), inv(Command{Name: "reload", Privilege: PrivilegeAdmin}), errors.New("boom"), time.Now())
+	if n := client.notices["player1"]; len(n) != 1 || !strings.Contains(n[0], "boom") {
+		t.Errorf("admins get the details by notice, got %q", client.notices)
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	for err, want := range map[error]string{
+		nil:                                  metrics.OutcomeOK,
+		&UsageError{Usage: "!pet purrito"}:   metrics.OutcomeUsage,
+		&NotPermittedError{Command: "op"}:    metrics.OutcomeDenied,
+		&RateLimitedError{Command: "pet"}:    metrics.OutcomeRateLimited,
+		Unavailable("load", errors.New("x")): metrics.OutcomeUnavailable,
+		errors.New("boom"):                   metrics.OutcomeError,
+	} {
+		if got := Outcome(err); got != want {
+			t.Errorf("Outcome(%v) = %q, want %q", err, got, want)
+		}
+	}
+}
+
+func TestHandleCommand_CountsOutcomes(t *testing.T) {
+	_, _, _, cc := setupTest()
+	cc.Register(Command{Name: "metricsok", Handler: MessageHandler(func(context.Context, string) error { return nil })})
+	cc.Register(Command{Name: "metricsfail", Handler: MessageHandler(func(context.Context, string) error { return errors.New("boom") })})
+
+	ok := metrics.CommandsHandled.WithLabelValues("metricsok", metrics.OutcomeOK)
+	failed := metrics.CommandsHandled.WithLabelValues("metricsfail", metrics.OutcomeError)
+	okBefore, failedBefore := testutil.ToFloat64(ok), testutil.ToFloat64(failed)
+
+	for _, msg := range []string{"!metricsok", "!metricsok", "!metricsfail"} {
+		_ = cc.HandleCommand(context.Background(), &irc.Line{Nick: "player1", Args: []string{"#testchan", msg}})
+	}
+	if got := testutil.ToFloat64(ok) - okBefore; got != 2 {
+		t.Errorf("ok = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(failed) - failedBefore; got != 1 {
+		t.Errorf("error = %v, want 1", got)
+	}
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+)
+
+// --------------------------------------------------
+// History
+// "!history [nick]" shows the last few things that happened between a player
+// and Purrito in this channel, from the cat_event log.
+// --------------------------------------------------
+
+const historyLength = 5
+
+var (
+	historyActions = map[string]string{
+		"pet":                         "🤚 pet",
+		"love":                        "💕 love",
+		"feed":                        "🍣 feed",
+		"laser":                       "🔦 laser",
+		"catnip":                      "🌿 catnip",
+		"slap":                        "👋 slap",
+		"kick":                        "🦶 kick",
+		cat_event.ActionDecay:         "🍂 missed a day",
+		cat_event.ActionBond:          "💞 bonded",
+		cat_event.ActionSetLove:       "🛠️ love set by an admin",
+		cat_event.ActionSetBondPoints: "🛠️ BondPoints set by an admin",
+	}
+	historyOutcomes = map[string]string{
+		cat_event.OutcomeAccepted: "😻",
+		cat_event.OutcomeRejected: "😾",
+		cat_event.OutcomeWarned:   "⚠️",
+		cat_event.OutcomePunished: "😿",
+		cat_event.OutcomeDecayed:  "💔",
+		cat_event.OutcomeAwarded:  "✨",
+		cat_event.OutcomeSet:      "🔧",
+	}
+)
+
+// RegisterHistory registers "!history" backed by repo.
+func (c *CommandControllerImpl) RegisterHistory(repo cat_event.CatEventRepository) {
+	h := &historyCommand{c: c, repo: repo}
+	c.Register(Command{
+		Name:        "history",
+		Usage:       "!history [nick]",
+		Description: "What you (or nick) and I did lately 📜",
+		Params:      []Param{{Name: "nick", Type: ArgNick, Optional: true}},
+		Handler:     h.history,
+	})
+}
+
+type historyCommand struct {
+	c    *CommandControllerImpl
+	repo cat_event.CatEventRepository
+}
+
+func (h *historyCommand) history(ctx context.Context, inv *Invocation) error {
+	game := h.c.game
+	nick := inv.Args.String(0)
+	if nick == "" {
+		nick = inv.Nick
+	}
+
+	events, err := h.repo.History(ctx, game.Network, game.Channel, cat_event.Query{Player: nick, Limit: historyLength})
+	if err != nil {
+		return Unavailable("load history", err)
+	}
+	if len(events) == 0 {
+		game.IrcClient.Privmsg(game.Channel, fmt.Sprintf("📜 Purrito doesn't remember anything with %s yet 🐾", nick))
+		return nil
+	}
+
+	now := time.Now()
+	if ca, ok := game.CatActions.(*cat_actions.CatActions); ok {
+		now = ca.Clock().Now()
+	}
+	parts := make([]string, 0, len(events))
+	for _, e := range events {
+		parts = append(parts, formatEvent(e, now))
+	}
+	game.IrcClient.Privmsg(game.Channel, fmt.Sprintf("📜 Purrito remembers %s: %s", nick, strings.Join(parts, "  •  ")))
+	return nil
+}
+
+// formatEvent renders e as e.g. "2h ago 🍣 feed 😻 (41→42%, +3 BP)".
+func formatEvent(e *cat_event.CatEvent, now time.Time) string {
+	action, ok := historyActions[e.Action]
+	if !ok {
+		action = e.Action
+	}
+	out := fmt.Sprintf("%s %s %s (%d→%d%%", ago(now.Sub(e.CreatedAt)), action, historyOutcomes[e.Outcome], e.LoveBefore, e.LoveAfter)
+	switch {
+	case e.Action == cat_event.ActionSetBondPoints: // the new total, not a gain
+		out += fmt.Sprintf(", %d BP", e.BondPoints)
+	case e.BondPoints > 0:
+		out += fmt.Sprintf(", +%d BP", e.BondPoints)
+	}
+	return out + ")"
+}
+
+// ago rounds d down to the largest unit: "just now", "5m ago", "3h ago", "2d ago".
+func ago(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+}
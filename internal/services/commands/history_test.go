@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+	irc "github.com/fluffle/goirc/client"
+)
+
+func TestHistory(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	client := &mockIRCClient{}
+	cb := catbot.NewCatBot(client, newPlayerRepo(), "testnet", "#testchan", 30*time.Minute, 30*time.Minute, 30*time.Minute,
+		cat_actions.WithClock(clock.NewFake(now)))
+	cc := NewCommandController(cb)
+	repo := cat_event.NewMemoryCatEventRepository()
+	cc.(*CommandControllerImpl).RegisterHistory(repo)
+
+	ctx := context.Background()
+	for _, e := range []cat_event.CatEvent{
+		{CreatedAt: now.Add(-26 * time.Hour), Player: "alice", Action: cat_event.ActionDecay, Outcome: cat_event.OutcomeDecayed, LoveBefore: 50, LoveAfter: 40},
+		{CreatedAt: now.Add(-2 * time.Hour), Player: "alice", Action: "feed", Outcome: cat_event.OutcomeAccepted, LoveBefore: 40, LoveAfter: 41, BondPoints: 3},
+		{CreatedAt: now.Add(-5 * time.Minute), Player: "bob", Action: "slap", Outcome: cat_event.OutcomeWarned, LoveBefore: 10, LoveAfter: 10},
+	} {
+		e.Network, e.Channel = "testnet", "#testchan"
+		if err := repo.Append(ctx, &e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	say := func(nick, msg string) string {
+		client.Clear()
+		cc.HandleCommand(ctx, &irc.Line{Nick: nick, Args: []string{"#testchan", msg}})
+		return client.LastMessage()
+	}
+
+	got := say("alice", "!history")
+	for _, want := range []string{"remembers alice", "2h ago 🍣 feed 😻 (40→41%, +3 BP)", "1d ago 🍂 missed a day 💔 (50→40%)"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("!history = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Index(got, "feed") > strings.Index(got, "missed a day") {
+		t.Errorf("history should be newest first: %q", got)
+	}
+
+	if got := say("alice", "!history bob"); !strings.Contains(got, "5m ago 👋 slap ⚠️ (10→10%)") {
+		t.Errorf("!history bob = %q", got)
+	}
+	if got := say("alice", "!history carol"); !strings.Contains(got, "doesn't remember anything with carol") {
+		t.Errorf("!history carol = %q", got)
+	}
+}
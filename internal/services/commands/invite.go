@@ -3,27 +3,31 @@ package commands
 import (
 	"context"
 	"fmt"
-	"strings"
 
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
 	"github.com/MyelinBots/catbot-go/internal/services/context_manager"
 	irc "github.com/fluffle/goirc/client"
 )
 
-// InviteHandler allows users to invite purrito to their own channels
-func InviteHandler(ircClient *irc.Conn) func(ctx context.Context, args ...string) error {
-	return func(ctx context.Context, args ...string) error {
-		nick := context_manager.GetNickContext(ctx)
-
-		if len(args) < 2 || strings.ToLower(args[0]) != "purrito" {
-			return fmt.Errorf("usage: !invite purrito #channel")
-		}
+// InviteParams: !invite purrito #channel
+var InviteParams = []Param{
+	{Name: "purrito", Literal: "purrito"},
+	{Name: "#channel", Type: ArgChannel},
+}
 
-		channel := args[1]
+// InviteHandler allows users to invite purrito to their own channels.
+// The greeting goes through out (the outbound queue) like every other message;
+// a missing or malformed #channel is answered with the usage by HandleCommand.
+func InviteHandler(ircClient *irc.Conn, out catbot.IRCClient) HandlerFunc {
+	return func(ctx context.Context, inv *Invocation) error {
+		nick := context_manager.GetNickContext(ctx)
+		channel := inv.Args.String(1)
 
 		ircClient.Join(channel)
-		ircClient.Privmsg(channel, fmt.Sprintf("purrito: meows and joins %s's channel. 🐾", nick))
+		out.Privmsg(channel, fmt.Sprintf("purrito: meows and joins %s's channel. 🐾", nick))
 
-		fmt.Println("Invite command received from", nick)
+		logging.FromContext(ctx).Info("invited to a channel", "to", channel)
 		return nil
 	}
 }
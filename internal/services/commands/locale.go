@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// --------------------------------------------------
+// Help text catalog (en, th)
+// English command descriptions live on Command.Description;
+// other languages translate them with the key "cmd.<name>".
+// --------------------------------------------------
+
+const defaultLanguage = "en"
+
+var catalogs = map[string]map[string]string{
+	"en": {
+		"help.greeting": "🐱 Hi %s! I am \x0303Purrito\x0F — your friendly IRC cat on the \x0311DarkWorld Network\x0F",
+		"help.game":     "\x0310✨ = How the game works = ✨\x0F",
+		"help.rules": "\x0309 * \x0FPet, love, feed, catnip or laser with me to increase your \x0313Love Meter\x0F ❤️ \x0311(0–100%)\x0F\n" +
+			"\x0309 * \x0FReach \x0303100%\x0F ❤️ to become \x0313Bonded\x0F —> this unlocks \x0310daily BondPoints\x0F ⭐\n" +
+			"\x0309 * \x0FBondPoints are earned \x0311once per day\x0F while bonded \x0307(streaks give bonus points)\x0F\n" +
+			"\x0309 * \x0FIf you ignore me for a day, your bond may slowly fade... \x0304</3\x0F\n" +
+			"\x0309 * \x0FLong bonding streaks unlock \x0313secret gifts\x0F and \x0310special titles\x0F 🎁",
+		"help.commands": "\x0310🐾 = Commands you can use = 🐾\x0F",
+		"help.tip":      "\x0313= Tip =\x0F Come back \x0311every day\x0F to keep our bond strong and unlock \x0303rare rewards\x0F ✨",
+		"help.page":     "\x0307Page %d/%d\x0F — type \x0311%s %d\x0F for more, or \x0311!help <command>\x0F for details",
+		"help.nopage":   "There are only %d page(s) of help 😺",
+		"help.unknown":  "I don't know a command called %s 😿 — try !help",
+		"help.usage":    "\x0311%s\x0F \x0307::::\x0F %s",
+		"help.aliases":  "Also: %s",
+		"help.cooldown": "Cooldown: %s",
+		"help.needs":    "Needs: %s",
+
+		"throttle.slowdown": "😾 Slow down %s... give me a moment to catch my breath 🐾",
+
+		"error.usage":       "😼 Usage: %s",
+		"error.denied":      "🚫 %s: !%s needs %s",
+		"error.unavailable": "😿 Sorry %s, Purrito can't find his notebook right now... try again in a little while 🐾",
+		"error.internal":    "🙀 Oops %s, Purrito knocked something off the table... try again later 🐾",
+	},
+	"th": {
+		"help.greeting": "🐱 สวัสดี %s! ฉันคือ \x0303Purrito\x0F — แมวเพื่อนซี้บน IRC แห่ง \x0311DarkWorld Network\x0F",
+		"help.game":     "\x0310✨ = วิธีเล่น = ✨\x0F",
+		"help.rules": "\x0309 * \x0Fลูบ ให้ความรัก ให้อาหาร ให้แคทนิป หรือเล่นเลเซอร์กับฉัน เพื่อเพิ่ม \x0313Love Meter\x0F ❤️ \x0311(0–100%)\x0F\n" +
+			"\x0309 * \x0Fถึง \x0303100%\x0F ❤️ แล้วจะ \x0313ผูกพัน (Bonded)\x0F —> ปลดล็อก \x0310BondPoints รายวัน\x0F ⭐\n" +
+			"\x0309 * \x0FBondPoints ได้ \x0311วันละครั้ง\x0F ระหว่างที่ผูกพัน \x0307(ต่อเนื่องหลายวันได้โบนัส)\x0F\n" +
+			"\x0309 * \x0Fถ้าทิ้งฉันไปหนึ่งวัน ความผูกพันอาจค่อยๆ จางลง... \x0304</3\x0F\n" +
+			"\x0309 * \x0Fผูกพันต่อเนื่องนานๆ จะปลดล็อก \x0313ของขวัญลับ\x0F และ \x0310ฉายาพิเศษ\x0F 🎁",
+		"help.commands": "\x0310🐾 = คำสั่งที่ใช้ได้ = 🐾\x0F",
+		"help.tip":      "\x0313= เคล็ดลับ =\x0F แวะมา \x0311ทุกวัน\x0F เพื่อรักษาความผูกพันและปลดล็อก \x0303รางวัลหายาก\x0F ✨",
+		"help.page":     "\x0307หน้า %d/%d\x0F — พิมพ์ \x0311%s %d\x0F เพื่อดูต่อ หรือ \x0311!help <คำสั่ง>\x0F เพื่อดูรายละเอียด",
+		"help.nopage":   "ความช่วยเหลือมีแค่ %d หน้าเท่านั้น 😺",
+		"help.unknown":  "ไม่รู้จักคำสั่ง %s 😿 — ลอง !help",
+		"help.usage":    "\x0311%s\x0F \x0307::::\x0F %s",
+		"help.aliases":  "ชื่ออื่น: %s",
+		"help.cooldown": "คูลดาวน์: %s",
+		"help.needs":    "ต้องมีสิทธิ์: %s",
+
+		"throttle.slowdown": "😾 ช้าลงหน่อย %s... ขอพักหายใจแป๊บนึง 🐾",
+
+		"error.usage":       "😼 วิธีใช้: %s",
+		"error.denied":      "🚫 %s: !%s ต้องมีสิทธิ์ %s",
+		"error.unavailable": "😿 ขอโทษนะ %s, Purrito หาสมุดจดไม่เจอ... ลองใหม่อีกสักพักนะ 🐾",
+		"error.internal":    "🙀 อุ๊ย %s, Purrito ทำของตกจากโต๊ะ... ลองใหม่ทีหลังนะ 🐾",
+
+		"cmd.pet":     "ลูบหัวฉันสิ อาจจะคราง... หรือข่วน! 🐾",
+		"cmd.love":    "ให้ความรักฉันหน่อย... รักมาก ครางมาก 💗",
+		"cmd.feed":    "ให้ขนมอร่อยๆ กับฉัน 🍣 🍗 🍤 🍉",
+		"cmd.slap":    "แกล้งฉัน... แต่ระวังตัวด้วยนะ 👋😼",
+		"cmd.kick":    "อย่าแม้แต่จะคิด... ฉันจำนะ 😾",
+		"cmd.catnip":  "ให้แคทนิปเพื่อให้ฉันอารมณ์ดี 🌿😸",
+		"cmd.laser":   "ดูว่าฉันวิ่งไล่เลเซอร์ครั้งล่าสุดเมื่อไหร่ 🔦⚡️",
+		"cmd.status":  "ดูความรัก อารมณ์ ความผูกพัน และของขวัญของคุณ ❤️😽",
+		"cmd.toplove": "ดูว่าฉันรักใครมากที่สุด 💖",
+		"cmd.history": "ดูว่าคุณ (หรือคนอื่น) กับฉันทำอะไรกันไปบ้างเมื่อเร็วๆ นี้ 📜",
+		"cmd.invite":  "ชวนฉันไปห้องของคุณ 📨",
+		"cmd.purrito": "หน้านี้แหละ ใส่เลขหน้าหรือชื่อคำสั่งได้ 📖",
+	},
+}
+
+// Locale looks up help text in one language, falling back to English.
+type Locale string
+
+func NewLocale(lang string) Locale {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if _, ok := catalogs[lang]; !ok {
+		lang = defaultLanguage
+	}
+	return Locale(lang)
+}
+
+// T formats the message for key. Unknown keys are returned as-is.
+func (l Locale) T(key string, args ...any) string {
+	msg, ok := catalogs[string(l)][key]
+	if !ok {
+		if msg, ok = catalogs[defaultLanguage][key]; !ok {
+			return key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Has reports whether key exists in this language or the fallback.
+func (l Locale) Has(key string) bool {
+	if _, ok := catalogs[string(l)][key]; ok {
+		return true
+	}
+	_, ok := catalogs[defaultLanguage][key]
+	return ok
+}
+
+// Lines splits a multi-line message into one IRC line each.
+func (l Locale) Lines(key string) []string {
+	return strings.Split(l.T(key), "\n")
+}
+
+// Description returns the localized description of cmd.
+func (l Locale) Description(cmd *Command) string {
+	if key := "cmd." + cmd.Name; l.Has(key) {
+		return l.T(key)
+	}
+	return cmd.Description
+}
@@ -2,44 +2,178 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/services/catbot"
 	"github.com/MyelinBots/catbot-go/internal/services/context_manager"
 )
 
+// Help delivery modes: NOTICE or PRIVMSG to the asking nick, or the channel itself.
+const (
+	HelpNotice  = "notice"
+	HelpPrivmsg = "privmsg"
+	HelpChannel = "channel"
+
+	defaultHelpPageSize = 10
+)
+
+// WithHelp sets how help is delivered, its language and how many lines fit on a page.
+func WithHelp(delivery, language string, pageSize int) Option {
+	return func(c *CommandControllerImpl) {
+		switch strings.ToLower(strings.TrimSpace(delivery)) {
+		case HelpPrivmsg:
+			c.helpDelivery = HelpPrivmsg
+		case HelpChannel:
+			c.helpDelivery = HelpChannel
+		default:
+			c.helpDelivery = HelpNotice
+		}
+		c.locale = NewLocale(language)
+		if pageSize > 0 {
+			c.helpPageSize = pageSize
+		}
+	}
+}
+
+// WithHelpClient sends help through client instead of the game's IRC client,
+// e.g. a low priority outbound queue so help never delays game events.
+func WithHelpClient(client catbot.IRCClient) Option {
+	return func(c *CommandControllerImpl) { c.helpClient = client }
+}
+
+// PurritoHandler: "!purrito [page]" and "!help [page|command]".
 func (c *CommandControllerImpl) PurritoHandler() func(ctx context.Context, args ...string) error {
 	return func(ctx context.Context, args ...string) error {
 		nick := context_manager.GetNickContext(ctx)
 
-		lines := []string{
-			"🐱 Hi " + nick + "! I am \x0303Purrito\x0F — your friendly IRC cat on the \x0311DarkWorld Network\x0F",
-			"",
-			"\x0310✨ = How the game works = ✨\x0F",
-			"\x0309 * \x0FPet, love, feed, catnip or laser with me to increase your \x0313Love Meter\x0F ❤️ \x0311(0–100%)\x0F",
-			"\x0309 * \x0FReach \x0303100%\x0F ❤️ to become \x0313Bonded\x0F —> this unlocks \x0310daily BondPoints\x0F ⭐",
-			"\x0309 * \x0FBondPoints are earned \x0311once per day\x0F while bonded \x0307(streaks give bonus points)\x0F",
-			"\x0309 * \x0FIf you ignore me for a day, your bond may slowly fade... \x0304</3\x0F",
-			"\x0309 * \x0FLong bonding streaks unlock \x0313secret gifts\x0F and \x0310special titles\x0F 🎁",
-			"",
-			"\x0310🐾 = Commands you can use = 🐾\x0F",
-			"\x0311 * \x0F!pet purrito \x0307::::\x0F Pet me, maybe I will purr... or scratch! 🐾",
-			"\x0311 * \x0F!love purrito \x0307::::\x0F Show me some love... more love, more purrs 💗",
-			"\x0311 * \x0F!feed purrito \x0307::::\x0F Feed me some tasty treats 🍣 🍗 🍤 🍉",
-			"\x0311 * \x0F!slap purrito \x0307::::\x0F Tease me... but be careful 👋😼",
-			"\x0311 * \x0F!catnip purrito \x0307::::\x0F Give me some catnip to boost my mood 🌿😸",
-			"\x0311 * \x0F!laser purrito \x0307::::\x0F Find out when I was last seen chasing lasers 🔦⚡️",
-			"\x0311 * \x0F!status purrito \x0307::::\x0F Check your love, mood, bond & gifts ❤️😽",
-			"\x0311 * \x0F!toplove \x0307::::\x0F See who I love the most 💖",
-			"",
-			"\x0313= Tip =\x0F Come back \x0311every day\x0F to keep our bond strong and unlock \x0303rare rewards\x0F ✨",
-		}
-
-		for _, l := range lines {
-			// keep each message reasonably short to avoid server truncation
-			if len(l) > 400 {
-				l = l[:400]
-			}
-			c.game.IrcClient.Privmsg(c.game.Channel, l)
+		var fields []string
+		if len(args) > 0 {
+			fields = strings.Fields(args[0])
 		}
+		cmd := "!purrito"
+		if len(fields) > 0 {
+			cmd, fields = fields[0], fields[1:]
+		}
+
+		c.showHelp(nick, cmd, fields)
+		return nil
+	}
+}
+
+// HelpHandler is PurritoHandler for commands registered with Register.
+func (c *CommandControllerImpl) HelpHandler() HandlerFunc {
+	return func(ctx context.Context, inv *Invocation) error {
+		c.showHelp(inv.Nick, "!"+inv.Name, inv.Args)
 		return nil
 	}
 }
+
+func (c *CommandControllerImpl) showHelp(nick, cmd string, args []string) {
+	if len(args) > 0 {
+		if _, err := strconv.Atoi(args[0]); err != nil {
+			c.sendHelp(nick, c.commandHelp(args[0]))
+			return
+		}
+	}
+
+	page := 1
+	if len(args) > 0 {
+		page, _ = strconv.Atoi(args[0])
+	}
+
+	lines, pages := paginate(c.helpLines(nick), c.helpPageSize, page)
+	if lines == nil {
+		c.sendHelp(nick, []string{c.locale.T("help.nopage", pages)})
+		return
+	}
+	if page < pages {
+		lines = append(lines, c.locale.T("help.page", page, pages, cmd, page+1))
+	}
+	c.sendHelp(nick, lines)
+}
+
+// helpLines is the whole help text before pagination.
+func (c *CommandControllerImpl) helpLines(nick string) []string {
+	lines := []string{c.locale.T("help.greeting", nick), c.locale.T("help.game")}
+	lines = append(lines, c.locale.Lines("help.rules")...)
+	lines = append(lines, c.locale.T("help.commands"))
+	lines = append(lines, c.commandLines()...)
+	return append(lines, c.locale.T("help.tip"))
+}
+
+// commandLines lists every public command from its registered metadata.
+func (c *CommandControllerImpl) commandLines() []string {
+	var out []string
+	for _, cmd := range c.Commands() {
+		desc := c.locale.Description(&cmd)
+		if cmd.Hidden || cmd.Privilege > PrivilegeNone || desc == "" {
+			continue
+		}
+		out = append(out, fmt.Sprintf("\x0311 * \x0F%s \x0307::::\x0F %s", usageOf(&cmd), desc))
+	}
+	return out
+}
+
+// commandHelp describes one command: usage, aliases, cooldown and privilege.
+func (c *CommandControllerImpl) commandHelp(name string) []string {
+	cmd, ok := c.router.Lookup(name)
+	if !ok || cmd.Hidden {
+		return []string{c.locale.T("help.unknown", name)}
+	}
+
+	out := []string{c.locale.T("help.usage", usageOf(cmd), c.locale.Description(cmd))}
+
+	var extra []string
+	if len(cmd.Aliases) > 0 {
+		aliases := make([]string, len(cmd.Aliases))
+		for i, a := range cmd.Aliases {
+			aliases[i] = "!" + a
+		}
+		extra = append(extra, c.locale.T("help.aliases", strings.Join(aliases, ", ")))
+	}
+	if cmd.Cooldown > 0 {
+		extra = append(extra, c.locale.T("help.cooldown", cmd.Cooldown.Round(time.Second)))
+	}
+	if cmd.Privilege > PrivilegeNone {
+		extra = append(extra, c.locale.T("help.needs", cmd.Privilege))
+	}
+	if len(extra) > 0 {
+		out = append(out, strings.Join(extra, " • "))
+	}
+	return out
+}
+
+// paginate returns page (1-based) of lines and the page count; nil when page is out of range.
+func paginate(lines []string, size, page int) ([]string, int) {
+	if size <= 0 {
+		size = defaultHelpPageSize
+	}
+	pages := (len(lines) + size - 1) / size
+	if page < 1 || page > pages {
+		return nil, pages
+	}
+	end := page * size
+	if end > len(lines) {
+		end = len(lines)
+	}
+	return lines[(page-1)*size : end], pages
+}
+
+// sendHelp delivers help to nick without flooding the channel.
+func (c *CommandControllerImpl) sendHelp(nick string, lines []string) {
+	client := c.replyClient()
+
+	for _, l := range lines {
+		switch {
+		case nick == "" || c.helpDelivery == HelpChannel:
+			client.Privmsg(c.game.Channel, l)
+		case c.helpDelivery == HelpPrivmsg:
+			client.Privmsg(nick, l)
+		default:
+			client.Notice(nick, l)
+		}
+	}
+}
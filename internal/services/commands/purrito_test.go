@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	irc "github.com/fluffle/goirc/client"
+)
+
+func setupHelp(opts ...Option) (*mockIRCClient, CommandController) {
+	client, _, cb, _ := setupTest()
+	cc := NewCommandController(cb, opts...)
+
+	cc.Register(Command{Name: "pet", Usage: "!pet purrito", Description: "Pet me", Handler: noop})
+	cc.Register(Command{Name: "toplove", Aliases: []string{"top"}, Usage: "!toplove", Description: "Top lovers", Cooldown: 30 * time.Second, Handler: noop})
+	cc.Register(Command{Name: "purrito", Aliases: []string{"help"}, Usage: "!purrito", Description: "Help", Handler: cc.(*CommandControllerImpl).HelpHandler()})
+	return client, cc
+}
+
+func say(cc CommandController, msg string) {
+	cc.HandleCommand(context.Background(), &irc.Line{Nick: "player1", Args: []string{"#testchan", msg}})
+}
+
+func TestHelp_NoticeToNickByDefault(t *testing.T) {
+	client, cc := setupHelp()
+
+	say(cc, "!purrito")
+
+	if len(client.notices["player1"]) == 0 {
+		t.Fatal("expected help as NOTICE to the asking nick")
+	}
+	if len(client.notices) != 1 {
+		t.Errorf("help should only go to the asking nick, got targets %v", client.notices)
+	}
+	all := strings.Join(client.notices["player1"], "\n")
+	for _, want := range []string{"!pet purrito", "Pet me", "!toplove", "Top lovers"} {
+		if !strings.Contains(all, want) {
+			t.Errorf("help missing %q", want)
+		}
+	}
+}
+
+func TestHelp_Paginated(t *testing.T) {
+	client, cc := setupHelp(WithHelp(HelpNotice, "en", 4))
+
+	say(cc, "!purrito")
+	first := client.notices["player1"]
+	if len(first) != 5 {
+		t.Fatalf("expected 4 lines + footer, got %d", len(first))
+	}
+	if !strings.Contains(first[4], "Page 1/") || !strings.Contains(first[4], "!purrito 2") {
+		t.Errorf("unexpected footer %q", first[4])
+	}
+
+	client.Clear()
+	say(cc, "!help 2")
+	second := client.notices["player1"]
+	if len(second) == 0 || second[0] == first[0] {
+		t.Fatalf("page 2 should differ from page 1, got %v", second)
+	}
+	if !strings.Contains(second[len(second)-1], "!help 3") {
+		t.Errorf("footer should repeat the alias used, got %q", second[len(second)-1])
+	}
+
+	client.Clear()
+	say(cc, "!purrito 99")
+	if got := client.notices["player1"]; len(got) != 1 || !strings.Contains(got[0], "only") {
+		t.Errorf("expected out of range message, got %v", got)
+	}
+}
+
+func TestHelp_CommandDetails(t *testing.T) {
+	client, cc := setupHelp()
+
+	say(cc, "!help top")
+	got := strings.Join(client.notices["player1"], "\n")
+	for _, want := range []string{"!toplove", "Top lovers", "!top", "30s"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("details missing %q in %q", want, got)
+		}
+	}
+
+	client.Clear()
+	say(cc, "!help nosuch")
+	if !strings.Contains(client.LastMessage(), "nosuch") {
+		t.Errorf("expected unknown command message, got %q", client.LastMessage())
+	}
+}
+
+func TestHelp_DeliveryModes(t *testing.T) {
+	client, cc := setupHelp(WithHelp(HelpPrivmsg, "en", 0))
+	say(cc, "!help pet")
+	if len(client.notices) != 0 || client.LastMessage() == "" {
+		t.Error("privmsg delivery should not send notices")
+	}
+
+	client, cc = setupHelp(WithHelp(HelpChannel, "en", 0))
+	say(cc, "!help pet")
+	if len(client.notices) != 0 || client.LastMessage() == "" {
+		t.Error("channel delivery should not send notices")
+	}
+}
+
+func TestHelp_Localized(t *testing.T) {
+	client, cc := setupHelp(WithHelp(HelpNotice, "th", 50))
+	cc.Register(Command{Name: "dance", Usage: "!dance", Description: "Dance with me", Handler: noop})
+
+	say(cc, "!purrito")
+	all := strings.Join(client.notices["player1"], "\n")
+	if !strings.Contains(all, "สวัสดี player1") {
+		t.Errorf("expected Thai greeting, got %q", all)
+	}
+	// no Thai translation for dance: fall back to Command.Description
+	if !strings.Contains(all, "Dance with me") {
+		t.Error("expected English fallback description")
+	}
+}
+
+func TestLocale_Fallbacks(t *testing.T) {
+	if NewLocale("xx") != NewLocale("en") {
+		t.Error("unknown language should fall back to en")
+	}
+
+	th := NewLocale("TH")
+	if got := th.Description(&Command{Name: "pet", Description: "Pet me"}); got == "Pet me" {
+		t.Error("expected Thai description for pet")
+	}
+	if got := th.T("no.such.key"); got != "no.such.key" {
+		t.Errorf("unknown key = %q", got)
+	}
+
+	// every Thai key must exist in English with the same verbs
+	for key, msg := range catalogs["th"] {
+		if strings.HasPrefix(key, "cmd.") {
+			continue
+		}
+		en, ok := catalogs["en"][key]
+		if !ok {
+			t.Errorf("th key %q missing from en", key)
+			continue
+		}
+		if verbs(en) != verbs(msg) {
+			t.Errorf("%q: format verbs differ (en %q, th %q)", key, verbs(en), verbs(msg))
+		}
+	}
+}
+
+// verbs lists the fmt verbs in s, ignoring "100%" style literals.
+func verbs(s string) string {
+	var out []string
+	for i := 0; i < len(s)-1; i++ {
+		if s[i] == '%' && strings.ContainsRune("sdv", rune(s[i+1])) {
+			out = append(out, fmt.Sprintf("%%%c", s[i+1]))
+		}
+	}
+	return strings.Join(out, ",")
+}
@@ -0,0 +1,459 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --------------------------------------------------
+// Command metadata
+// --------------------------------------------------
+
+// Privilege is the minimum channel status needed to run a command.
+type Privilege int
+
+const (
+	PrivilegeNone Privilege = iota
+	PrivilegeVoice
+	PrivilegeHalfOp
+	PrivilegeOp
+	PrivilegeAdmin
+)
+
+func (p Privilege) String() string {
+	switch p {
+	case PrivilegeVoice:
+		return "voice"
+	case PrivilegeHalfOp:
+		return "halfop"
+	case PrivilegeOp:
+		return "op"
+	case PrivilegeAdmin:
+		return "admin"
+	default:
+		return "none"
+	}
+}
+
+// Command describes a registered command. Help output is generated from it.
+type Command struct {
+	Name        string        // canonical name without prefix, e.g. "pet"
+	Aliases     []string      // extra names, e.g. "top" for "toplove"
+	Usage       string        // e.g. "!pet purrito"
+	Description string        // one line shown in help
+	Cooldown    time.Duration // per nick, 0 = none
+	Privilege   Privilege     // minimum privilege, PrivilegeNone = everyone
+	Hidden      bool          // not listed in help
+	Params      []Param       // typed arguments, nil = no validation
+	DefaultArgs []string      // used when the command is given no arguments
+	Handler     HandlerFunc
+}
+
+// HandlerFunc receives the parsed invocation of a command.
+type HandlerFunc func(ctx context.Context, inv *Invocation) error
+
+// Invocation is a single matched command line.
+type Invocation struct {
+	Command *Command
+	Name    string // name as typed, may be an alias, e.g. "help"
+	Nick    string
+	Source  string // nick!user@host
+	Account string // services account from the IRCv3 account-tag, "" when unknown
+	Channel string
+	Raw     string // message as typed, e.g. "Purrito: PET"
+	Message string // canonical form, e.g. "!pet purrito"
+	Args    Args
+}
+
+// --------------------------------------------------
+// Typed arguments
+// --------------------------------------------------
+
+type ArgType int
+
+const (
+	ArgWord    ArgType = iota // any single word
+	ArgInt                    // base 10 integer
+	ArgNick                   // IRC nickname
+	ArgChannel                // #channel or &channel
+	ArgText                   // rest of the line, must be last
+)
+
+// Param declares one positional argument of a command.
+type Param struct {
+	Name     string
+	Type     ArgType
+	Optional bool
+	Literal  string // when set the argument must equal it (case-insensitive)
+}
+
+// ArgError is returned when arguments don't match a command's Params.
+type ArgError struct {
+	Param  string
+	Reason string
+}
+
+func (e *ArgError) Error() string {
+	return fmt.Sprintf("invalid argument %s: %s", e.Param, e.Reason)
+}
+
+// Args holds validated positional arguments.
+type Args []string
+
+func (a Args) Len() int { return len(a) }
+
+// String returns argument i or "" when missing.
+func (a Args) String(i int) string {
+	if i < 0 || i >= len(a) {
+		return ""
+	}
+	return a[i]
+}
+
+// Int returns argument i as an int (0 when missing or not a number).
+func (a Args) Int(i int) int {
+	n, _ := strconv.Atoi(a.String(i))
+	return n
+}
+
+// Rest joins arguments from i to the end.
+func (a Args) Rest(i int) string {
+	if i >= len(a) {
+		return ""
+	}
+	return strings.Join(a[i:], " ")
+}
+
+// ParseArgs validates fields against params. ArgText swallows the remaining fields.
+func ParseArgs(params []Param, fields []string) (Args, error) {
+	if params == nil {
+		return Args(fields), nil
+	}
+
+	out := make(Args, 0, len(params))
+	for i, p := range params {
+		if i >= len(fields) {
+			if p.Optional {
+				break
+			}
+			return nil, &ArgError{Param: p.Name, Reason: "missing"}
+		}
+
+		v := fields[i]
+		if p.Type == ArgText {
+			out = append(out, strings.Join(fields[i:], " "))
+			return out, nil
+		}
+		if err := checkArg(p, v); err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+
+	if len(fields) > len(params) {
+		return nil, &ArgError{Param: fields[len(params)], Reason: "unexpected"}
+	}
+	return out, nil
+}
+
+func checkArg(p Param, v string) error {
+	if p.Literal != "" && !strings.EqualFold(v, p.Literal) {
+		return &ArgError{Param: p.Name, Reason: fmt.Sprintf("expected %q", p.Literal)}
+	}
+
+	switch p.Type {
+	case ArgInt:
+		if _, err := strconv.Atoi(v); err != nil {
+			return &ArgError{Param: p.Name, Reason: "not a number"}
+		}
+	case ArgChannel:
+		if !isChannel(v) {
+			return &ArgError{Param: p.Name, Reason: "not a channel"}
+		}
+	case ArgNick:
+		if !isNick(v) {
+			return &ArgError{Param: p.Name, Reason: "not a nickname"}
+		}
+	}
+	return nil
+}
+
+func isChannel(s string) bool {
+	return len(s) > 1 && (s[0] == '#' || s[0] == '&') && !strings.ContainsAny(s, " ,\x07")
+}
+
+// isNick follows RFC 2812: letter or special first, then letters, digits, specials or '-'.
+func isNick(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', strings.ContainsRune("[]\\`_^{|}", r):
+		case i > 0 && (r >= '0' && r <= '9' || r == '-'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// --------------------------------------------------
+// Router
+// --------------------------------------------------
+
+// Router matches chat lines to commands.
+// A line matches when it starts with one of the prefixes ("!pet purrito", ".pet purrito")
+// or addresses one of the names ("Purrito: pet", "purrito, pet"). Matching is case-insensitive.
+type Router struct {
+	mu       sync.RWMutex
+	prefixes []string
+	names    []string
+	commands map[string]*Command // canonical name + aliases -> command
+	order    []string            // canonical names in registration order
+
+	// nick|command -> end of its cooldown, pruned once a minute
+	cooldown map[string]time.Time
+	pruned   time.Time
+	now      func() time.Time
+}
+
+func NewRouter(prefixes []string, names []string) *Router {
+	r := &Router{
+		commands: make(map[string]*Command),
+		cooldown: make(map[string]time.Time),
+		now:      time.Now,
+	}
+	r.SetPrefixes(prefixes...)
+	r.SetNames(names...)
+	return r
+}
+
+// SetPrefixes replaces the command prefixes. Longer prefixes are tried first.
+func (r *Router) SetPrefixes(prefixes ...string) {
+	var out []string
+	for _, p := range prefixes {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		out = []string{"!"}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return len(out[i]) > len(out[j]) })
+
+	r.mu.Lock()
+	r.prefixes = out
+	r.mu.Unlock()
+}
+
+// SetNames replaces the names the bot answers to when addressed.
+func (r *Router) SetNames(names ...string) {
+	var out []string
+	for _, n := range names {
+		if n = strings.ToLower(strings.TrimSpace(n)); n != "" {
+			out = append(out, n)
+		}
+	}
+
+	r.mu.Lock()
+	r.names = out
+	r.mu.Unlock()
+}
+
+// Register adds cmd, replacing any command with the same name.
+func (r *Router) Register(cmd Command) {
+	cmd.Name = r.canonical(cmd.Name)
+	if cmd.Name == "" {
+		return
+	}
+	aliases := make([]string, 0, len(cmd.Aliases))
+	for _, a := range cmd.Aliases {
+		if a = r.canonical(a); a != "" && a != cmd.Name {
+			aliases = append(aliases, a)
+		}
+	}
+	cmd.Aliases = aliases
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if old, ok := r.commands[cmd.Name]; ok && old.Name == cmd.Name {
+		for _, a := range old.Aliases {
+			if r.commands[a] == old {
+				delete(r.commands, a)
+			}
+		}
+	} else {
+		r.order = append(r.order, cmd.Name)
+	}
+
+	c := &cmd
+	r.commands[cmd.Name] = c
+	for _, a := range aliases {
+		if _, taken := r.commands[a]; !taken {
+			r.commands[a] = c
+		}
+	}
+}
+
+// Lookup finds a command by name or alias, with or without prefix.
+func (r *Router) Lookup(name string) (*Command, bool) {
+	name = r.canonical(name)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.commands[name]
+	return c, ok
+}
+
+// Commands returns registered commands in registration order.
+func (r *Router) Commands() []Command {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	out := make([]Command, 0, len(r.order))
+	for _, name := range r.order {
+		if c, ok := r.commands[name]; ok && c.Name == name {
+			out = append(out, *c)
+		}
+	}
+	return out
+}
+
+// Match parses message into an invocation. ok is false when the line isn't a known command.
+func (r *Router) Match(message string) (inv *Invocation, ok bool) {
+	fields := strings.Fields(message)
+	if len(fields) == 0 {
+		return nil, false
+	}
+
+	var name string
+	switch {
+	case r.addressed(fields[0]):
+		if len(fields) < 2 {
+			return nil, false
+		}
+		// "Purrito: pet" and "Purrito: !pet" are both fine
+		name, fields = fields[1], fields[2:]
+	default:
+		token, hasPrefix := r.stripPrefix(fields[0])
+		if !hasPrefix {
+			return nil, false
+		}
+		name, fields = token, fields[1:]
+	}
+
+	cmd, found := r.Lookup(name)
+	if !found {
+		return nil, false
+	}
+	name = r.canonical(name)
+
+	// subcommands are registered as "name sub", e.g. "purrito set"
+	if len(fields) > 0 {
+		if sub, ok := r.Lookup(cmd.Name + " " + fields[0]); ok {
+			cmd, name, fields = sub, sub.Name, fields[1:]
+		}
+	}
+
+	if len(fields) == 0 && len(cmd.DefaultArgs) > 0 {
+		fields = cmd.DefaultArgs
+	}
+
+	canonical := "!" + cmd.Name
+	if len(fields) > 0 {
+		canonical += " " + strings.Join(fields, " ")
+	}
+
+	return &Invocation{
+		Command: cmd,
+		Name:    name,
+		Raw:     message,
+		Message: canonical,
+		Args:    Args(fields),
+	}, true
+}
+
+// Allow records a use of cmd by nick and reports whether its cooldown has passed.
+func (r *Router) Allow(cmd *Command, nick string) bool {
+	if cmd.Cooldown <= 0 {
+		return true
+	}
+
+	key := strings.ToLower(nick) + "|" + cmd.Name
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune(now)
+
+	if until, ok := r.cooldown[key]; ok && now.Before(until) {
+		return false
+	}
+	r.cooldown[key] = now.Add(cmd.Cooldown)
+	return true
+}
+
+// prune drops ended cooldowns at most once a minute. Caller holds mu.
+func (r *Router) prune(now time.Time) {
+	if now.Sub(r.pruned) < time.Minute {
+		return
+	}
+	r.pruned = now
+
+	for k, until := range r.cooldown {
+		if !now.Before(until) {
+			delete(r.cooldown, k)
+		}
+	}
+}
+
+// canonical lowercases name and strips a leading prefix ("!PET" -> "pet").
+func (r *Router) canonical(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if token, ok := r.stripPrefix(name, "!"); ok {
+		return token
+	}
+	return name
+}
+
+func (r *Router) stripPrefix(token string, extra ...string) (string, bool) {
+	r.mu.RLock()
+	prefixes := append(append([]string{}, r.prefixes...), extra...)
+	r.mu.RUnlock()
+
+	for _, p := range prefixes {
+		if strings.HasPrefix(token, p) && len(token) > len(p) {
+			return strings.ToLower(token[len(p):]), true
+		}
+	}
+	return "", false
+}
+
+func (r *Router) addressed(token string) bool {
+	if len(token) < 2 {
+		return false
+	}
+	last := token[len(token)-1]
+	if last != ':' && last != ',' {
+		return false
+	}
+	token = strings.ToLower(token[:len(token)-1])
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, n := range r.names {
+		if token == n {
+			return true
+		}
+	}
+	return false
+}
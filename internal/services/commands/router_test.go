@@ -0,0 +1,300 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/services/throttle"
+	irc "github.com/fluffle/goirc/client"
+)
+
+func noop(ctx context.Context, inv *Invocation) error { return nil }
+
+func TestRouter_PrefixesAndCase(t *testing.T) {
+	r := NewRouter([]string{"!", "."}, nil)
+	r.Register(Command{Name: "pet", Handler: noop})
+
+	for _, msg := range []string{"!pet purrito", "!PET purrito", ".pet purrito", "  .Pet   purrito "} {
+		inv, ok := r.Match(msg)
+		if !ok {
+			t.Fatalf("%q should match", msg)
+		}
+		if inv.Message != "!pet purrito" {
+			t.Errorf("%q: canonical message = %q", msg, inv.Message)
+		}
+		if inv.Args.String(0) != "purrito" {
+			t.Errorf("%q: args = %v", msg, inv.Args)
+		}
+	}
+
+	for _, msg := range []string{"pet purrito", "~pet purrito", "!", "!unknown"} {
+		if _, ok := r.Match(msg); ok {
+			t.Errorf("%q should not match", msg)
+		}
+	}
+}
+
+func TestRouter_AddressedByName(t *testing.T) {
+	r := NewRouter([]string{"!"}, []string{"Purrito", "catbot"})
+	r.Register(Command{Name: "pet", DefaultArgs: []string{"purrito"}, Handler: noop})
+
+	for _, msg := range []string{"Purrito: pet", "purrito, PET", "catbot: !pet", "Purrito: pet purrito"} {
+		inv, ok := r.Match(msg)
+		if !ok {
+			t.Fatalf("%q should match", msg)
+		}
+		if inv.Message != "!pet purrito" {
+			t.Errorf("%q: canonical message = %q", msg, inv.Message)
+		}
+	}
+
+	for _, msg := range []string{"Purrito:", "Purrito pet", "someone: pet"} {
+		if _, ok := r.Match(msg); ok {
+			t.Errorf("%q should not match", msg)
+		}
+	}
+}
+
+func TestRouter_Aliases(t *testing.T) {
+	r := NewRouter(nil, nil)
+	r.Register(Command{Name: "!toplove", Aliases: []string{"!top", "leaders"}, Handler: noop})
+
+	inv, ok := r.Match("!TOP")
+	if !ok {
+		t.Fatal("alias should match")
+	}
+	if inv.Command.Name != "toplove" || inv.Message != "!toplove" {
+		t.Errorf("alias resolved to %q / %q", inv.Command.Name, inv.Message)
+	}
+	if _, ok := r.Lookup("leaders"); !ok {
+		t.Error("lookup by alias failed")
+	}
+
+	// re-registering drops the old aliases
+	r.Register(Command{Name: "toplove", Handler: noop})
+	if _, ok := r.Match("!top"); ok {
+		t.Error("stale alias still matches")
+	}
+	if got := len(r.Commands()); got != 1 {
+		t.Errorf("expected 1 command, got %d", got)
+	}
+}
+
+func TestRouter_CommandsInRegistrationOrder(t *testing.T) {
+	r := NewRouter(nil, nil)
+	for _, n := range []string{"pet", "love", "feed"} {
+		r.Register(Command{Name: n, Handler: noop})
+	}
+	r.Register(Command{Name: "love", Description: "again", Handler: noop})
+
+	var names []string
+	for _, c := range r.Commands() {
+		names = append(names, c.Name)
+	}
+	if strings.Join(names, ",") != "pet,love,feed" {
+		t.Errorf("order = %v", names)
+	}
+}
+
+func TestRouter_Cooldown(t *testing.T) {
+	r := NewRouter(nil, nil)
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+
+	cmd := &Command{Name: "feed", Cooldown: time.Minute}
+	if !r.Allow(cmd, "alice") {
+		t.Fatal("first use should pass")
+	}
+	if r.Allow(cmd, "ALICE") {
+		t.Error("second use within cooldown should be blocked")
+	}
+	if !r.Allow(cmd, "bob") {
+		t.Error("cooldown is per nick")
+	}
+
+	now = now.Add(time.Minute)
+	if !r.Allow(cmd, "alice") {
+		t.Error("use after cooldown should pass")
+	}
+
+	// ended cooldowns are dropped, so the map doesn't grow with every nick
+	now = now.Add(2 * time.Minute)
+	r.Allow(cmd, "carol")
+	if len(r.cooldown) != 1 {
+		t.Errorf("expected only carol's cooldown to be kept, got %v", r.cooldown)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	params := []Param{
+		{Name: "purrito", Literal: "purrito"},
+		{Name: "#channel", Type: ArgChannel},
+		{Name: "amount", Type: ArgInt, Optional: true},
+	}
+
+	args, err := ParseArgs(params, []string{"Purrito", "#cats", "5"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if args.String(1) != "#cats" || args.Int(2) != 5 {
+		t.Errorf("args = %v", args)
+	}
+
+	if _, err := ParseArgs(params, []string{"purrito", "#cats"}); err != nil {
+		t.Errorf("optional param: %v", err)
+	}
+
+	bad := [][]string{
+		{"dog", "#cats"},
+		{"purrito", "cats"},
+		{"purrito", "#cats", "many"},
+		{"purrito"},
+		{"purrito", "#cats", "1", "extra"},
+	}
+	for _, fields := range bad {
+		if _, err := ParseArgs(params, fields); err == nil {
+			t.Errorf("%v should fail", fields)
+		}
+	}
+
+	text, err := ParseArgs([]Param{{Name: "nick", Type: ArgNick}, {Name: "message", Type: ArgText}}, []string{"alice", "hello", "there"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if text.String(1) != "hello there" {
+		t.Errorf("text = %q", text.String(1))
+	}
+
+	if _, err := ParseArgs([]Param{{Name: "nick", Type: ArgNick}}, []string{"9lives"}); err == nil {
+		t.Error("nick starting with a digit should fail")
+	}
+}
+
+func TestHandleCommand_PrefixCaseAndAddressing(t *testing.T) {
+	_, _, cb, _ := setupTest()
+	cc := NewCommandController(cb, WithPrefixes("!", "."), WithNames("Purrito"))
+
+	var got []string
+	cc.AddCommand("!test", func(ctx context.Context, message string) error {
+		got = append(got, message)
+		return nil
+	})
+
+	for _, msg := range []string{"!TEST one", ".test one", "Purrito: test one"} {
+		line := &irc.Line{Nick: "player1", Args: []string{"#testchan", msg}}
+		if err := cc.HandleCommand(context.Background(), line); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(got))
+	}
+	for _, m := range got {
+		if m != "!test one" {
+			t.Errorf("legacy handler got %q", m)
+		}
+	}
+}
+
+func TestHandleCommand_InvalidArgsShowUsage(t *testing.T) {
+	client, _, _, cc := setupTest()
+
+	called := false
+	cc.Register(Command{
+		Name:   "invite",
+		Usage:  "!invite purrito #channel",
+		Params: InviteParams,
+		Handler: func(ctx context.Context, inv *Invocation) error {
+			called = true
+			return nil
+		},
+	})
+
+	line := &irc.Line{Nick: "player1", Args: []string{"#testchan", "!invite purrito nochannel"}}
+	if err := cc.HandleCommand(context.Background(), line); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if called {
+		t.Error("handler should not run with invalid args")
+	}
+	if !strings.Contains(client.LastMessage(), "!invite purrito #channel") {
+		t.Errorf("expected usage, got %q", client.LastMessage())
+	}
+}
+
+func TestHandleCommand_Privilege(t *testing.T) {
+	client, _, cb, _ := setupTest()
+
+	level := PrivilegeVoice
+	cc := NewCommandController(cb, WithPrivilegeChecker(func(ctx context.Context, line *irc.Line) Privilege { return level }))
+
+	called := 0
+	cc.Register(Command{Name: "reset", Privilege: PrivilegeOp, Handler: func(ctx context.Context, inv *Invocation) error {
+		called++
+		return nil
+	}})
+
+	line := &irc.Line{Nick: "player1", Args: []string{"#testchan", "!reset"}}
+	cc.HandleCommand(context.Background(), line)
+	if called != 0 {
+		t.Error("voice should not run an op command")
+	}
+	if !strings.Contains(client.LastMessage(), "needs op") {
+		t.Errorf("expected privilege message, got %q", client.LastMessage())
+	}
+
+	level = PrivilegeOp
+	cc.HandleCommand(context.Background(), line)
+	if called != 1 {
+		t.Error("op should run an op command")
+	}
+}
+
+func TestPurritoHandler_ListsRegisteredCommands(t *testing.T) {
+	client, _, _, cc := setupTest()
+
+	cc.Register(Command{Name: "pet", Usage: "!pet purrito", Description: "Pet me", Handler: noop})
+	cc.Register(Command{Name: "secret", Usage: "!secret", Description: "hidden", Hidden: true, Handler: noop})
+	cc.Register(Command{Name: "reset", Usage: "!reset", Description: "ops only", Privilege: PrivilegeOp, Handler: noop})
+
+	if err := cc.(*CommandControllerImpl).PurritoHandler()(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	all := strings.Join(client.messages, "\n")
+	if !strings.Contains(all, "!pet purrito") || !strings.Contains(all, "Pet me") {
+		t.Error("registered command missing from help")
+	}
+	if strings.Contains(all, "!secret") || strings.Contains(all, "!reset") {
+		t.Error("hidden or privileged command listed in help")
+	}
+}
+
+func TestHandleCommand_Throttle(t *testing.T) {
+	client, _, cb, _ := setupTest()
+	th := throttle.New(throttle.Config{
+		Default: throttle.Rule{throttle.ScopeNick: {Count: 1, Per: time.Minute}},
+		Notify:  true,
+	})
+	cc := NewCommandController(cb, WithThrottle(th))
+
+	called := 0
+	cc.Register(Command{Name: "status", Handler: func(ctx context.Context, inv *Invocation) error {
+		called++
+		return nil
+	}})
+
+	for i := 0; i < 3; i++ {
+		cc.HandleCommand(context.Background(), &irc.Line{Nick: "player1", Host: "cat.lover", Args: []string{"#testchan", "!status"}})
+	}
+
+	if called != 1 {
+		t.Errorf("expected 1 call through the throttle, got %d", called)
+	}
+	if got := client.notices["player1"]; len(got) != 1 || !strings.Contains(got[0], "Slow down") {
+		t.Errorf("expected a single slow down notice, got %q", got)
+	}
+}
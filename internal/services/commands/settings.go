@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/settings"
+)
+
+// --------------------------------------------------
+// Channel settings
+// "!purrito set [key] [value]" for channel operators. Changes are stored
+// per channel and applied to the running game right away.
+// --------------------------------------------------
+
+// RegisterSettings registers "!purrito set" backed by store.
+func (c *CommandControllerImpl) RegisterSettings(store *settings.Store) {
+	s := &settingsCommands{c: c, store: store}
+	c.Register(Command{
+		Name:        "purrito set",
+		Usage:       "!purrito set [key] [value|default]",
+		Description: "Tune Purrito for this channel ⚙️",
+		Privilege:   PrivilegeOp,
+		Params:      []Param{{Name: "key", Optional: true}, {Name: "value", Type: ArgText, Optional: true}},
+		Handler:     s.set,
+	})
+}
+
+type settingsCommands struct {
+	c     *CommandControllerImpl
+	store *settings.Store
+}
+
+// commands that can't be disabled, or nobody could turn them back on
+var alwaysEnabled = map[string]bool{"purrito": true, "purrito set": true}
+
+func (s *settingsCommands) set(ctx context.Context, inv *Invocation) error {
+	ca, ok := s.c.game.CatActions.(*cat_actions.CatActions)
+	if !ok {
+		return errNoGame
+	}
+	key, value := strings.ToLower(inv.Args.String(0)), inv.Args.String(1)
+	current := ca.Settings()
+
+	switch {
+	case key == "":
+		var parts []string
+		for _, k := range settings.Keys {
+			parts = append(parts, fmt.Sprintf("%s=%s", k, current.Get(k)))
+		}
+		s.c.replyClient().Notice(inv.Nick, "⚙️ "+strings.Join(parts, " • "))
+		return nil
+	case !settings.Known(key):
+		return &UsageError{
+			Usage:  fmt.Sprintf("!purrito set [%s] [value|default]", strings.Join(settings.Keys, "|")),
+			Reason: fmt.Sprintf("unknown setting %q", key),
+		}
+	case value == "":
+		s.say("⚙️ %s = %s", key, current.Get(key))
+		return nil
+	}
+
+	var (
+		next settings.Settings
+		err  error
+	)
+	switch {
+	case strings.EqualFold(value, "default"):
+		next, err = s.store.Reset(ctx, ca.Network, ca.Channel, key)
+	case key == settings.KeyDisabled:
+		if value, err = s.disabledCommands(value); err != nil {
+			return valueUsage(key, err)
+		}
+		next, err = s.store.Set(ctx, ca.Network, ca.Channel, key, value, inv.Source)
+	default:
+		next, err = s.store.Set(ctx, ca.Network, ca.Channel, key, value, inv.Source)
+	}
+	var invalid *settings.InvalidError
+	if errors.As(err, &invalid) {
+		return valueUsage(key, err)
+	}
+	if err != nil {
+		return Unavailable("set "+key, err)
+	}
+
+	ca.ApplySettings(next)
+	s.say("⚙️ %s set %s to %s", inv.Nick, key, next.Get(key))
+	return nil
+}
+
+// valueUsage shows what key accepts, since the usage line alone can't say.
+func valueUsage(key string, reason error) error {
+	return &UsageError{
+		Usage:  fmt.Sprintf("!purrito set %s [value|default] (%v)", key, reason),
+		Reason: reason.Error(),
+	}
+}
+
+// disabledCommands resolves aliases ("top" -> "toplove") and rejects commands
+// that don't exist, aren't public or must stay on.
+func (s *settingsCommands) disabledCommands(value string) (string, error) {
+	if strings.EqualFold(strings.TrimSpace(value), "none") {
+		return "none", nil
+	}
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		cmd, ok := s.c.router.Lookup(name)
+		if !ok || cmd.Hidden || cmd.Privilege > PrivilegeNone {
+			return "", fmt.Errorf("there is no !%s to disable", strings.TrimPrefix(name, "!"))
+		}
+		if alwaysEnabled[cmd.Name] {
+			return "", fmt.Errorf("!%s can't be disabled", cmd.Name)
+		}
+		names = append(names, cmd.Name)
+	}
+	return strings.Join(names, ","), nil
+}
+
+func (s *settingsCommands) say(format string, args ...any) {
+	s.c.game.IrcClient.Privmsg(s.c.game.Channel, fmt.Sprintf(format, args...))
+}
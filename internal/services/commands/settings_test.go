@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_settings"
+	"github.com/MyelinBots/catbot-go/internal/services/cat_actions"
+	"github.com/MyelinBots/catbot-go/internal/services/settings"
+	irc "github.com/fluffle/goirc/client"
+)
+
+type settingsSetup struct {
+	client *mockIRCClient
+	cc     CommandController
+	ca     *cat_actions.CatActions
+	repo   channel_settings.ChannelSettingsRepository
+	level  Privilege
+}
+
+func setupSettings() *settingsSetup {
+	client, _, cb, _ := setupTest()
+	s := &settingsSetup{client: client, ca: cb.CatActions.(*cat_actions.CatActions), repo: channel_settings.NewMemoryChannelSettingsRepository(), level: PrivilegeOp}
+
+	cc := NewCommandController(cb, WithPrivilegeChecker(func(context.Context, *irc.Line) Privilege { return s.level }))
+	impl := cc.(*CommandControllerImpl)
+	impl.Register(Command{Name: "pet", Handler: func(context.Context, *Invocation) error { client.Privmsg("#testchan", "purr"); return nil }})
+	impl.Register(Command{Name: "toplove", Aliases: []string{"top"}, Handler: func(context.Context, *Invocation) error { return nil }})
+	impl.Register(Command{Name: "purrito", Aliases: []string{"help"}, Handler: impl.HelpHandler()})
+	impl.RegisterSettings(settings.NewStore(s.repo, settings.Defaults()))
+	s.cc = cc
+	return s
+}
+
+func (s *settingsSetup) say(msg string) {
+	s.client.Clear()
+	s.cc.HandleCommand(context.Background(), &irc.Line{Nick: "op", Ident: "op", Host: "host", Args: []string{"#testchan", msg}})
+}
+
+func TestSettings_SetAppliesLiveAndPersists(t *testing.T) {
+	s := setupSettings()
+
+	s.say("!purrito set accept_chance 90")
+	if got := s.ca.Settings().AcceptChance; got != 90 {
+		t.Fatalf("accept chance = %d, want 90", got)
+	}
+	if !strings.Contains(s.client.LastMessage(), "accept_chance to 90%") {
+		t.Errorf("got %q", s.client.LastMessage())
+	}
+	stored, _ := s.repo.GetSettings(context.Background(), "testnet", "#testchan")
+	if stored["accept_chance"] != "90%" {
+		t.Errorf("stored %v", stored)
+	}
+
+	s.say("!purrito set catnip_cooldown 2h")
+	if got := s.ca.Settings().CatnipCooldown; got != 2*time.Hour {
+		t.Errorf("catnip cooldown = %v", got)
+	}
+
+	s.say("!purrito set accept_chance default")
+	if got := s.ca.Settings().AcceptChance; got != 60 {
+		t.Errorf("reset accept chance = %d, want 60", got)
+	}
+}
+
+func TestSettings_InvalidValues(t *testing.T) {
+	s := setupSettings()
+
+	for _, msg := range []string{
+		"!purrito set accept_chance 150",
+		"!purrito set min_respawn 2h",
+		"!purrito set colour orange",
+		"!purrito set disabled purrito",
+		"!purrito set disabled nosuchcommand",
+		"!purrito set colour",
+	} {
+		s.say(msg)
+		if !strings.HasPrefix(s.client.LastMessage(), "😼 Usage: !purrito set") {
+			t.Errorf("%s: expected the usage, got %q", msg, s.client.LastMessage())
+		}
+	}
+	s.say("!purrito set accept_chance 150")
+	if got := s.client.LastMessage(); !strings.Contains(got, "accept_chance wants a number from 0 to 100") {
+		t.Errorf("usage should say what the setting accepts, got %q", got)
+	}
+	if s.ca.Settings().AcceptChance != 60 {
+		t.Error("invalid values must not change the settings")
+	}
+}
+
+// brokenSettings fails to save like a database that went away
+type brokenSettings struct {
+	channel_settings.ChannelSettingsRepository
+}
+
+func (brokenSettings) SetSetting(context.Context, string, string, string, string, string) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connection refused")
+}
+
+func TestSettings_StorageFailure(t *testing.T) {
+	s := setupSettings()
+	impl := s.cc.(*CommandControllerImpl)
+	impl.RegisterSettings(settings.NewStore(brokenSettings{s.repo}, settings.Defaults()))
+
+	s.say("!purrito set accept_chance 90")
+	got := s.client.LastMessage()
+	if !strings.Contains(got, "can't find his notebook") || strings.Contains(got, "10.0.0.5") {
+		t.Errorf("got %q", got)
+	}
+	if s.ca.Settings().AcceptChance != 60 {
+		t.Error("a failed save must not change the settings")
+	}
+}
+
+func TestSettings_NeedsOp(t *testing.T) {
+	s := setupSettings()
+	s.level = PrivilegeVoice
+
+	s.say("!purrito set accept_chance 10")
+	if s.ca.Settings().AcceptChance != 60 {
+		t.Fatal("voiced users must not change settings")
+	}
+	if !strings.Contains(s.client.LastMessage(), "needs op") {
+		t.Errorf("got %q", s.client.LastMessage())
+	}
+}
+
+func TestSettings_DisabledCommandsAreIgnored(t *testing.T) {
+	s := setupSettings()
+
+	s.say("!purrito set disabled pet,top")
+	if got := s.ca.Settings().Get(settings.KeyDisabled); got != "pet,toplove" {
+		t.Fatalf("disabled = %q", got)
+	}
+
+	s.say("!pet purrito")
+	if len(s.client.messages) != 0 {
+		t.Errorf("disabled command answered: %q", s.client.messages)
+	}
+
+	s.say("!purrito set disabled none")
+	s.say("!pet purrito")
+	if s.client.LastMessage() != "purr" {
+		t.Errorf("re-enabled command should answer, got %q", s.client.messages)
+	}
+}
+
+func TestSettings_ShowValues(t *testing.T) {
+	s := setupSettings()
+
+	s.say("!purrito set")
+	if n := s.client.notices["op"]; len(n) != 1 || !strings.Contains(n[0], "spawn_window=30m") {
+		t.Errorf("expected every setting in a notice, got %q", n)
+	}
+
+	s.say("!purrito set decay")
+	if s.client.LastMessage() != "⚙️ decay = 5" {
+		t.Errorf("got %q", s.client.LastMessage())
+	}
+
+	// plain !purrito still shows help
+	s.say("!purrito 1")
+	if len(s.client.notices["op"]) == 0 || strings.Contains(s.client.notices["op"][0], "spawn_window") {
+		t.Errorf("!purrito <page> should still be help, got %q", s.client.notices["op"])
+	}
+}
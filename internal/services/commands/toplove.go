@@ -22,11 +22,7 @@ func (c *CommandControllerImpl) TopLove10Handler() func(ctx context.Context, arg
 
 		players, err := c.game.CatPlayerRepo.TopLoveMeter(ctx, c.game.Network, c.game.Channel, 10)
 		if err != nil {
-			// TEMP: surface the real error so we can fix it
-			errMsg := fmt.Sprintf("toplove error: %v", err)
-			fmt.Println("[toplove]", errMsg)
-			c.game.IrcClient.Privmsg(c.game.Channel, errMsg)
-			return err
+			return Unavailable("load top lovers", err)
 		}
 
 		if len(players) == 0 {
@@ -3,12 +3,17 @@ package lovemeter
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+	"github.com/MyelinBots/catbot-go/internal/services/streak"
 )
 
 // --------------------------------------------------
@@ -16,8 +21,12 @@ import (
 // --------------------------------------------------
 
 type LoveMeter interface {
-	Increase(player string, amount int)
-	Decrease(player string, amount int)
+	// Add changes the player's love by delta and returns it from before and
+	// after the change, both from the same atomic update.
+	Add(ctx context.Context, player string, delta int) (before, after int, err error)
+	// Increase and Decrease return the player's new love.
+	Increase(player string, amount int) int
+	Decrease(player string, amount int) int
 
 	Get(player string) int
 	GetLoveBar(player string) string
@@ -31,18 +40,72 @@ type LoveMeter interface {
 	DailyDecayAll(ctx context.Context) error
 }
 
+// DefaultDailyDecay is the love a bonded player loses for each day they skip.
+const DefaultDailyDecay = 5
+
 type LoveMeterImpl struct {
 	catPlayerRepo cat_player.CatPlayerRepository
 	Network       string
 	Channel       string
+
+	dailyDecay atomic.Int64
+	cal        *calendar.Calendar
+	log        *slog.Logger
+	eventLog   cat_event.CatEventRepository // nil = decay isn't logged
+	streaks    streak.Service
+}
+
+// Option configures the love meter.
+type Option func(*LoveMeterImpl)
+
+// WithCalendar sets the game day used for BondPoints and decay
+// (default: calendar.DefaultTimezone).
+func WithCalendar(cal *calendar.Calendar) Option {
+	return func(lm *LoveMeterImpl) { lm.cal = cal }
+}
+
+// WithLogger sets the logger for storage errors (default: slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(lm *LoveMeterImpl) { lm.log = l }
+}
+
+// WithEventLog appends every decay to repo.
+func WithEventLog(repo cat_event.CatEventRepository) Option {
+	return func(lm *LoveMeterImpl) { lm.eventLog = repo }
+}
+
+// WithStreaks sets the daily streaks that decay breaks
+// (default: a streak.Service on the same repository and calendar).
+func WithStreaks(s streak.Service) Option {
+	return func(lm *LoveMeterImpl) { lm.streaks = s }
 }
 
-func NewLoveMeter(catPlayerRepo cat_player.CatPlayerRepository, network, channel string) LoveMeter {
-	return &LoveMeterImpl{
+func NewLoveMeter(catPlayerRepo cat_player.CatPlayerRepository, network, channel string, opts ...Option) LoveMeter {
+	lm := &LoveMeterImpl{
 		catPlayerRepo: catPlayerRepo,
 		Network:       network,
 		Channel:       channel,
 	}
+	lm.dailyDecay.Store(DefaultDailyDecay)
+	for _, opt := range opts {
+		opt(lm)
+	}
+	if lm.cal == nil {
+		lm.cal = calendar.Default()
+	}
+	if lm.streaks == nil {
+		lm.streaks = streak.New(catPlayerRepo, streak.WithCalendar(lm.cal))
+	}
+	lm.log = logging.Or(lm.log)
+	return lm
+}
+
+// SetDailyDecay changes the daily decay; 0 turns decay off for the channel.
+func (lm *LoveMeterImpl) SetDailyDecay(amount int) {
+	if amount < 0 {
+		amount = 0
+	}
+	lm.dailyDecay.Store(int64(amount))
 }
 
 // --------------------------------------------------
@@ -88,76 +151,44 @@ func RenderLoveBar(love int) string {
 	return bar
 }
 
-// --------------------------------------------------
-// Time Helpers
-// --------------------------------------------------
-
-func nyNow() time.Time {
-	loc, err := time.LoadLocation("America/New_York")
-	if err != nil {
-		return time.Now()
-	}
-	return time.Now().In(loc)
-}
-
-func sameDayNY(a, b time.Time) bool {
-	loc, _ := time.LoadLocation("America/New_York")
-	aa := a.In(loc)
-	bb := b.In(loc)
-	return aa.Year() == bb.Year() && aa.YearDay() == bb.YearDay()
-}
-
-func sameDay(a, b time.Time) bool {
-	ay, am, ad := a.Date()
-	by, bm, bd := b.Date()
-	return ay == by && am == bm && ad == bd
-}
-
 // --------------------------------------------------
 // Persistence
 // --------------------------------------------------
 
-func (lm *LoveMeterImpl) persistLove(key string, love int) {
-	ctx := context.Background()
-
-	// ensure row exists (DO NOT overwrite other fields)
-	p, err := lm.catPlayerRepo.GetPlayerByName(ctx, key, lm.Network, lm.Channel)
+// Add changes a player's love by delta (clamped to 0..100) in one atomic
+// repository call, creating the player if needed, and returns the love
+// before and after.
+func (lm *LoveMeterImpl) Add(ctx context.Context, player string, delta int) (before, after int, err error) {
+	p, before, err := lm.catPlayerRepo.AddLove(ctx, norm(player), lm.Network, lm.Channel, delta)
 	if err != nil {
-		log.Printf("failed to load player %s: %v", key, err)
-		return
-	}
-	if p == nil {
-		_ = lm.catPlayerRepo.UpsertPlayer(ctx, &cat_player.CatPlayer{
-			Name:    key,
-			Network: lm.Network,
-			Channel: lm.Channel,
-		})
-	}
-
-	// update ONLY love_meter
-	if err := lm.catPlayerRepo.SetLoveMeter(ctx, key, lm.Network, lm.Channel, love); err != nil {
-		log.Printf("failed to set love for %s: %v", key, err)
+		return 0, 0, err
 	}
+	return ClampLove(before), ClampLove(p.LoveMeter), nil
 }
 
 // --------------------------------------------------
 // Core API (mutations + reads)
 // --------------------------------------------------
 
-func (lm *LoveMeterImpl) Increase(player string, amount int) {
-	key := norm(player)
-	current := lm.Get(key)
-
-	newVal := ClampLove(current + amount)
-	lm.persistLove(key, newVal)
+// Increase adds amount to the player's love and returns the new love (the
+// stored love if the change could not be saved).
+func (lm *LoveMeterImpl) Increase(player string, amount int) int {
+	_, love, err := lm.Add(context.Background(), player, amount)
+	if err != nil {
+		lm.log.Error("failed to increase love", logging.KeyNick, norm(player), "error", err)
+		return lm.Get(player)
+	}
+	return love
 }
 
-func (lm *LoveMeterImpl) Decrease(player string, amount int) {
-	key := norm(player)
-	current := lm.Get(key)
-
-	newVal := ClampLove(current - amount)
-	lm.persistLove(key, newVal)
+// Decrease is Increase with -amount.
+func (lm *LoveMeterImpl) Decrease(player string, amount int) int {
+	_, love, err := lm.Add(context.Background(), player, -amount)
+	if err != nil {
+		lm.log.Error("failed to decrease love", logging.KeyNick, norm(player), "error", err)
+		return lm.Get(player)
+	}
+	return love
 }
 
 func (lm *LoveMeterImpl) Get(player string) int {
@@ -176,8 +207,11 @@ func (lm *LoveMeterImpl) GetLoveBar(player string) string {
 }
 
 func (lm *LoveMeterImpl) GetMood(player string) string {
-	love := lm.Get(player)
+	return MoodFor(lm.Get(player))
+}
 
+// MoodFor is Purrito's mood (IRC-coloured) towards a player with love.
+func MoodFor(love int) string {
 	switch {
 	case love == 0:
 		return "\x0304hostile 😾\x0F" // red
@@ -198,7 +232,7 @@ func (lm *LoveMeterImpl) GetMood(player string) string {
 
 func (lm *LoveMeterImpl) StatusLine(player string) string {
 	love := lm.Get(player)
-	return fmt.Sprintf("%d%% %s %s", love, lm.GetMood(player), lm.GetLoveBar(player))
+	return fmt.Sprintf("%d%% %s %s", love, MoodFor(love), RenderLoveBar(love))
 }
 
 // --------------------------------------------------
@@ -222,53 +256,35 @@ func bondPointsForStreak(streak int) int {
 // RecordInteraction (fixed):
 // - TouchInteraction always (for decay)
 // - Only when love==100 (bonded)
-// - Only once per NY day using LastBondPointsAt
+// - Only once per game day using LastBondPointsAt
 // - Uses CatPlayer.BondPointStreak + repo.SetBondPointStreak
 func (lm *LoveMeterImpl) RecordInteraction(ctx context.Context, player string) (awardedBondPoints int, newStreak int, err error) {
 	key := norm(player)
-	now := nyNow()
+	now := lm.cal.Now()
 
 	// Always mark interaction time (supports decay logic)
 	_ = lm.catPlayerRepo.TouchInteraction(ctx, key, lm.Network, lm.Channel, now)
 
-	// Must load player from DB
 	p, err := lm.catPlayerRepo.GetPlayerByName(ctx, key, lm.Network, lm.Channel)
-	if err != nil {
+	if err != nil || p == nil {
 		return 0, 0, err
 	}
-	if p == nil {
-		// ensure row exists
-		if err := lm.catPlayerRepo.UpsertPlayer(ctx, &cat_player.CatPlayer{
-			Name:    key,
-			Network: lm.Network,
-			Channel: lm.Channel,
-		}); err != nil {
-			return 0, 0, err
-		}
-		p, err = lm.catPlayerRepo.GetPlayerByName(ctx, key, lm.Network, lm.Channel)
-		if err != nil || p == nil {
-			return 0, 0, fmt.Errorf("failed to load player %s", key)
-		}
-	}
 
 	// gate: bonded only
 	if ClampLove(p.LoveMeter) != 100 {
 		return 0, 0, nil
 	}
 
-	// once per NY day
-	if p.LastBondPointsAt != nil && sameDayNY(*p.LastBondPointsAt, now) {
+	// once per game day
+	if p.LastBondPointsAt != nil && lm.cal.SameDay(*p.LastBondPointsAt, now) {
 		return 0, p.BondPointStreak, nil
 	}
 
 	// streak rule:
 	// if last award was yesterday -> streak++, else reset to 1
 	newStreak = 1
-	if p.LastBondPointsAt != nil {
-		yesterday := now.AddDate(0, 0, -1)
-		if sameDayNY(*p.LastBondPointsAt, yesterday) {
-			newStreak = p.BondPointStreak + 1
-		}
+	if p.LastBondPointsAt != nil && lm.cal.DayBefore(*p.LastBondPointsAt, now) {
+		newStreak = p.BondPointStreak + 1
 	}
 
 	awardedBondPoints = bondPointsForStreak(newStreak)
@@ -292,78 +308,106 @@ func (lm *LoveMeterImpl) RecordInteraction(ctx context.Context, player string) (
 // --------------------------------------------------
 
 func (lm *LoveMeterImpl) DailyDecayAll(ctx context.Context) error {
-	now := time.Now()
-
-	players, err := lm.catPlayerRepo.ListPlayersAtOrAbove(ctx, lm.Network, lm.Channel, 100)
-	if err != nil {
-		return err
-	}
-
-	for _, p := range players {
-		if p.LastDecayAt != nil && sameDay(*p.LastDecayAt, now) {
-			continue
-		}
-		if p.LastInteractedAt != nil && sameDay(*p.LastInteractedAt, now) {
-			continue
-		}
-
-		// decay 100 -> 95
-		lm.Decrease(p.Name, 5)
-		if err := lm.catPlayerRepo.SetDecayAt(ctx, p.Name, p.Network, p.Channel, now); err != nil {
-			log.Printf("failed to set decay at for %s: %v", p.Name, err)
-		}
+	_, err := lm.decayDay(ctx, lm.cal.Now(), false)
+	return err
+}
 
-		// reset bond streak on decay
-		if err := lm.catPlayerRepo.SetBondPointStreak(ctx, p.Name, p.Network, p.Channel, 0); err != nil {
-			log.Printf("failed to reset bond streak for %s: %v", p.Name, err)
-		}
-	}
+func (lm *LoveMeterImpl) DailyDecayWithWarning(ctx context.Context) ([]string, error) {
+	return lm.decayDay(ctx, lm.cal.Now(), true)
+}
 
-	return nil
+// DecayDay decays the bonded players who skipped the game day day falls on
+// and returns the warnings to announce. A player is left alone if they
+// visited on that day or later, or were already decayed on or after it, so
+// the scheduler can run a missed day again after downtime.
+func (lm *LoveMeterImpl) DecayDay(ctx context.Context, day time.Time) ([]string, error) {
+	return lm.decayDay(ctx, day, true)
 }
 
-func (lm *LoveMeterImpl) DailyDecayWithWarning(ctx context.Context) ([]string, error) {
-	now := time.Now()
+func (lm *LoveMeterImpl) decayDay(ctx context.Context, day time.Time, warn bool) ([]string, error) {
+	decay := int(lm.dailyDecay.Load())
+	if decay == 0 {
+		return nil, nil
+	}
+	now := lm.cal.Now()
+	start := lm.cal.StartOfDay(day)
 
 	players, err := lm.catPlayerRepo.ListPlayersAtOrAbove(ctx, lm.Network, lm.Channel, 100)
 	if err != nil {
 		return nil, err
 	}
 
+	when := "today"
+	if !lm.cal.SameDay(day, now) {
+		when = "yesterday"
+	}
+
 	var announcements []string
 
 	for _, p := range players {
-		// prevent double decay in a day
-		if p.LastDecayAt != nil && sameDay(*p.LastDecayAt, now) {
+		// prevent double decay for the same day
+		if p.LastDecayAt != nil && !p.LastDecayAt.Before(start) {
 			continue
 		}
-		// if today has interacted, don't decay
-		if p.LastInteractedAt != nil && sameDay(*p.LastInteractedAt, now) {
+		// came that day (or since), don't decay
+		if p.LastInteractedAt != nil && !p.LastInteractedAt.Before(start) {
 			continue
 		}
 
-		oldLove := p.LoveMeter
-
-		lm.Decrease(p.Name, 5)
-		if err := lm.catPlayerRepo.SetDecayAt(ctx, p.Name, p.Network, p.Channel, now); err != nil {
-			log.Printf("failed to set decay at for %s: %v", p.Name, err)
+		// decay 100 -> 95 by default; on failure last_decay_at stays unset
+		// and the next run tries again
+		oldLove, newLove, err := lm.Add(ctx, p.Name, -decay)
+		if err != nil {
+			lm.log.Error("failed to decay love", logging.KeyNick, p.Name, "error", err)
+			continue
+		}
+		// stamp the game day that decayed, not now: catching up several
+		// missed days decays each of them in turn
+		if err := lm.catPlayerRepo.SetDecayAt(ctx, p.Name, p.Network, p.Channel, start); err != nil {
+			lm.log.Error("failed to set decay at", logging.KeyNick, p.Name, "error", err)
 		}
+		lm.logDecay(ctx, p.Name, oldLove, newLove, now)
 
 		// reset bond streak on decay
 		if err := lm.catPlayerRepo.SetBondPointStreak(ctx, p.Name, p.Network, p.Channel, 0); err != nil {
-			log.Printf("failed to reset bond streak for %s: %v", p.Name, err)
+			lm.log.Error("failed to reset bond streak", logging.KeyNick, p.Name, "error", err)
+		}
+
+		// decay breaks the daily bonding streak too (HighestStreak is kept)
+		if err := lm.streaks.Break(ctx, p.Name, p.Network, p.Channel); err != nil {
+			lm.log.Error("failed to reset daily streak", logging.KeyNick, p.Name, "error", err)
 		}
 
 		// warning only once: 100 -> 95
-		if oldLove == 100 && !p.PerfectDropWarned {
+		if warn && oldLove == 100 && !p.PerfectDropWarned {
 			announcements = append(announcements,
-				fmt.Sprintf("😿 Purrito is waiting but %s did not come today, the perfect bond has begun to fade (100%% → 95%%) 🐾", p.Name),
+				fmt.Sprintf("😿 Purrito is waiting but %s did not come %s, the perfect bond has begun to fade (100%% → %d%%) 🐾", p.Name, when, newLove),
 			)
 			if err := lm.catPlayerRepo.SetPerfectDropWarned(ctx, p.Name, p.Network, p.Channel, true); err != nil {
-				log.Printf("failed to set perfect drop warned for %s: %v", p.Name, err)
+				lm.log.Error("failed to set perfect drop warned", logging.KeyNick, p.Name, "error", err)
 			}
 		}
 	}
 
 	return announcements, nil
 }
+
+// logDecay appends a decay to the event log, if there is one.
+func (lm *LoveMeterImpl) logDecay(ctx context.Context, player string, before, after int, at time.Time) {
+	if lm.eventLog == nil {
+		return
+	}
+	err := lm.eventLog.Append(ctx, &cat_event.CatEvent{
+		CreatedAt:  at,
+		Network:    lm.Network,
+		Channel:    lm.Channel,
+		Player:     player,
+		Action:     cat_event.ActionDecay,
+		Outcome:    cat_event.OutcomeDecayed,
+		LoveBefore: before,
+		LoveAfter:  after,
+	})
+	if err != nil {
+		lm.log.Error("failed to log decay", logging.KeyNick, player, "error", err)
+	}
+}
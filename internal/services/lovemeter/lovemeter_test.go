@@ -7,155 +7,15 @@ import (
 	"testing"
 	"time"
 
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_event"
 	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+	"github.com/MyelinBots/catbot-go/internal/services/streak"
 )
 
-// mockCatPlayerRepo is a simple in-memory mock for testing
-type mockCatPlayerRepo struct {
-	mu      sync.RWMutex
-	players map[string]*cat_player.CatPlayer
-}
-
-func newMockRepo() *mockCatPlayerRepo {
-	return &mockCatPlayerRepo{
-		players: make(map[string]*cat_player.CatPlayer),
-	}
-}
-
-func (m *mockCatPlayerRepo) key(name, network, channel string) string {
-	return strings.ToLower(name) + "|" + strings.ToLower(network) + "|" + strings.ToLower(channel)
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByID(ctx context.Context, id string) (*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetPlayerByName(ctx context.Context, name, network, channel string) (*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	p := m.players[m.key(name, network, channel)]
-	if p != nil {
-		cp := *p
-		return &cp, nil
-	}
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) GetAllPlayers(ctx context.Context, network, channel string) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) UpsertPlayer(ctx context.Context, player *cat_player.CatPlayer) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(player.Name, player.Network, player.Channel)
-	m.players[k] = player
-	return nil
-}
-
-func (m *mockCatPlayerRepo) TopLoveMeter(ctx context.Context, network, channel string, limit int) ([]*cat_player.CatPlayer, error) {
-	return nil, nil
-}
-
-func (m *mockCatPlayerRepo) TouchInteraction(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastInteractedAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetDecayAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastDecayAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) ListPlayersAtOrAbove(ctx context.Context, network, channel string, minLove int) ([]*cat_player.CatPlayer, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	var result []*cat_player.CatPlayer
-	for _, p := range m.players {
-		if strings.EqualFold(p.Network, network) && strings.EqualFold(p.Channel, channel) && p.LoveMeter >= minLove {
-			cp := *p
-			result = append(result, &cp)
-		}
-	}
-	return result, nil
-}
-
-func (m *mockCatPlayerRepo) SetPerfectDropWarned(ctx context.Context, name, network, channel string, warned bool) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.PerfectDropWarned = warned
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddBondPoints(ctx context.Context, name, network, channel string, delta int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPoints += delta
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointsAt(ctx context.Context, name, network, channel string, t time.Time) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LastBondPointsAt = &t
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetBondPointStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.BondPointStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetHighestBondStreak(ctx context.Context, name, network, channel string, streak int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(name, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.HighestBondStreak = streak
-	}
-	return nil
-}
-
-func (m *mockCatPlayerRepo) AddGiftsUnlocked(ctx context.Context, name, network, channel string, giftMask int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetGiftsUnlocked(ctx context.Context, name, network, channel string, giftsUnlocked int) error {
-	return nil
-}
-
-func (m *mockCatPlayerRepo) SetLoveMeter(ctx context.Context, nick, network, channel string, love int) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	k := m.key(nick, network, channel)
-	if p, ok := m.players[k]; ok {
-		p.LoveMeter = love
-	}
-	return nil
+func newPlayerRepo() cat_player.CatPlayerRepository {
+	return cat_player.NewMemoryPlayerRepository()
 }
 
 // Tests
@@ -222,7 +82,7 @@ func TestRenderLoveBar(t *testing.T) {
 }
 
 func TestNewLoveMeter(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	if lm == nil {
 		t.Fatal("NewLoveMeter returned nil")
@@ -230,7 +90,7 @@ func TestNewLoveMeter(t *testing.T) {
 }
 
 func TestLoveMeter_Get_NewPlayer(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	// New player should have 0 love
@@ -241,7 +101,7 @@ func TestLoveMeter_Get_NewPlayer(t *testing.T) {
 }
 
 func TestLoveMeter_Increase(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	// Setup player with 0 love
@@ -252,7 +112,9 @@ func TestLoveMeter_Increase(t *testing.T) {
 		LoveMeter: 0,
 	})
 
-	lm.Increase("player1", 10)
+	if got := lm.Increase("player1", 10); got != 10 {
+		t.Errorf("Increase returned %d, want 10", got)
+	}
 
 	love := lm.Get("player1")
 	if love != 10 {
@@ -261,7 +123,7 @@ func TestLoveMeter_Increase(t *testing.T) {
 }
 
 func TestLoveMeter_Decrease(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	// Setup player with 50 love
@@ -272,7 +134,9 @@ func TestLoveMeter_Decrease(t *testing.T) {
 		LoveMeter: 50,
 	})
 
-	lm.Decrease("player1", 10)
+	if got := lm.Decrease("player1", 10); got != 40 {
+		t.Errorf("Decrease returned %d, want 40", got)
+	}
 
 	love := lm.Get("player1")
 	if love != 40 {
@@ -281,7 +145,7 @@ func TestLoveMeter_Decrease(t *testing.T) {
 }
 
 func TestLoveMeter_IncreaseCapped(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
@@ -300,7 +164,7 @@ func TestLoveMeter_IncreaseCapped(t *testing.T) {
 }
 
 func TestLoveMeter_DecreaseCapped(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
@@ -319,7 +183,7 @@ func TestLoveMeter_DecreaseCapped(t *testing.T) {
 }
 
 func TestLoveMeter_GetMood(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	tests := []struct {
@@ -349,7 +213,7 @@ func TestLoveMeter_GetMood(t *testing.T) {
 }
 
 func TestLoveMeter_GetLoveBar(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
@@ -366,7 +230,7 @@ func TestLoveMeter_GetLoveBar(t *testing.T) {
 }
 
 func TestLoveMeter_StatusLine(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
@@ -406,7 +270,7 @@ func TestBondPointsForStreak(t *testing.T) {
 }
 
 func TestRecordInteraction_NotBonded(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -428,7 +292,7 @@ func TestRecordInteraction_NotBonded(t *testing.T) {
 }
 
 func TestRecordInteraction_Bonded(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -453,7 +317,7 @@ func TestRecordInteraction_Bonded(t *testing.T) {
 }
 
 func TestDailyDecayAll(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -478,8 +342,78 @@ func TestDailyDecayAll(t *testing.T) {
 	}
 }
 
+func TestDailyDecayAll_LogsEvent(t *testing.T) {
+	repo := newPlayerRepo()
+	events := cat_event.NewMemoryCatEventRepository()
+	lm := NewLoveMeter(repo, "testnet", "#testchan", WithEventLog(events))
+	ctx := context.Background()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:             "player1",
+		Network:          "testnet",
+		Channel:          "#testchan",
+		LoveMeter:        100,
+		LastInteractedAt: &yesterday,
+	})
+
+	if err := lm.DailyDecayAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// already decayed today: no second event
+	if err := lm.DailyDecayAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := events.History(ctx, "testnet", "#testchan", cat_event.Query{Player: "player1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("want 1 decay event, got %+v", got)
+	}
+	e := got[0]
+	if e.Action != cat_event.ActionDecay || e.Outcome != cat_event.OutcomeDecayed || e.LoveBefore != 100 || e.LoveAfter != 95 {
+		t.Errorf("unexpected event %+v", e)
+	}
+}
+
+// recordingStreaks records who decay broke the streak of
+type recordingStreaks struct {
+	streak.Service
+	broken []string
+}
+
+func (b *recordingStreaks) Break(_ context.Context, nick, _, _ string) error {
+	b.broken = append(b.broken, nick)
+	return nil
+}
+
+func TestDailyDecayAll_BreaksStreak(t *testing.T) {
+	repo := newPlayerRepo()
+	streaks := &recordingStreaks{}
+	lm := NewLoveMeter(repo, "testnet", "#testchan", WithStreaks(streaks))
+	ctx := context.Background()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:             "player1",
+		Network:          "testnet",
+		Channel:          "#testchan",
+		LoveMeter:        100,
+		LastInteractedAt: &yesterday,
+	})
+
+	if err := lm.DailyDecayAll(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(streaks.broken) != 1 || streaks.broken[0] != "player1" {
+		t.Errorf("decay should break the streak through the streak service, got %v", streaks.broken)
+	}
+}
+
 func TestDailyDecayAll_InteractedToday(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -523,7 +457,7 @@ func TestNorm(t *testing.T) {
 }
 
 func TestDailyDecayWithWarning(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -555,7 +489,7 @@ func TestDailyDecayWithWarning(t *testing.T) {
 }
 
 func TestDailyDecayWithWarning_AlreadyWarned(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -581,7 +515,7 @@ func TestDailyDecayWithWarning_AlreadyWarned(t *testing.T) {
 }
 
 func TestDailyDecayAll_AlreadyDecayedToday(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -607,7 +541,7 @@ func TestDailyDecayAll_AlreadyDecayedToday(t *testing.T) {
 }
 
 func TestRecordInteraction_SecondCallSameDay(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -640,47 +574,8 @@ func TestRecordInteraction_SecondCallSameDay(t *testing.T) {
 	}
 }
 
-func TestSameDayNY(t *testing.T) {
-	loc, _ := time.LoadLocation("America/New_York")
-
-	// Same day
-	t1 := time.Date(2024, 1, 15, 10, 0, 0, 0, loc)
-	t2 := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
-	if !sameDayNY(t1, t2) {
-		t.Error("should be same day")
-	}
-
-	// Different days
-	t3 := time.Date(2024, 1, 15, 23, 0, 0, 0, loc)
-	t4 := time.Date(2024, 1, 16, 1, 0, 0, 0, loc)
-	if sameDayNY(t3, t4) {
-		t.Error("should be different days")
-	}
-}
-
-func TestSameDay(t *testing.T) {
-	t1 := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
-	t2 := time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)
-	if !sameDay(t1, t2) {
-		t.Error("should be same day")
-	}
-
-	t3 := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
-	t4 := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
-	if sameDay(t3, t4) {
-		t.Error("should be different days")
-	}
-}
-
-func TestNyNow(t *testing.T) {
-	now := nyNow()
-	if now.IsZero() {
-		t.Error("nyNow should not return zero time")
-	}
-}
-
 func TestLoveMeter_MultipleIncrease(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	repo.UpsertPlayer(context.Background(), &cat_player.CatPlayer{
@@ -701,7 +596,7 @@ func TestLoveMeter_MultipleIncrease(t *testing.T) {
 }
 
 func TestLoveMeter_NewPlayerIncrease(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 
 	// Don't pre-create player
@@ -715,7 +610,7 @@ func TestLoveMeter_NewPlayerIncrease(t *testing.T) {
 }
 
 func TestRecordInteraction_NewPlayer(t *testing.T) {
-	repo := newMockRepo()
+	repo := newPlayerRepo()
 	lm := NewLoveMeter(repo, "testnet", "#testchan")
 	ctx := context.Background()
 
@@ -730,3 +625,169 @@ func TestRecordInteraction_NewPlayer(t *testing.T) {
 		t.Errorf("new player should return 0,0 - got pts=%d, streak=%d", pts, streak)
 	}
 }
+
+func TestSetDailyDecay(t *testing.T) {
+	repo := newPlayerRepo()
+	lm := NewLoveMeter(repo, "testnet", "#testchan").(*LoveMeterImpl)
+	ctx := context.Background()
+
+	yesterday := time.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:             "player1",
+		Network:          "testnet",
+		Channel:          "#testchan",
+		LoveMeter:        100,
+		LastInteractedAt: &yesterday,
+	})
+
+	lm.SetDailyDecay(0)
+	if msgs, _ := lm.DailyDecayWithWarning(ctx); len(msgs) != 0 || lm.Get("player1") != 100 {
+		t.Fatalf("decay 0 should turn decay off, love=%d msgs=%q", lm.Get("player1"), msgs)
+	}
+
+	lm.SetDailyDecay(10)
+	msgs, err := lm.DailyDecayWithWarning(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if love := lm.Get("player1"); love != 90 {
+		t.Errorf("expected love to decay to 90, got %d", love)
+	}
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "(100% → 90%)") {
+		t.Errorf("warning should show the real drop, got %q", msgs)
+	}
+}
+
+func TestDailyDecay_UsesGameDay(t *testing.T) {
+	repo := newPlayerRepo()
+	ctx := context.Background()
+
+	// 23:30 in Bangkok is 16:30 UTC: "today" depends on the game's timezone
+	now := time.Date(2024, 1, 15, 16, 30, 0, 0, time.UTC)
+	earlier := time.Date(2024, 1, 15, 1, 0, 0, 0, time.UTC) // Jan 15 08:00 in Bangkok
+	cal := calendar.New("Asia/Bangkok", calendar.WithClock(clock.NewFake(now)))
+	lm := NewLoveMeter(repo, "testnet", "#testchan", WithCalendar(cal))
+
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:             "player1",
+		Network:          "testnet",
+		Channel:          "#testchan",
+		LoveMeter:        100,
+		LastInteractedAt: &earlier,
+	})
+	if err := lm.DailyDecayAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if love := lm.Get("player1"); love != 100 {
+		t.Errorf("played earlier the same game day, love should stay 100, got %d", love)
+	}
+
+	// in Los Angeles the visit was Jan 14 17:00 and it is now Jan 15 08:30
+	cal.SetTimezone("America/Los_Angeles")
+	if err := lm.DailyDecayAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if love := lm.Get("player1"); love != 95 {
+		t.Errorf("no visit this game day, love should decay to 95, got %d", love)
+	}
+}
+
+func TestDecayDay_CatchesUpMissedDay(t *testing.T) {
+	repo := newPlayerRepo()
+	ctx := context.Background()
+
+	// the bot was down over midnight and comes back Jan 16 10:00 in New York
+	now := time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC)
+	cal := calendar.New("America/New_York", calendar.WithClock(clock.NewFake(now)))
+	lm := NewLoveMeter(repo, "testnet", "#testchan", WithCalendar(cal)).(*LoveMeterImpl)
+	yesterday := cal.StartOfDay(now).Add(-time.Nanosecond)
+
+	twoDaysAgo := now.AddDate(0, 0, -2)
+	lastNight := time.Date(2024, 1, 16, 2, 0, 0, 0, time.UTC) // Jan 15 21:00 in New York
+	for name, visit := range map[string]time.Time{"skipped": twoDaysAgo, "came": lastNight} {
+		repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+			Name: name, Network: "testnet", Channel: "#testchan",
+			LoveMeter: 100, LastInteractedAt: &visit,
+		})
+	}
+
+	msgs, err := lm.DecayDay(ctx, yesterday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lm.Get("skipped") != 95 || lm.Get("came") != 100 {
+		t.Errorf("only the player who skipped Jan 15 should decay, got skipped=%d came=%d", lm.Get("skipped"), lm.Get("came"))
+	}
+	if len(msgs) != 1 || !strings.Contains(msgs[0], "did not come yesterday") {
+		t.Errorf("expected one warning about yesterday, got %q", msgs)
+	}
+
+	// a second replica (or a restart) running the same day again is a no-op
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name: "skipped", Network: "testnet", Channel: "#testchan",
+		LoveMeter: 100, LastInteractedAt: &twoDaysAgo, LastDecayAt: &now,
+	})
+	if _, err := lm.DecayDay(ctx, yesterday); err != nil {
+		t.Fatal(err)
+	}
+	if love := lm.Get("skipped"); love != 100 {
+		t.Errorf("already decayed for Jan 15, love should stay 100, got %d", love)
+	}
+}
+
+func TestDecayDay_StampsTheDecayedDay(t *testing.T) {
+	repo := newPlayerRepo()
+	ctx := context.Background()
+
+	// the bot comes back Jan 18 after missing the decays for Jan 15, 16 and 17
+	now := time.Date(2024, 1, 18, 10, 0, 0, 0, time.UTC)
+	cal := calendar.New("UTC", calendar.WithClock(clock.NewFake(now)))
+	lm := NewLoveMeter(repo, "testnet", "#testchan", WithCalendar(cal)).(*LoveMeterImpl)
+	lastVisit := time.Date(2024, 1, 14, 12, 0, 0, 0, time.UTC)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name: "player1", Network: "testnet", Channel: "#testchan",
+		LoveMeter: 100, LastInteractedAt: &lastVisit,
+	})
+
+	jan15 := time.Date(2024, 1, 15, 23, 59, 0, 0, time.UTC)
+	if _, err := lm.DecayDay(ctx, jan15); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := repo.GetPlayerByName(ctx, "player1", "testnet", "#testchan")
+	if p.LoveMeter != 95 || p.LastDecayAt == nil || !p.LastDecayAt.Equal(cal.StartOfDay(jan15)) {
+		t.Fatalf("expected love 95 decayed for Jan 15, got %d at %v", p.LoveMeter, p.LastDecayAt)
+	}
+
+	// back at 100 (e.g. !setlove): the next missed day still decays
+	repo.SetLoveMeter(ctx, "player1", "testnet", "#testchan", 100)
+	if _, err := lm.DecayDay(ctx, jan15.AddDate(0, 0, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if love := lm.Get("player1"); love != 95 {
+		t.Errorf("Jan 16 should decay after Jan 15, got %d", love)
+	}
+}
+
+func TestIncreaseDecrease_ConcurrentUpdatesAreNotLost(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	lm := NewLoveMeter(repo, "testnet", "#testchan")
+	lm.Increase("player1", 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i%2 == 0 {
+				lm.Increase("Player1", 1)
+			} else {
+				lm.Decrease("player1", 1)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := lm.Get("player1"); got != 50 {
+		t.Errorf("expected love 50 after 20 increases and 20 decreases, got %d", got)
+	}
+}
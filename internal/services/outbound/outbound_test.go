@@ -0,0 +1,308 @@
+package outbound
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// recordingConn records every line written to the connection
+type recordingConn struct {
+	mu    sync.Mutex
+	lines []string // "PRIVMSG target :text" / "NOTICE target :text"
+}
+
+func (c *recordingConn) Privmsg(target, msg string) { c.add("PRIVMSG", target, msg) }
+func (c *recordingConn) Notice(target, msg string)  { c.add("NOTICE", target, msg) }
+
+func (c *recordingConn) add(cmd, target, msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lines = append(c.lines, cmd+" "+target+" :"+msg)
+}
+
+func (c *recordingConn) Lines() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.lines...)
+}
+
+// fakeClock is advanced by hand
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) Now() time.Time      { return f.t }
+func (f *fakeClock) Add(d time.Duration) { f.t = f.t.Add(d) }
+func newFakeClock() *fakeClock           { return &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)} }
+func newTestQueue(cfg Config) (*Queue, *recordingConn, *fakeClock) {
+	conn, clock := &recordingConn{}, newFakeClock()
+	q := New(conn, cfg)
+	q.now = clock.Now
+	return q, conn, clock
+}
+
+// --------------------------------------------------
+// Split
+// --------------------------------------------------
+
+func TestSplit_ShortLineUntouched(t *testing.T) {
+	got := Split("\x0303hello\x0F world", 400)
+	if len(got) != 1 || got[0] != "\x0303hello\x0F world" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSplit_Newlines(t *testing.T) {
+	got := Split("one\ntwo\r\nthree", 400)
+	if strings.Join(got, "|") != "one|two|three" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSplit_ByteLimitAndUTF8(t *testing.T) {
+	msg := strings.Repeat("ความรัก😺", 60) // multi-byte runes, no spaces
+	for _, max := range []int{16, 50, 101, 400} {
+		lines := Split(msg, max)
+		var joined strings.Builder
+		for _, l := range lines {
+			if len(l) > max {
+				t.Errorf("max %d: line of %d bytes", max, len(l))
+			}
+			if !utf8.ValidString(l) {
+				t.Errorf("max %d: invalid UTF-8 %q", max, l)
+			}
+			joined.WriteString(l)
+		}
+		if joined.String() != msg {
+			t.Errorf("max %d: text lost while splitting", max)
+		}
+	}
+}
+
+func TestSplit_PrefersSpaces(t *testing.T) {
+	msg := strings.Repeat("purr ", 30)
+	for _, l := range Split(msg, 42) {
+		if strings.HasPrefix(l, "urr") || strings.HasSuffix(l, "pu") {
+			t.Errorf("word cut in half: %q", l)
+		}
+	}
+}
+
+func TestSplit_KeepsColourCodesWhole(t *testing.T) {
+	// colour codes right at the boundary must not be cut between \x03 and its digits
+	msg := strings.Repeat("ab\x0304,12cd", 40)
+	for _, max := range []int{17, 18, 19, 20, 21} {
+		for _, l := range Split(msg, max) {
+			if strings.HasSuffix(l, "\x03") || strings.HasSuffix(l, "\x030") || strings.HasSuffix(l, "\x0304,") {
+				t.Errorf("max %d: colour code cut: %q", max, l)
+			}
+		}
+	}
+}
+
+func TestSplit_ReopensFormatting(t *testing.T) {
+	msg := "\x02\x0304" + strings.Repeat("x", 50) + "\x0F" + strings.Repeat("y", 50)
+	lines := Split(msg, 20)
+	if len(lines) < 3 {
+		t.Fatalf("expected several lines, got %q", lines)
+	}
+	if !strings.HasPrefix(lines[1], "\x0304\x02") {
+		t.Errorf("continuation should re-open colour and bold, got %q", lines[1])
+	}
+	last := lines[len(lines)-1]
+	if strings.HasPrefix(last, "\x03") || strings.HasPrefix(last, "\x02") {
+		t.Errorf("formatting after reset should not be re-opened, got %q", last)
+	}
+}
+
+// --------------------------------------------------
+// Queue
+// --------------------------------------------------
+
+func TestQueue_TokenBucketPerTarget(t *testing.T) {
+	q, conn, clock := newTestQueue(Config{Rate: 1, Burst: 2})
+
+	for i := 0; i < 4; i++ {
+		q.Privmsg("#a", "hello")
+	}
+	q.Privmsg("#b", "hi")
+
+	wait := q.sendReady()
+	if got := len(conn.Lines()); got != 3 {
+		t.Fatalf("expected burst of 2 to #a + 1 to #b, got %d: %q", got, conn.Lines())
+	}
+	if wait <= 0 || wait > time.Second {
+		t.Errorf("expected to wait up to 1s for #a, got %v", wait)
+	}
+
+	clock.Add(time.Second)
+	q.sendReady()
+	if got := len(conn.Lines()); got != 4 {
+		t.Errorf("one more token after 1s, got %d lines", got)
+	}
+
+	clock.Add(time.Second)
+	if q.sendReady(); len(conn.Lines()) != 5 {
+		t.Errorf("queue should be drained, got %d lines", len(conn.Lines()))
+	}
+	if s := q.Stats(); s.Total != 0 || s.Sent != 5 || s.HighWater != 5 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestQueue_PriorityOrder(t *testing.T) {
+	q, conn, _ := newTestQueue(Config{Rate: 1, Burst: 10})
+
+	q.At(PriorityLow).Notice("alice", "help 1")
+	q.At(PriorityNormal).Privmsg("#cats", "reply")
+	q.At(PriorityHigh).Privmsg("#cats", "Purrito appears")
+
+	q.sendReady()
+	lines := conn.Lines()
+	want := []string{"PRIVMSG #cats :Purrito appears", "PRIVMSG #cats :reply", "NOTICE alice :help 1"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", lines, want)
+	}
+}
+
+func TestQueue_FIFOPerTarget(t *testing.T) {
+	q, conn, clock := newTestQueue(Config{Rate: 1, Burst: 1})
+
+	q.Privmsg("#cats", "first")
+	q.Privmsg("#cats", "second")
+	q.Privmsg("#CATS", "third")
+
+	for i := 0; i < 3; i++ {
+		q.sendReady()
+		clock.Add(time.Second)
+	}
+	want := "PRIVMSG #cats :first|PRIVMSG #cats :second|PRIVMSG #CATS :third"
+	if got := strings.Join(conn.Lines(), "|"); got != want {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestQueue_GlobalLimit(t *testing.T) {
+	q, conn, clock := newTestQueue(Config{Rate: 10, Burst: 10, GlobalRate: 1, GlobalBurst: 2})
+
+	for _, target := range []string{"#a", "#b", "#c", "#d"} {
+		q.Privmsg(target, "hi")
+	}
+
+	q.sendReady()
+	if got := len(conn.Lines()); got != 2 {
+		t.Fatalf("global burst is 2, sent %d", got)
+	}
+	clock.Add(2 * time.Second)
+	q.sendReady()
+	if got := len(conn.Lines()); got != 4 {
+		t.Errorf("expected all sent after refill, got %d", got)
+	}
+}
+
+func TestQueue_DropsLowestPriorityWhenFull(t *testing.T) {
+	q, conn, _ := newTestQueue(Config{Rate: 1, Burst: 1, MaxQueue: 2})
+
+	q.At(PriorityLow).Notice("alice", "help")
+	q.At(PriorityLow).Notice("alice", "more help")
+	q.At(PriorityHigh).Privmsg("#cats", "event")
+	q.At(PriorityLow).Notice("alice", "even more help")
+
+	s := q.Stats()
+	if s.Total != 2 || s.Dropped != 2 || s.Depth[PriorityHigh] != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	q.sendReady()
+	if lines := conn.Lines(); len(lines) != 2 || lines[0] != "PRIVMSG #cats :event" {
+		t.Errorf("got %q", lines)
+	}
+}
+
+func TestQueue_SplitsLongMessages(t *testing.T) {
+	q, conn, _ := newTestQueue(Config{Rate: 1, Burst: 100, MaxLineBytes: 100})
+
+	q.Privmsg("#cats", strings.Repeat("meow ", 100))
+	q.Privmsg("#cats", "")
+
+	q.sendReady()
+	lines := conn.Lines()
+	if len(lines) < 5 {
+		t.Fatalf("expected the message to be split, got %d lines", len(lines))
+	}
+	for _, l := range lines {
+		if len(strings.TrimPrefix(l, "PRIVMSG #cats :")) > 100 {
+			t.Errorf("line too long: %d bytes", len(l))
+		}
+	}
+}
+
+func TestQueue_Run(t *testing.T) {
+	conn := &recordingConn{}
+	q := New(conn, Config{Rate: 100, Burst: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+
+	for i := 0; i < 5; i++ {
+		q.Privmsg("#cats", "hi")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(conn.Lines()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := len(conn.Lines()); got != 5 {
+		t.Errorf("expected 5 lines sent, got %d", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop on cancel")
+	}
+}
+
+func TestQueue_PauseHoldsLines(t *testing.T) {
+	q, conn, _ := newTestQueue(Config{Rate: 1, Burst: 10})
+
+	q.Pause()
+	q.Privmsg("#cats", "while disconnected")
+	if q.sendReady(); len(conn.Lines()) != 0 {
+		t.Fatalf("paused queue sent %q", conn.Lines())
+	}
+
+	q.Resume()
+	if q.sendReady(); len(conn.Lines()) != 1 {
+		t.Errorf("resumed queue should send the held line, got %q", conn.Lines())
+	}
+}
+
+func TestQueue_Drain(t *testing.T) {
+	q, conn, _ := newTestQueue(Config{Rate: 1, Burst: 10})
+	for i := 0; i < 3; i++ {
+		q.Privmsg("#cats", "bye")
+	}
+
+	if err := q.Drain(context.Background()); err != nil {
+		t.Fatalf("Drain: %v", err)
+	}
+	if len(conn.Lines()) != 3 {
+		t.Errorf("expected 3 lines sent, got %d", len(conn.Lines()))
+	}
+
+	q.Pause()
+	q.Privmsg("#cats", "stuck")
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := q.Drain(ctx); err == nil {
+		t.Error("Drain of a paused queue should give up when ctx is done")
+	}
+}
@@ -0,0 +1,387 @@
+package outbound
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// --------------------------------------------------
+// Outbound queue
+// Every PRIVMSG/NOTICE goes through here: split to fit an IRC line, queued by priority,
+// and released per target through a token bucket so we don't get kicked for flooding.
+// --------------------------------------------------
+
+// Conn is the part of the IRC connection the queue writes to (*irc.Conn satisfies it).
+type Conn interface {
+	Privmsg(target, message string)
+	Notice(target, message string)
+}
+
+type Priority int
+
+const (
+	PriorityHigh   Priority = iota // game events: spawns, action replies
+	PriorityNormal                 // command replies
+	PriorityLow                    // help text and other bulk output
+
+	priorities = 3
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+type Config struct {
+	Rate         float64 // messages per second per target
+	Burst        int     // messages a target can receive back to back
+	GlobalRate   float64 // messages per second over the whole connection, 0 = unlimited
+	GlobalBurst  int
+	MaxLineBytes int // payload bytes per line, keep below goirc's SplitLen (450)
+	MaxQueue     int // queued lines before the lowest priority is dropped
+}
+
+func (c Config) withDefaults() Config {
+	if c.Rate <= 0 {
+		c.Rate = 0.5
+	}
+	if c.Burst <= 0 {
+		c.Burst = 4
+	}
+	if c.GlobalRate > 0 && c.GlobalBurst <= 0 {
+		c.GlobalBurst = c.Burst
+	}
+	if c.MaxLineBytes <= 0 {
+		c.MaxLineBytes = 400
+	}
+	if c.MaxQueue <= 0 {
+		c.MaxQueue = 500
+	}
+	return c
+}
+
+// Stats is a snapshot of the queue for metrics.
+type Stats struct {
+	Depth     [priorities]int // queued lines per priority
+	Total     int             // queued lines overall
+	HighWater int             // largest Total seen
+	Sent      uint64
+	Dropped   uint64
+}
+
+type message struct {
+	notice bool
+	target string
+	text   string
+}
+
+type Queue struct {
+	conn Conn
+	cfg  Config
+	now  func() time.Time
+
+	mu      sync.Mutex
+	pending [priorities][]message
+	buckets map[string]*bucket // key: lowercased target
+	global  *bucket
+	stats   Stats
+	paused  bool
+
+	wake chan struct{}
+}
+
+func New(conn Conn, cfg Config) *Queue {
+	cfg = cfg.withDefaults()
+	q := &Queue{
+		conn:    conn,
+		cfg:     cfg,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+		wake:    make(chan struct{}, 1),
+	}
+	if cfg.GlobalRate > 0 {
+		q.global = newBucket(cfg.GlobalRate, cfg.GlobalBurst, q.now())
+	}
+	return q
+}
+
+// At returns a client that enqueues at priority p. It implements catbot.IRCClient.
+func (q *Queue) At(p Priority) *Client {
+	return &Client{queue: q, priority: p}
+}
+
+// Privmsg enqueues at PriorityNormal.
+func (q *Queue) Privmsg(target, msg string) { q.Enqueue(PriorityNormal, false, target, msg) }
+
+// Notice enqueues at PriorityNormal.
+func (q *Queue) Notice(target, msg string) { q.Enqueue(PriorityNormal, true, target, msg) }
+
+// Enqueue splits msg into IRC-sized lines and queues them for target.
+func (q *Queue) Enqueue(p Priority, notice bool, target, msg string) {
+	if p < 0 || p >= priorities {
+		p = PriorityLow
+	}
+
+	lines := Split(msg, q.cfg.MaxLineBytes)
+
+	q.mu.Lock()
+	for _, l := range lines {
+		if l == "" {
+			continue // empty PRIVMSG/NOTICE is rejected by servers
+		}
+		if q.stats.Total >= q.cfg.MaxQueue && !q.dropLowerThan(p) {
+			q.stats.Dropped++
+			continue
+		}
+		q.pending[p] = append(q.pending[p], message{notice: notice, target: target, text: l})
+		q.stats.Depth[p]++
+		q.stats.Total++
+		if q.stats.Total > q.stats.HighWater {
+			q.stats.HighWater = q.stats.Total
+		}
+	}
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+// dropLowerThan drops the newest line of the lowest priority below p to make room. Caller holds mu.
+func (q *Queue) dropLowerThan(p Priority) bool {
+	for low := Priority(priorities - 1); low > p; low-- {
+		if n := len(q.pending[low]); n > 0 {
+			q.pending[low] = q.pending[low][:n-1]
+			q.stats.Depth[low]--
+			q.stats.Total--
+			q.stats.Dropped++
+			return true
+		}
+	}
+	return false
+}
+
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Pause holds every queued line until Resume, e.g. while disconnected.
+// Lines can still be enqueued.
+func (q *Queue) Pause() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.paused = true
+}
+
+func (q *Queue) Resume() {
+	q.mu.Lock()
+	q.paused = false
+	q.mu.Unlock()
+
+	q.signal()
+}
+
+func (q *Queue) Stats() Stats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.stats
+}
+
+// Run sends queued lines until ctx is done.
+func (q *Queue) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+
+	for {
+		wait := q.sendReady()
+
+		if !timer.Stop() {
+			select {
+			case <-timer.C:
+			default:
+			}
+		}
+		if wait > 0 {
+			timer.Reset(wait)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-timer.C:
+		}
+	}
+}
+
+// Drain sends everything still queued, within the rate limits, and returns
+// once the queue is empty or ctx is done. Used on shutdown before QUIT.
+func (q *Queue) Drain(ctx context.Context) error {
+	for {
+		wait := q.sendReady()
+		if q.Stats().Total == 0 {
+			return nil
+		}
+		if wait <= 0 {
+			wait = 50 * time.Millisecond // paused
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// sendReady sends every line whose buckets allow it and returns how long to wait
+// for the next one (0 when the queue is empty).
+func (q *Queue) sendReady() time.Duration {
+	for {
+		msg, wait, ok := q.next()
+		if !ok {
+			return wait
+		}
+		if msg.notice {
+			q.conn.Notice(msg.target, msg.text)
+		} else {
+			q.conn.Privmsg(msg.target, msg.text)
+		}
+	}
+}
+
+// next pops the first sendable line, highest priority first, FIFO per target.
+func (q *Queue) next() (message, time.Duration, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.paused {
+		return message{}, 0, false
+	}
+
+	now := q.now()
+	if q.stats.Total == 0 {
+		q.pruneIdle(now)
+		return message{}, 0, false
+	}
+
+	if q.global != nil {
+		if w := q.global.wait(now); w > 0 {
+			return message{}, w, false
+		}
+	}
+
+	var wait time.Duration
+
+	blocked := make(map[string]bool)
+	for p := range q.pending {
+		for i, m := range q.pending[p] {
+			key := strings.ToLower(m.target)
+			if blocked[key] {
+				continue
+			}
+
+			b := q.bucketFor(key, now)
+			if w := b.wait(now); w > 0 {
+				blocked[key] = true
+				if wait == 0 || w < wait {
+					wait = w
+				}
+				continue
+			}
+
+			b.take(now)
+			if q.global != nil {
+				q.global.take(now)
+			}
+			q.pending[p] = append(q.pending[p][:i], q.pending[p][i+1:]...)
+			q.stats.Depth[p]--
+			q.stats.Total--
+			q.stats.Sent++
+			return m, 0, true
+		}
+	}
+	return message{}, wait, false
+}
+
+func (q *Queue) bucketFor(key string, now time.Time) *bucket {
+	b, ok := q.buckets[key]
+	if !ok {
+		b = newBucket(q.cfg.Rate, q.cfg.Burst, now)
+		q.buckets[key] = b
+	}
+	return b
+}
+
+// pruneIdle forgets targets whose bucket has fully refilled. Caller holds mu.
+func (q *Queue) pruneIdle(now time.Time) {
+	for key, b := range q.buckets {
+		if b.refill(now); b.tokens >= b.burst {
+			delete(q.buckets, key)
+		}
+	}
+}
+
+// --------------------------------------------------
+// Client: a priority-bound view of the queue
+// --------------------------------------------------
+
+type Client struct {
+	queue    *Queue
+	priority Priority
+}
+
+func (c *Client) Privmsg(target, msg string) { c.queue.Enqueue(c.priority, false, target, msg) }
+
+func (c *Client) Notice(target, msg string) { c.queue.Enqueue(c.priority, true, target, msg) }
+
+// --------------------------------------------------
+// Token bucket
+// --------------------------------------------------
+
+type bucket struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(rate float64, burst int, now time.Time) *bucket {
+	return &bucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+}
+
+// wait returns how long until a token is available (0 = now).
+func (b *bucket) wait(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	if d := time.Duration((1 - b.tokens) / b.rate * float64(time.Second)); d > time.Millisecond {
+		return d
+	}
+	return time.Millisecond
+}
+
+func (b *bucket) take(now time.Time) {
+	b.refill(now)
+	b.tokens--
+}
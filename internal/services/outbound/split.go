@@ -0,0 +1,188 @@
+package outbound
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// mIRC formatting control characters
+const (
+	ctrlBold          = '\x02'
+	ctrlColor         = '\x03'
+	ctrlHexColor      = '\x04'
+	ctrlReset         = '\x0F'
+	ctrlMonospace     = '\x11'
+	ctrlReverse       = '\x16'
+	ctrlItalic        = '\x1D'
+	ctrlStrikethrough = '\x1E'
+	ctrlUnderline     = '\x1F'
+)
+
+// format is the formatting active at some point of a line.
+type format struct {
+	toggles map[byte]bool
+	color   string // last colour code incl. \x03, "" when none
+}
+
+func (f *format) reset() {
+	f.toggles = nil
+	f.color = ""
+}
+
+// prefix re-opens the active formatting at the start of a continuation line.
+func (f *format) prefix() string {
+	var b strings.Builder
+	b.WriteString(f.color)
+	for _, c := range []byte{ctrlBold, ctrlItalic, ctrlUnderline, ctrlReverse, ctrlStrikethrough, ctrlMonospace} {
+		if f.toggles[c] {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// codeLen returns the length of the formatting code at s[0] (0 when s[0] is plain text).
+func codeLen(s string) int {
+	switch s[0] {
+	case ctrlColor:
+		n := 1
+		n += digits(s[n:], 2)
+		if n > 1 && n < len(s) && s[n] == ',' && digits(s[n+1:], 2) > 0 {
+			n += 1 + digits(s[n+1:], 2)
+		}
+		return n
+	case ctrlHexColor:
+		n := 1
+		n += hexDigits(s[n:], 6)
+		if n > 1 && n < len(s) && s[n] == ',' && hexDigits(s[n+1:], 6) == 6 {
+			n += 7
+		}
+		return n
+	case ctrlBold, ctrlReset, ctrlMonospace, ctrlReverse, ctrlItalic, ctrlStrikethrough, ctrlUnderline:
+		return 1
+	}
+	return 0
+}
+
+func digits(s string, max int) int {
+	n := 0
+	for n < len(s) && n < max && s[n] >= '0' && s[n] <= '9' {
+		n++
+	}
+	return n
+}
+
+func hexDigits(s string, max int) int {
+	n := 0
+	for n < len(s) && n < max && strings.IndexByte("0123456789abcdefABCDEF", s[n]) >= 0 {
+		n++
+	}
+	if n != max {
+		return 0
+	}
+	return n
+}
+
+func (f *format) apply(code string) {
+	switch code[0] {
+	case ctrlReset:
+		f.reset()
+	case ctrlColor, ctrlHexColor:
+		if len(code) == 1 {
+			f.color = "" // bare \x03 ends colour
+		} else {
+			f.color = code
+		}
+	default:
+		if f.toggles == nil {
+			f.toggles = make(map[byte]bool)
+		}
+		f.toggles[code[0]] = !f.toggles[code[0]]
+	}
+}
+
+// Split breaks msg into lines of at most max bytes.
+// It never cuts a UTF-8 character or a colour code in half, prefers to break on spaces,
+// re-opens active mIRC formatting on continuation lines and turns newlines into separate lines.
+func Split(msg string, max int) []string {
+	if max < 16 {
+		max = 16
+	}
+
+	var out []string
+	for _, line := range strings.Split(strings.ReplaceAll(msg, "\r\n", "\n"), "\n") {
+		out = append(out, splitLine(line, max)...)
+	}
+	return out
+}
+
+func splitLine(line string, max int) []string {
+	if len(line) <= max {
+		return []string{line}
+	}
+
+	var (
+		out   []string
+		state format
+	)
+	for len(line) > 0 {
+		head := state.prefix()
+		budget := max - len(head)
+		if len(line) <= budget {
+			out = append(out, head+line)
+			break
+		}
+
+		// walk forward to the last safe cut point within budget
+		cut, lastSpace := 0, -1
+		next := state
+		next.toggles = copyToggles(state.toggles)
+		var atSpace format
+		for cut < len(line) {
+			n := codeLen(line[cut:])
+			if n == 0 {
+				_, n = utf8.DecodeRuneInString(line[cut:])
+			}
+			if cut+n > budget {
+				break
+			}
+			if line[cut] == ' ' {
+				lastSpace = cut
+				atSpace = next
+				atSpace.toggles = copyToggles(next.toggles)
+			}
+			if c := codeLen(line[cut:]); c > 0 {
+				next.apply(line[cut : cut+c])
+			}
+			cut += n
+		}
+		if cut == 0 {
+			// budget smaller than a single code/rune: emit it anyway
+			_, cut = utf8.DecodeRuneInString(line)
+		}
+
+		// break on a space when it doesn't waste more than half the line
+		if lastSpace > budget/2 {
+			out = append(out, head+line[:lastSpace])
+			line = line[lastSpace+1:]
+			state = atSpace
+			continue
+		}
+
+		out = append(out, head+line[:cut])
+		line = line[cut:]
+		state = next
+	}
+	return out
+}
+
+func copyToggles(m map[byte]bool) map[byte]bool {
+	if m == nil {
+		return nil
+	}
+	c := make(map[byte]bool, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,74 @@
+package random
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+/*
+RANDOM
+Every roll of the game (accept chances, respawn delays, which message is
+picked) goes through a Rand. A fixed seed replays the same game; tests can
+script the exact rolls with Sequence.
+*/
+
+type Rand interface {
+	Intn(n int) int
+	Int63n(n int64) int64
+}
+
+// New returns a Rand seeded with seed, or from the clock when seed is 0.
+// Safe for concurrent use.
+func New(seed int64) Rand {
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &locked{r: rand.New(rand.NewSource(seed))}
+}
+
+type locked struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (l *locked) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Intn(n)
+}
+
+func (l *locked) Int63n(n int64) int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.r.Int63n(n)
+}
+
+// Sequence returns its values in order, each reduced into [0, n), and
+// starts over when they run out. Sequence(0) always picks the first option
+// and never passes a percentage roll above 0.
+func Sequence(values ...int64) Rand {
+	if len(values) == 0 {
+		values = []int64{0}
+	}
+	return &sequence{values: values}
+}
+
+type sequence struct {
+	mu     sync.Mutex
+	values []int64
+	next   int
+}
+
+func (s *sequence) Int63n(n int64) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := s.values[s.next%len(s.values)]
+	s.next++
+	if v %= n; v < 0 {
+		v += n
+	}
+	return v
+}
+
+func (s *sequence) Intn(n int) int { return int(s.Int63n(int64(n))) }
@@ -0,0 +1,24 @@
+package random
+
+import "testing"
+
+func TestNew_SeedReplays(t *testing.T) {
+	a, b := New(42), New(42)
+	for i := 0; i < 20; i++ {
+		if x, y := a.Intn(100), b.Intn(100); x != y {
+			t.Fatalf("roll %d: %d != %d with the same seed", i, x, y)
+		}
+	}
+}
+
+func TestSequence(t *testing.T) {
+	r := Sequence(5, 120, -1)
+	for _, want := range []int{5, 20, 99, 5} {
+		if got := r.Intn(100); got != want {
+			t.Errorf("got %d, want %d", got, want)
+		}
+	}
+	if got := Sequence().Int63n(10); got != 0 {
+		t.Errorf("empty sequence should roll 0, got %d", got)
+	}
+}
@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+)
+
+/*
+DAILY SCHEDULER
+Runs a job once a day at a wall-clock time of the game's timezone (e.g.
+00:00), instead of every 24h from whenever the process happened to start.
+With a Locker, replicas sharing a database take a lease first and record
+the due time when it completes, so only one of them runs each due time (and
+a restart doesn't run a completed one again). On start every due time since
+the last completed one is run in order, which catches up the runs missed
+while the bot was down; without a Locker (or a completed run) only the most
+recent due time is run again, so jobs must be safe to repeat for the same
+due time.
+*/
+
+// DefaultLockTTL bounds how long a crashed replica can hold the lease.
+const DefaultLockTTL = 10 * time.Minute
+
+// Locker is a lease shared by every replica, see job_lock.JobLockRepository.
+type Locker interface {
+	TryLock(ctx context.Context, name, owner string, due time.Time, ttl time.Duration) (bool, error)
+	Complete(ctx context.Context, name, owner string, due time.Time) error
+	Unlock(ctx context.Context, name, owner string) error
+	LastDue(ctx context.Context, name string) (*time.Time, error)
+}
+
+// Job does the work for one due time.
+type Job func(ctx context.Context, due time.Time) error
+
+type Daily struct {
+	name         string
+	hour, minute int
+	cal          *calendar.Calendar
+	job          Job
+
+	locker  Locker
+	owner   string
+	lockTTL time.Duration
+
+	log *slog.Logger
+
+	// after is time.After; tests replace it to fire by hand
+	after func(time.Duration) <-chan time.Time
+}
+
+// Option configures a Daily schedule.
+type Option func(*Daily)
+
+// WithLocker runs the job only when owner holds the lease on the job's name.
+func WithLocker(l Locker, owner string) Option {
+	return func(d *Daily) { d.locker, d.owner = l, owner }
+}
+
+// WithLockTTL changes how long the lease is held (default: DefaultLockTTL).
+func WithLockTTL(ttl time.Duration) Option {
+	return func(d *Daily) { d.lockTTL = ttl }
+}
+
+// WithLogger sets the logger (default: slog.Default()).
+func WithLogger(l *slog.Logger) Option {
+	return func(d *Daily) { d.log = l }
+}
+
+// NewDaily schedules job every day at at ("HH:MM") in cal's timezone. name
+// identifies the job for the lock and logs, it must be unique per channel.
+func NewDaily(name, at string, cal *calendar.Calendar, job Job, opts ...Option) (*Daily, error) {
+	hour, minute, err := ParseTimeOfDay(at)
+	if err != nil {
+		return nil, err
+	}
+	d := &Daily{
+		name:    name,
+		hour:    hour,
+		minute:  minute,
+		cal:     cal,
+		job:     job,
+		lockTTL: DefaultLockTTL,
+		after:   time.After,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.log = logging.Or(d.log).With("job", name)
+	return d, nil
+}
+
+// ParseTimeOfDay parses "HH:MM" (24h clock); "" means midnight.
+func ParseTimeOfDay(s string) (hour, minute int, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, 0, nil
+	}
+	h, m, ok := strings.Cut(s, ":")
+	if ok {
+		hour, err = strconv.Atoi(h)
+		if err == nil {
+			minute, err = strconv.Atoi(m)
+		}
+	}
+	if !ok || err != nil || hour < 0 || hour > 23 || minute < 0 || minute > 59 {
+		return 0, 0, fmt.Errorf("time of day %q: want HH:MM, e.g. 00:00 or 06:30", s)
+	}
+	return hour, minute, nil
+}
+
+// Last is the most recent due time at or before now.
+func (d *Daily) Last(now time.Time) time.Time {
+	due := d.on(now, 0)
+	if due.After(now) {
+		due = d.on(now, -1)
+	}
+	return due
+}
+
+// Next is the first due time after now.
+func (d *Daily) Next(now time.Time) time.Time {
+	due := d.on(now, 0)
+	if !due.After(now) {
+		due = d.on(now, 1)
+	}
+	return due
+}
+
+// on is the due time of the game day days away from the one t falls on.
+func (d *Daily) on(t time.Time, days int) time.Time {
+	y, m, day := t.In(d.cal.Location()).Date()
+	return time.Date(y, m, day+days, d.hour, d.minute, 0, 0, d.cal.Location())
+}
+
+// Start catches up the due times missed up to now, then runs the job at
+// every due time until ctx is cancelled.
+func (d *Daily) Start(ctx context.Context) {
+	due := d.Last(d.cal.Now())
+	for _, missed := range d.missed(ctx, due) {
+		if err := d.run(ctx, missed); err != nil {
+			// later due times would complete past this one, retry it next time
+			break
+		}
+	}
+
+	for {
+		now := d.cal.Now()
+		if now.Before(due) {
+			// the timer fired early, don't run the same due time twice
+			now = due
+		}
+		due = d.Next(now)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.after(due.Sub(d.cal.Now())):
+			_ = d.run(ctx, due)
+		}
+	}
+}
+
+// missed is every due time after the last completed one up to last, oldest
+// first. Without a Locker, or a completed due time to start from, it is just
+// last.
+func (d *Daily) missed(ctx context.Context, last time.Time) []time.Time {
+	if d.locker == nil {
+		return []time.Time{last}
+	}
+	done, err := d.locker.LastDue(ctx, d.name)
+	if err != nil {
+		d.log.Error("failed to read the last completed due time", "error", err)
+		return []time.Time{last}
+	}
+	if done == nil {
+		return []time.Time{last}
+	}
+
+	var out []time.Time
+	for due := d.Next(*done); !due.After(last); due = d.Next(due) {
+		out = append(out, due)
+	}
+	return out
+}
+
+// RunDue runs the job for due if the lease can be taken and due hasn't
+// completed yet. It reports whether the job ran.
+func (d *Daily) RunDue(ctx context.Context, due time.Time) (bool, error) {
+	if d.locker == nil {
+		return true, d.job(ctx, due)
+	}
+
+	ok, err := d.locker.TryLock(ctx, d.name, d.owner, due, d.lockTTL)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := d.job(ctx, due); err != nil {
+		// let the next attempt (here or on another replica) retry due
+		if err := d.locker.Unlock(context.Background(), d.name, d.owner); err != nil {
+			d.log.Error("failed to release the job lock", "error", err)
+		}
+		return true, err
+	}
+	if err := d.locker.Complete(context.Background(), d.name, d.owner, due); err != nil {
+		d.log.Error("failed to complete the job lock", "error", err)
+	}
+	return true, nil
+}
+
+// run runs due and logs how it went; the error is already logged.
+func (d *Daily) run(ctx context.Context, due time.Time) error {
+	start := time.Now()
+	ran, err := d.RunDue(ctx, due)
+	switch {
+	case err != nil:
+		d.log.Error("job failed", "due", due, "error", err, logging.Since(start))
+	case !ran:
+		d.log.Info("job is running or done elsewhere, skipped", "due", due)
+	default:
+		d.log.Info("job done", "due", due, logging.Since(start))
+	}
+	return err
+}
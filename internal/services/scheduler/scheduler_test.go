@@ -0,0 +1,189 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/job_lock"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+)
+
+func TestParseTimeOfDay(t *testing.T) {
+	for in, want := range map[string][2]int{"": {0, 0}, "00:00": {0, 0}, "6:30": {6, 30}, " 23:59 ": {23, 59}} {
+		h, m, err := ParseTimeOfDay(in)
+		if err != nil || h != want[0] || m != want[1] {
+			t.Errorf("%q: got %d:%d, %v", in, h, m, err)
+		}
+	}
+	for _, in := range []string{"24:00", "12:60", "noon", "12", "-1:00"} {
+		if _, _, err := ParseTimeOfDay(in); err == nil {
+			t.Errorf("%q should be rejected", in)
+		}
+	}
+}
+
+func TestDaily_LastAndNext(t *testing.T) {
+	cal := calendar.New("America/New_York")
+	d, err := NewDaily("decay", "06:30", cal, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ny := cal.Location()
+
+	now := time.Date(2024, 3, 10, 4, 0, 0, 0, ny)
+	if got, want := d.Last(now), time.Date(2024, 3, 9, 6, 30, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Last = %v, want %v", got, want)
+	}
+	// Mar 10 is the spring-forward day, the wall-clock time is kept
+	if got, want := d.Next(now), time.Date(2024, 3, 10, 6, 30, 0, 0, ny); !got.Equal(want) {
+		t.Errorf("Next = %v, want %v", got, want)
+	}
+
+	at := time.Date(2024, 3, 10, 6, 30, 0, 0, ny)
+	if !d.Last(at).Equal(at) || !d.Next(at).Equal(at.AddDate(0, 0, 1)) {
+		t.Errorf("exactly at the due time: Last=%v Next=%v", d.Last(at), d.Next(at))
+	}
+}
+
+func TestDaily_CatchUpThenSchedule(t *testing.T) {
+	// the bot starts Jan 16 10:00 in New York, after missing midnight
+	fake := clock.NewFake(time.Date(2024, 1, 16, 15, 0, 0, 0, time.UTC))
+	cal := calendar.New("America/New_York", calendar.WithClock(fake))
+
+	var (
+		mu   sync.Mutex
+		runs []time.Time
+	)
+	job := func(_ context.Context, due time.Time) error {
+		mu.Lock()
+		defer mu.Unlock()
+		runs = append(runs, due)
+		return nil
+	}
+	d, _ := NewDaily("decay", "00:00", cal, job)
+
+	fire := make(chan time.Time)
+	waits := make(chan time.Duration)
+	d.after = func(wait time.Duration) <-chan time.Time {
+		waits <- wait
+		return fire
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() { d.Start(ctx); close(done) }()
+
+	if wait := <-waits; wait != 14*time.Hour {
+		t.Errorf("should sleep until midnight, got %v", wait)
+	}
+	fake.Advance(14 * time.Hour)
+	fire <- time.Time{}
+	if wait := <-waits; wait != 24*time.Hour {
+		t.Errorf("next run should be a day later, got %v", wait)
+	}
+	cancel()
+	<-done
+
+	ny := cal.Location()
+	want := []time.Time{time.Date(2024, 1, 16, 0, 0, 0, 0, ny), time.Date(2024, 1, 17, 0, 0, 0, 0, ny)}
+	if len(runs) != 2 || !runs[0].Equal(want[0]) || !runs[1].Equal(want[1]) {
+		t.Errorf("expected the missed midnight then the next one, got %v", runs)
+	}
+}
+
+func TestDaily_CatchesUpEveryMissedDay(t *testing.T) {
+	// midnight Jan 14 completed, then the bot was down until Jan 17 10:00
+	fake := clock.NewFake(time.Date(2024, 1, 17, 10, 0, 0, 0, time.UTC))
+	cal := calendar.New("UTC", calendar.WithClock(fake))
+	locks := job_lock.NewMemoryJobLockRepository()
+	jan14 := time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC)
+	locks.TryLock(context.Background(), "decay", "replica-a", jan14, time.Minute)
+	locks.Complete(context.Background(), "decay", "replica-a", jan14)
+
+	var runs []time.Time
+	job := func(_ context.Context, due time.Time) error {
+		runs = append(runs, due)
+		return nil
+	}
+	d, _ := NewDaily("decay", "00:00", cal, job, WithLocker(locks, "replica-a"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.after = func(time.Duration) <-chan time.Time {
+		cancel() // stop once the catch-up is done
+		return nil
+	}
+	d.Start(ctx)
+
+	want := []time.Time{jan14.AddDate(0, 0, 1), jan14.AddDate(0, 0, 2), jan14.AddDate(0, 0, 3)}
+	if len(runs) != len(want) {
+		t.Fatalf("expected the 3 missed midnights, got %v", runs)
+	}
+	for i := range want {
+		if !runs[i].Equal(want[i]) {
+			t.Errorf("run %d = %v, want %v", i, runs[i], want[i])
+		}
+	}
+	if last, _ := locks.LastDue(context.Background(), "decay"); last == nil || !last.Equal(want[2]) {
+		t.Errorf("last completed due time = %v, want %v", last, want[2])
+	}
+
+	// a failed day stops the catch-up, so it is retried before later days
+	runs = nil
+	fails, _ := NewDaily("fails", "00:00", cal, func(_ context.Context, due time.Time) error {
+		runs = append(runs, due)
+		return errors.New("db down")
+	}, WithLocker(locks, "replica-a"))
+	locks.TryLock(context.Background(), "fails", "replica-a", jan14, time.Minute)
+	locks.Complete(context.Background(), "fails", "replica-a", jan14)
+	ctx, cancel = context.WithCancel(context.Background())
+	fails.after = func(time.Duration) <-chan time.Time {
+		cancel()
+		return nil
+	}
+	fails.Start(ctx)
+	if len(runs) != 1 || !runs[0].Equal(want[0]) {
+		t.Errorf("expected only Jan 15 to be tried, got %v", runs)
+	}
+}
+
+func TestDaily_LockRunsOnce(t *testing.T) {
+	locks := job_lock.NewMemoryJobLockRepository()
+	cal := calendar.New("UTC")
+	due := time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC)
+
+	runs := 0
+	job := func(context.Context, time.Time) error { runs++; return nil }
+	a, _ := NewDaily("decay/net/#cats", "00:00", cal, job, WithLocker(locks, "replica-a"))
+	b, _ := NewDaily("decay/net/#cats", "00:00", cal, job, WithLocker(locks, "replica-b"))
+
+	// replica a is mid-run
+	locks.TryLock(context.Background(), "decay/net/#cats", "replica-a", due, time.Minute)
+	if ran, err := b.RunDue(context.Background(), due); ran || err != nil {
+		t.Errorf("b should skip while a holds the lease, ran=%v err=%v", ran, err)
+	}
+	locks.Unlock(context.Background(), "decay/net/#cats", "replica-a")
+
+	if ran, _ := a.RunDue(context.Background(), due); !ran {
+		t.Error("free lease should run")
+	}
+	// b's timer fires a moment later: the due time is done, don't run it again
+	if ran, _ := b.RunDue(context.Background(), due); ran || runs != 1 {
+		t.Errorf("a completed due time must not run again, ran=%v runs=%d", ran, runs)
+	}
+	if ran, _ := b.RunDue(context.Background(), due.Add(24*time.Hour)); !ran || runs != 2 {
+		t.Errorf("the next due time should run, ran=%v runs=%d", ran, runs)
+	}
+
+	// a failed run is released, so it can be retried
+	fail, _ := NewDaily("fail/net/#cats", "00:00", cal, func(context.Context, time.Time) error { return errors.New("db down") }, WithLocker(locks, "replica-a"))
+	if ran, err := fail.RunDue(context.Background(), due); !ran || err == nil {
+		t.Fatalf("failing job: ran=%v err=%v", ran, err)
+	}
+	if ok, _ := locks.TryLock(context.Background(), "fail/net/#cats", "replica-b", due, time.Minute); !ok {
+		t.Error("a failed due time should be free to retry")
+	}
+}
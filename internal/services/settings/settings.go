@@ -0,0 +1,327 @@
+package settings
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_settings"
+	"github.com/MyelinBots/catbot-go/internal/logging"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+)
+
+/*
+SETTINGS
+Game knobs a channel can tune with !purrito set. Defaults come from config
+(spawn timings) and the game's built-in values; overrides are stored per
+channel and applied live to that channel's CatActions.
+*/
+
+// Settings is one channel's effective configuration.
+type Settings struct {
+	SpawnWindow    time.Duration
+	MinRespawn     time.Duration
+	MaxRespawn     time.Duration
+	AcceptChance   int           // % chance pet/love/feed/laser are accepted
+	CatnipLove     int           // love gained from a good catnip
+	CatnipCooldown time.Duration // between two catnips of the same player
+	DailyDecay     int           // love lost per day by bonded players who didn't visit
+	Timezone       string        // where the game day starts and ends
+	Disabled       []string      // commands turned off in the channel
+}
+
+const (
+	KeySpawnWindow    = "spawn_window"
+	KeyMinRespawn     = "min_respawn"
+	KeyMaxRespawn     = "max_respawn"
+	KeyAcceptChance   = "accept_chance"
+	KeyCatnipLove     = "catnip_love"
+	KeyCatnipCooldown = "catnip_cooldown"
+	KeyDailyDecay     = "decay"
+	KeyTimezone       = "timezone"
+	KeyDisabled       = "disabled"
+)
+
+// Keys lists every setting in display order.
+var Keys = []string{
+	KeySpawnWindow, KeyMinRespawn, KeyMaxRespawn,
+	KeyAcceptChance, KeyCatnipLove, KeyCatnipCooldown,
+	KeyDailyDecay, KeyTimezone, KeyDisabled,
+}
+
+// Defaults are the game's built-in values.
+func Defaults() Settings {
+	return Settings{
+		SpawnWindow:    30 * time.Minute,
+		MinRespawn:     30 * time.Minute,
+		MaxRespawn:     30 * time.Minute,
+		AcceptChance:   60,
+		CatnipLove:     3,
+		CatnipCooldown: 24 * time.Hour,
+		DailyDecay:     5,
+		Timezone:       calendar.DefaultTimezone,
+	}
+}
+
+// Enabled reports whether command may be used in the channel.
+func (s Settings) Enabled(command string) bool {
+	for _, d := range s.Disabled {
+		if strings.EqualFold(d, command) {
+			return false
+		}
+	}
+	return true
+}
+
+// Get renders the value of key ("" for an unknown key).
+func (s Settings) Get(key string) string {
+	switch key {
+	case KeySpawnWindow:
+		return formatDuration(s.SpawnWindow)
+	case KeyMinRespawn:
+		return formatDuration(s.MinRespawn)
+	case KeyMaxRespawn:
+		return formatDuration(s.MaxRespawn)
+	case KeyAcceptChance:
+		return strconv.Itoa(s.AcceptChance) + "%"
+	case KeyCatnipLove:
+		return strconv.Itoa(s.CatnipLove)
+	case KeyCatnipCooldown:
+		return formatDuration(s.CatnipCooldown)
+	case KeyDailyDecay:
+		return strconv.Itoa(s.DailyDecay)
+	case KeyTimezone:
+		return s.Timezone
+	case KeyDisabled:
+		if len(s.Disabled) == 0 {
+			return "none"
+		}
+		return strings.Join(s.Disabled, ",")
+	}
+	return ""
+}
+
+// With returns s with key set from value, or an error explaining what's accepted.
+func (s Settings) With(key, value string) (Settings, error) {
+	next, err := s.set(key, value)
+	if err != nil {
+		return s, err
+	}
+	if next.MinRespawn > next.MaxRespawn {
+		return s, invalidf("%s (%s) can't be longer than %s (%s)",
+			KeyMinRespawn, formatDuration(next.MinRespawn), KeyMaxRespawn, formatDuration(next.MaxRespawn))
+	}
+	return next, nil
+}
+
+// Known reports whether key is a setting.
+func Known(key string) bool {
+	for _, k := range Keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// set parses one value without checking it against the other settings.
+func (s Settings) set(key, value string) (Settings, error) {
+	value = strings.TrimSpace(value)
+
+	switch key {
+	case KeySpawnWindow:
+		d, err := parseDuration(key, value, time.Minute)
+		if err != nil {
+			return s, err
+		}
+		s.SpawnWindow = d
+	case KeyMinRespawn:
+		d, err := parseDuration(key, value, time.Minute)
+		if err != nil {
+			return s, err
+		}
+		s.MinRespawn = d
+	case KeyMaxRespawn:
+		d, err := parseDuration(key, value, time.Minute)
+		if err != nil {
+			return s, err
+		}
+		s.MaxRespawn = d
+	case KeyAcceptChance:
+		n, err := parsePercent(key, value)
+		if err != nil {
+			return s, err
+		}
+		s.AcceptChance = n
+	case KeyCatnipLove:
+		n, err := parsePercent(key, value)
+		if err != nil {
+			return s, err
+		}
+		s.CatnipLove = n
+	case KeyCatnipCooldown:
+		d, err := parseDuration(key, value, 0)
+		if err != nil {
+			return s, err
+		}
+		s.CatnipCooldown = d
+	case KeyDailyDecay:
+		n, err := parsePercent(key, value)
+		if err != nil {
+			return s, err
+		}
+		s.DailyDecay = n
+	case KeyTimezone:
+		loc, err := calendar.LoadLocation(value)
+		if err != nil || value == "" {
+			return s, invalidf("%s wants an IANA name like Asia/Bangkok or UTC", key)
+		}
+		s.Timezone = loc.String()
+	case KeyDisabled:
+		s.Disabled = nil
+		if strings.EqualFold(value, "none") {
+			break
+		}
+		for _, c := range strings.Split(value, ",") {
+			if c = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(c), "!")); c != "" {
+				s.Disabled = append(s.Disabled, c)
+			}
+		}
+		sort.Strings(s.Disabled)
+	default:
+		return s, unknownKey(key)
+	}
+	return s, nil
+}
+
+// InvalidError is a key or value a channel can't set; it says what's accepted.
+type InvalidError struct {
+	Reason string
+}
+
+func (e *InvalidError) Error() string { return e.Reason }
+
+func invalidf(format string, args ...any) error {
+	return &InvalidError{Reason: fmt.Sprintf(format, args...)}
+}
+
+func unknownKey(key string) error {
+	return invalidf("unknown setting %q, try: %s", key, strings.Join(Keys, ", "))
+}
+
+// parseDuration accepts Go durations ("45m", "1h30m") or a bare number of unit.
+func parseDuration(key, value string, unit time.Duration) (time.Duration, error) {
+	if n, err := strconv.Atoi(value); err == nil && unit > 0 {
+		value = (time.Duration(n) * unit).String()
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 || (unit > 0 && d < time.Minute) {
+		return 0, invalidf("%s wants a duration like 30m or 2h", key)
+	}
+	return d, nil
+}
+
+func parsePercent(key, value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil || n < 0 || n > 100 {
+		return 0, invalidf("%s wants a number from 0 to 100", key)
+	}
+	return n, nil
+}
+
+// formatDuration drops the zero units: 30m instead of 30m0s.
+func formatDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+/*
+STORE
+Loads and saves a channel's overrides on top of the defaults.
+*/
+
+type Store struct {
+	repo     channel_settings.ChannelSettingsRepository
+	defaults Settings
+}
+
+func NewStore(repo channel_settings.ChannelSettingsRepository, defaults Settings) *Store {
+	return &Store{repo: repo, defaults: defaults}
+}
+
+// Defaults are the settings of a channel without overrides.
+func (st *Store) Defaults() Settings { return st.defaults }
+
+// Load returns the channel's settings. Stored values that no longer validate
+// are logged and skipped.
+func (st *Store) Load(ctx context.Context, network, channel string) (Settings, error) {
+	overrides, err := st.repo.GetSettings(ctx, network, channel)
+	if err != nil {
+		return st.defaults, err
+	}
+	return st.apply(ctx, overrides), nil
+}
+
+// Set validates and stores one override and returns the new settings. A value
+// that doesn't validate is an *InvalidError; anything else is a storage failure.
+func (st *Store) Set(ctx context.Context, network, channel, key, value, by string) (Settings, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+
+	current, err := st.Load(ctx, network, channel)
+	if err != nil {
+		return current, fmt.Errorf("load settings: %w", err)
+	}
+	next, err := current.With(key, value)
+	if err != nil {
+		return current, err
+	}
+	if err := st.repo.SetSetting(ctx, network, channel, key, next.Get(key), by); err != nil {
+		return current, fmt.Errorf("save %s: %w", key, err)
+	}
+	return next, nil
+}
+
+// Reset drops an override, so the default applies again.
+func (st *Store) Reset(ctx context.Context, network, channel, key string) (Settings, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	if !Known(key) {
+		return st.defaults, unknownKey(key)
+	}
+	if err := st.repo.DeleteSetting(ctx, network, channel, key); err != nil {
+		return st.defaults, fmt.Errorf("reset %s: %w", key, err)
+	}
+	return st.Load(ctx, network, channel)
+}
+
+// apply layers overrides on the defaults. The respawn bounds are checked
+// once at the end, so a stored pair is accepted whatever order it was set in.
+func (st *Store) apply(ctx context.Context, overrides map[string]string) Settings {
+	log := logging.FromContext(ctx)
+	s := st.defaults
+	for _, key := range Keys {
+		value, ok := overrides[key]
+		if !ok {
+			continue
+		}
+		next, err := s.set(key, value)
+		if err != nil {
+			log.Warn("ignoring invalid setting", "key", key, "value", value, "error", err)
+			continue
+		}
+		s = next
+	}
+	if s.MinRespawn > s.MaxRespawn {
+		log.Warn("ignoring respawn settings", KeyMinRespawn, s.Get(KeyMinRespawn), KeyMaxRespawn, s.Get(KeyMaxRespawn))
+		s.MinRespawn, s.MaxRespawn = st.defaults.MinRespawn, st.defaults.MaxRespawn
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+package settings
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/channel_settings"
+)
+
+func TestWith(t *testing.T) {
+	s := Defaults() // applied in order
+
+	tests := []struct {
+		key, value string
+		want       string // Get(key) afterwards, "" = error
+	}{
+		{KeySpawnWindow, "45m", "45m"},
+		{KeySpawnWindow, "90", "1h30m"},
+		{KeySpawnWindow, "10s", ""},
+		{KeyMaxRespawn, "2h", "2h"},
+		{KeyMinRespawn, "1h", "1h"},
+		{KeyAcceptChance, "75%", "75%"},
+		{KeyAcceptChance, "101", ""},
+		{KeyCatnipCooldown, "0s", "0s"},
+		{KeyCatnipCooldown, "soon", ""},
+		{KeyDailyDecay, "0", "0"},
+		{KeyTimezone, "Asia/Bangkok", "Asia/Bangkok"},
+		{KeyTimezone, "Mars/Olympus", ""},
+		{KeyDisabled, "!Slap, kick", "kick,slap"},
+		{KeyDisabled, "none", "none"},
+		{"colour", "orange", ""},
+	}
+	for _, tt := range tests {
+		next, err := s.With(tt.key, tt.value)
+		if tt.want == "" {
+			var invalid *InvalidError
+			if !errors.As(err, &invalid) {
+				t.Errorf("%s=%s: expected an InvalidError, got %v", tt.key, tt.value, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s=%s: %v", tt.key, tt.value, err)
+			continue
+		}
+		if got := next.Get(tt.key); got != tt.want {
+			t.Errorf("%s=%s: got %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+		s = next
+	}
+
+	if _, err := s.With(KeyMinRespawn, "3h"); err == nil {
+		t.Error("min_respawn above max_respawn should fail")
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	repo := channel_settings.NewMemoryChannelSettingsRepository()
+	defaults := Defaults()
+	defaults.SpawnWindow = 10 * time.Minute
+	store := NewStore(repo, defaults)
+
+	// raise max before min so both are stored
+	if _, err := store.Set(ctx, "net", "#a", KeyMaxRespawn, "3h", "op"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(ctx, "net", "#a", KeyMinRespawn, "2h", "op"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Set(ctx, "net", "#a", KeyAcceptChance, "200", "op"); err == nil {
+		t.Error("invalid value should not be stored")
+	}
+
+	s, err := store.Load(ctx, "net", "#A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.MinRespawn != 2*time.Hour || s.MaxRespawn != 3*time.Hour || s.SpawnWindow != 10*time.Minute {
+		t.Errorf("got %+v", s)
+	}
+	if other, _ := store.Load(ctx, "net", "#b"); other.MinRespawn != defaults.MinRespawn {
+		t.Error("settings must not leak to other channels")
+	}
+
+	// stored values that don't validate are skipped
+	repo.SetSetting(ctx, "net", "#a", KeyAcceptChance, "lots", "op")
+	if s, _ := store.Load(ctx, "net", "#a"); s.AcceptChance != defaults.AcceptChance {
+		t.Errorf("bad stored value should fall back, got %d", s.AcceptChance)
+	}
+
+	s, err = store.Reset(ctx, "net", "#a", KeyMinRespawn)
+	if err != nil || s.MinRespawn != defaults.MinRespawn {
+		t.Errorf("reset: %v %v", s.MinRespawn, err)
+	}
+	if _, err := store.Reset(ctx, "net", "#a", "colour"); err == nil {
+		t.Error("unknown key should fail")
+	}
+}
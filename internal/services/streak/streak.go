@@ -0,0 +1,120 @@
+package streak
+
+import (
+	"context"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+)
+
+type Result struct {
+	Advanced bool // false if today's bond was already counted
+	Current  int
+	Highest  int
+	Unlocks  []bondrewards.Unlock
+}
+
+type Service interface {
+	// Call after a successful (accepted) interaction with Purrito
+	RecordInteraction(ctx context.Context, nick, network, channel string) (Result, error)
+
+	// Break resets the current streak (HighestStreak is kept)
+	Break(ctx context.Context, nick, network, channel string) error
+}
+
+type Impl struct {
+	repo cat_player.CatPlayerRepository
+	cal  *calendar.Calendar
+}
+
+// Option configures the service.
+type Option func(*Impl)
+
+// WithCalendar sets the game day (default: calendar.DefaultTimezone).
+func WithCalendar(cal *calendar.Calendar) Option {
+	return func(s *Impl) { s.cal = cal }
+}
+
+func New(repo cat_player.CatPlayerRepository, opts ...Option) Service {
+	s := &Impl{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.cal == nil {
+		s.cal = calendar.Default()
+	}
+	return s
+}
+
+func (s *Impl) RecordInteraction(ctx context.Context, nick, network, channel string) (Result, error) {
+	now := s.cal.Now()
+
+	p, err := s.repo.GetPlayerByName(ctx, nick, network, channel)
+	if err != nil {
+		return Result{}, err
+	}
+	if p == nil {
+		if err := s.repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+			Name:    nick,
+			Network: network,
+			Channel: channel,
+		}); err != nil {
+			return Result{}, err
+		}
+		p, err = s.repo.GetPlayerByName(ctx, nick, network, channel)
+		if err != nil || p == nil {
+			return Result{}, err
+		}
+	}
+
+	// One step per game day
+	if p.LastStreakAt != nil && s.cal.SameDay(*p.LastStreakAt, now) {
+		return Result{
+			Current: p.CurrentStreak,
+			Highest: p.HighestStreak,
+		}, nil
+	}
+
+	// if last bonded day was yesterday -> streak++, else start over at 1
+	newCurrent := 1
+	if p.LastStreakAt != nil && p.CurrentStreak > 0 && s.cal.DayBefore(*p.LastStreakAt, now) {
+		newCurrent = p.CurrentStreak + 1
+	}
+
+	newHighest := p.HighestStreak
+	if newCurrent > newHighest {
+		newHighest = newCurrent
+	}
+
+	if err := s.repo.SetStreak(ctx, nick, network, channel, newCurrent, newHighest, now); err != nil {
+		return Result{}, err
+	}
+
+	// Gift7..Gift45 unlock when HighestStreak crosses a milestone (only once per gift)
+	var unlocks []bondrewards.Unlock
+	mask := 0
+	for _, u := range bondrewards.GiftUnlocks(p.HighestStreak, newHighest) {
+		if p.GiftsUnlocked&u.GiftMask != 0 {
+			continue
+		}
+		unlocks = append(unlocks, u)
+		mask |= u.GiftMask
+	}
+	if mask != 0 {
+		if err := s.repo.AddGiftsUnlocked(ctx, nick, network, channel, mask); err != nil {
+			return Result{}, err
+		}
+	}
+
+	return Result{
+		Advanced: true,
+		Current:  newCurrent,
+		Highest:  newHighest,
+		Unlocks:  unlocks,
+	}, nil
+}
+
+func (s *Impl) Break(ctx context.Context, nick, network, channel string) error {
+	return s.repo.ResetStreak(ctx, nick, network, channel)
+}
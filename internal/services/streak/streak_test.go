@@ -0,0 +1,242 @@
+package streak
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/MyelinBots/catbot-go/internal/db/repositories/cat_player"
+	"github.com/MyelinBots/catbot-go/internal/services/bondrewards"
+	"github.com/MyelinBots/catbot-go/internal/services/calendar"
+	"github.com/MyelinBots/catbot-go/internal/services/clock"
+)
+
+// Tests
+
+func TestNew(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo)
+	if svc == nil {
+		t.Fatal("New() returned nil")
+	}
+}
+
+func TestRecordInteraction_NewPlayer(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo)
+	ctx := context.Background()
+
+	res, err := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Advanced {
+		t.Error("first interaction should advance the streak")
+	}
+	if res.Current != 1 || res.Highest != 1 {
+		t.Errorf("expected current=1 highest=1, got current=%d highest=%d", res.Current, res.Highest)
+	}
+
+	p, _ := repo.GetPlayerByName(ctx, "player1", "testnet", "#testchan")
+	if p == nil || p.LastStreakAt == nil {
+		t.Fatal("expected player row with LastStreakAt set")
+	}
+}
+
+func TestRecordInteraction_SameDay(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo)
+	ctx := context.Background()
+
+	_, _ = svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	res, err := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Advanced {
+		t.Error("second interaction on the same day should not advance the streak")
+	}
+	if res.Current != 1 {
+		t.Errorf("expected current streak 1, got %d", res.Current)
+	}
+}
+
+func TestRecordInteraction_ConsecutiveDay(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	yesterday := svc.cal.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 4,
+		HighestStreak: 4,
+		LastStreakAt:  &yesterday,
+	})
+
+	res, err := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Current != 5 {
+		t.Errorf("expected current streak 5, got %d", res.Current)
+	}
+	if res.Highest != 5 {
+		t.Errorf("expected highest streak 5, got %d", res.Highest)
+	}
+}
+
+func TestRecordInteraction_MissedDayResets(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	threeDaysAgo := svc.cal.Now().AddDate(0, 0, -3)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 10,
+		HighestStreak: 12,
+		LastStreakAt:  &threeDaysAgo,
+	})
+
+	res, err := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Current != 1 {
+		t.Errorf("expected streak reset to 1, got %d", res.Current)
+	}
+	if res.Highest != 12 {
+		t.Errorf("highest streak should be kept at 12, got %d", res.Highest)
+	}
+}
+
+func TestRecordInteraction_BrokenStreakRestartsAtOne(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	// decay broke the streak yesterday (current=0) even though last bond was yesterday
+	yesterday := svc.cal.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 0,
+		HighestStreak: 8,
+		LastStreakAt:  &yesterday,
+	})
+
+	res, _ := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if res.Current != 1 {
+		t.Errorf("expected streak to restart at 1, got %d", res.Current)
+	}
+}
+
+func TestRecordInteraction_GiftUnlock(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	yesterday := svc.cal.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 6,
+		HighestStreak: 6,
+		LastStreakAt:  &yesterday,
+	})
+
+	res, err := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Unlocks) != 1 || res.Unlocks[0].GiftMask != bondrewards.Gift7 {
+		t.Fatalf("expected Gift7 unlock, got %+v", res.Unlocks)
+	}
+
+	p, _ := repo.GetPlayerByName(ctx, "player1", "testnet", "#testchan")
+	if p.GiftsUnlocked&bondrewards.Gift7 == 0 {
+		t.Error("expected Gift7 bit to be persisted")
+	}
+}
+
+func TestRecordInteraction_GiftAlreadyUnlocked(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	yesterday := svc.cal.Now().AddDate(0, 0, -1)
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 6,
+		HighestStreak: 6,
+		LastStreakAt:  &yesterday,
+		GiftsUnlocked: bondrewards.Gift7,
+	})
+
+	res, _ := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+	if len(res.Unlocks) != 0 {
+		t.Errorf("expected no new unlocks, got %+v", res.Unlocks)
+	}
+}
+
+func TestBreak(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	svc := New(repo).(*Impl)
+	ctx := context.Background()
+
+	now := svc.cal.Now()
+	repo.UpsertPlayer(ctx, &cat_player.CatPlayer{
+		Name:          "player1",
+		Network:       "testnet",
+		Channel:       "#testchan",
+		CurrentStreak: 9,
+		HighestStreak: 9,
+		LastStreakAt:  &now,
+	})
+
+	if err := svc.Break(ctx, "player1", "testnet", "#testchan"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p, _ := repo.GetPlayerByName(ctx, "player1", "testnet", "#testchan")
+	if p.CurrentStreak != 0 {
+		t.Errorf("expected current streak 0, got %d", p.CurrentStreak)
+	}
+	if p.HighestStreak != 9 {
+		t.Errorf("highest streak should be kept at 9, got %d", p.HighestStreak)
+	}
+}
+
+func TestRecordInteraction_AcrossMidnight(t *testing.T) {
+	repo := cat_player.NewMemoryPlayerRepository()
+	cal := calendar.New("America/New_York")
+	fake := clock.NewFake(time.Date(2024, 1, 15, 23, 50, 0, 0, cal.Location()))
+	svc := New(repo, WithCalendar(calendar.New("America/New_York", calendar.WithClock(fake))))
+	ctx := context.Background()
+
+	svc.RecordInteraction(ctx, "player1", "testnet", "#testchan")
+
+	fake.Advance(5 * time.Minute) // 23:55, same day
+	if res, _ := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan"); res.Advanced || res.Current != 1 {
+		t.Errorf("same game day should not advance, got %+v", res)
+	}
+
+	fake.Advance(10 * time.Minute) // 00:05, next day
+	if res, _ := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan"); !res.Advanced || res.Current != 2 {
+		t.Errorf("next game day should advance to 2, got %+v", res)
+	}
+
+	fake.Advance(48 * time.Hour) // skipped a day
+	if res, _ := svc.RecordInteraction(ctx, "player1", "testnet", "#testchan"); res.Current != 1 || res.Highest != 2 {
+		t.Errorf("a missed day restarts at 1, got %+v", res)
+	}
+}
@@ -0,0 +1,340 @@
+package throttle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+THROTTLE
+Rate limits commands per nick, per host and per channel.
+Over the limit a command is dropped silently, or with a single "slow down" notice
+per burst; hosts that keep hammering are ignored for a while.
+*/
+
+// Limit allows Count commands per Per. The zero Limit means unlimited.
+type Limit struct {
+	Count int
+	Per   time.Duration
+}
+
+func (l Limit) unlimited() bool { return l.Count <= 0 || l.Per <= 0 }
+
+func (l Limit) String() string {
+	if l.unlimited() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%d/%s", l.Count, l.Per)
+}
+
+// ParseLimit reads "3/1m" (3 per minute). "" and "0" mean unlimited.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+
+	count, per, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: want count/duration, e.g. 3/1m", s)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || n < 0 {
+		return Limit{}, fmt.Errorf("limit %q: bad count", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: bad duration", s)
+	}
+	return Limit{Count: n, Per: d}, nil
+}
+
+// Scope is what a limit is counted against.
+type Scope string
+
+const (
+	ScopeNick    Scope = "nick"
+	ScopeHost    Scope = "host"
+	ScopeChannel Scope = "channel"
+)
+
+var scopes = []Scope{ScopeNick, ScopeHost, ScopeChannel}
+
+// Rule holds the limits of one command (or the defaults).
+type Rule map[Scope]Limit
+
+// ParseRules reads per-command overrides: "status=3/1m,toplove:channel=4/1m".
+// The scope defaults to nick.
+func ParseRules(s string) (map[string]Rule, error) {
+	out := make(map[string]Rule)
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("throttle rule %q: want command[:scope]=count/duration", part)
+		}
+		cmd, scope, hasScope := strings.Cut(strings.ToLower(strings.TrimSpace(key)), ":")
+		cmd = strings.TrimPrefix(cmd, "!")
+		if !hasScope {
+			scope = string(ScopeNick)
+		}
+		if !validScope(Scope(scope)) {
+			return nil, fmt.Errorf("throttle rule %q: unknown scope %q", part, scope)
+		}
+
+		limit, err := ParseLimit(value)
+		if err != nil {
+			return nil, err
+		}
+		if out[cmd] == nil {
+			out[cmd] = Rule{}
+		}
+		out[cmd][Scope(scope)] = limit
+	}
+	return out, nil
+}
+
+func validScope(s Scope) bool {
+	for _, v := range scopes {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+type Config struct {
+	Default  Rule            // applies to every command
+	Commands map[string]Rule // per command overrides, by scope
+	Notify   bool            // send one "slow down" notice per burst instead of dropping silently
+
+	IgnoreAfter  int           // violations within IgnoreWindow before a host is ignored, 0 = never
+	IgnoreWindow time.Duration // default 1m
+	IgnoreFor    time.Duration // default 10m
+}
+
+// Decision is the outcome of Check.
+type Decision int
+
+const (
+	Allow  Decision = iota
+	Drop            // over the limit, say nothing
+	Warn            // over the limit, tell the user once
+	Ignore          // host is temporarily ignored
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Allow:
+		return "allow"
+	case Drop:
+		return "drop"
+	case Warn:
+		return "warn"
+	default:
+		return "ignore"
+	}
+}
+
+type Throttler struct {
+	cfg Config
+	now func() time.Time
+
+	mu         sync.Mutex
+	buckets    map[string]*bucket     // scope|command|who
+	warned     map[string]bool        // nick|command warned during the current burst
+	violations map[string][]time.Time // host -> recent violations
+	ignored    map[string]time.Time   // host -> ignored until
+	lastPrune  time.Time
+}
+
+func New(cfg Config) *Throttler {
+	if cfg.IgnoreWindow <= 0 {
+		cfg.IgnoreWindow = time.Minute
+	}
+	if cfg.IgnoreFor <= 0 {
+		cfg.IgnoreFor = 10 * time.Minute
+	}
+	return &Throttler{
+		cfg:        cfg,
+		now:        time.Now,
+		buckets:    make(map[string]*bucket),
+		warned:     make(map[string]bool),
+		violations: make(map[string][]time.Time),
+		ignored:    make(map[string]time.Time),
+	}
+}
+
+// limit returns the limit for command in scope: the override if any, else the default.
+func (t *Throttler) limit(command string, scope Scope) Limit {
+	if r, ok := t.cfg.Commands[command]; ok {
+		if l, ok := r[scope]; ok {
+			return l
+		}
+	}
+	return t.cfg.Default[scope]
+}
+
+// Check records one use of command and decides whether it may run.
+func (t *Throttler) Check(command, nick, host, channel string) Decision {
+	command = strings.ToLower(command)
+	nick = strings.ToLower(nick)
+	host = strings.ToLower(host)
+	channel = strings.ToLower(channel)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.prune(now)
+
+	if until, ok := t.ignored[host]; ok && host != "" {
+		if now.Before(until) {
+			return Ignore
+		}
+		delete(t.ignored, host)
+	}
+
+	who := map[Scope]string{ScopeNick: nick, ScopeHost: host, ScopeChannel: channel}
+
+	// check every scope first so a rejected command doesn't consume tokens
+	var used []*bucket
+	for _, scope := range scopes {
+		l := t.limit(command, scope)
+		if l.unlimited() || who[scope] == "" {
+			continue
+		}
+		key := string(scope) + "|" + command + "|" + who[scope]
+		b, ok := t.buckets[key]
+		if !ok {
+			b = newBucket(l, now)
+			t.buckets[key] = b
+		}
+		if !b.ready(now) {
+			return t.violation(command, nick, host, now)
+		}
+		used = append(used, b)
+	}
+
+	for _, b := range used {
+		b.take()
+	}
+	delete(t.warned, nick+"|"+command)
+	return Allow
+}
+
+// violation counts an over-limit command and picks Drop, Warn or Ignore. Caller holds mu.
+func (t *Throttler) violation(command, nick, host string, now time.Time) Decision {
+	if t.cfg.IgnoreAfter > 0 && host != "" {
+		recent := t.violations[host][:0]
+		for _, v := range t.violations[host] {
+			if now.Sub(v) < t.cfg.IgnoreWindow {
+				recent = append(recent, v)
+			}
+		}
+		recent = append(recent, now)
+		t.violations[host] = recent
+
+		if len(recent) >= t.cfg.IgnoreAfter {
+			t.ignored[host] = now.Add(t.cfg.IgnoreFor)
+			delete(t.violations, host)
+			return Ignore
+		}
+	}
+
+	key := nick + "|" + command
+	if t.cfg.Notify && !t.warned[key] {
+		t.warned[key] = true
+		return Warn
+	}
+	return Drop
+}
+
+// Ignored lists hosts currently ignored and until when.
+func (t *Throttler) Ignored() map[string]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	out := make(map[string]time.Time)
+	for h, until := range t.ignored {
+		if now.Before(until) {
+			out[h] = until
+		}
+	}
+	return out
+}
+
+// Unignore lifts an ignore early.
+func (t *Throttler) Unignore(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.ignored, strings.ToLower(host))
+}
+
+// prune drops idle state at most once a minute. Caller holds mu.
+func (t *Throttler) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < time.Minute {
+		return
+	}
+	t.lastPrune = now
+
+	for k, b := range t.buckets {
+		if b.full(now) {
+			delete(t.buckets, k)
+		}
+	}
+	for h, vs := range t.violations {
+		if len(vs) == 0 || now.Sub(vs[len(vs)-1]) >= t.cfg.IgnoreWindow {
+			delete(t.violations, h)
+		}
+	}
+	for h, until := range t.ignored {
+		if !now.Before(until) {
+			delete(t.ignored, h)
+		}
+	}
+}
+
+// --------------------------------------------------
+// Token bucket: Count tokens, refilled evenly over Per
+// --------------------------------------------------
+
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(l Limit, now time.Time) *bucket {
+	return &bucket{limit: l, tokens: float64(l.Count), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens += float64(b.limit.Count) * elapsed.Seconds() / b.limit.Per.Seconds()
+		if max := float64(b.limit.Count); b.tokens > max {
+			b.tokens = max
+		}
+	}
+	b.last = now
+}
+
+func (b *bucket) ready(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= 1
+}
+
+func (b *bucket) take() { b.tokens-- }
+
+func (b *bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= float64(b.limit.Count)
+}
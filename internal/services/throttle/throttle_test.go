@@ -0,0 +1,194 @@
+package throttle
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct{ t time.Time }
+
+func (f *fakeClock) Now() time.Time      { return f.t }
+func (f *fakeClock) Add(d time.Duration) { f.t = f.t.Add(d) }
+
+func newTestThrottler(cfg Config) (*Throttler, *fakeClock) {
+	clock := &fakeClock{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	th := New(cfg)
+	th.now = clock.Now
+	return th, clock
+}
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("3/1m")
+	if err != nil || l.Count != 3 || l.Per != time.Minute {
+		t.Fatalf("got %+v, %v", l, err)
+	}
+	for _, s := range []string{"", "0"} {
+		if l, err := ParseLimit(s); err != nil || !l.unlimited() {
+			t.Errorf("%q should be unlimited, got %+v, %v", s, l, err)
+		}
+	}
+	for _, s := range []string{"3", "x/1m", "3/soon", "3/-1s"} {
+		if _, err := ParseLimit(s); err == nil {
+			t.Errorf("%q should fail", s)
+		}
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("status=3/1m, !TopLove:channel=4/30s,toplove:host=0")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rules["status"][ScopeNick] != (Limit{3, time.Minute}) {
+		t.Errorf("status = %+v", rules["status"])
+	}
+	if rules["toplove"][ScopeChannel] != (Limit{4, 30 * time.Second}) {
+		t.Errorf("toplove channel = %+v", rules["toplove"])
+	}
+	if l, ok := rules["toplove"][ScopeHost]; !ok || !l.unlimited() {
+		t.Errorf("toplove host should be an explicit unlimited override, got %+v", rules["toplove"])
+	}
+
+	for _, s := range []string{"status", "status:planet=1/1m", "status=oops"} {
+		if _, err := ParseRules(s); err == nil {
+			t.Errorf("%q should fail", s)
+		}
+	}
+}
+
+func TestCheck_PerNickLimitRefills(t *testing.T) {
+	th, clock := newTestThrottler(Config{Default: Rule{ScopeNick: {Count: 2, Per: time.Minute}}})
+
+	for i := 0; i < 2; i++ {
+		if d := th.Check("status", "alice", "a.host", "#cats"); d != Allow {
+			t.Fatalf("call %d: got %s", i, d)
+		}
+	}
+	if d := th.Check("status", "ALICE", "a.host", "#cats"); d != Drop {
+		t.Errorf("third call should be dropped, got %s", d)
+	}
+	if d := th.Check("status", "bob", "b.host", "#cats"); d != Allow {
+		t.Errorf("limits are per nick, got %s", d)
+	}
+	if d := th.Check("pet", "alice", "a.host", "#cats"); d != Allow {
+		t.Errorf("limits are per command, got %s", d)
+	}
+
+	clock.Add(30 * time.Second)
+	if d := th.Check("status", "alice", "a.host", "#cats"); d != Allow {
+		t.Errorf("one token back after half the window, got %s", d)
+	}
+}
+
+func TestCheck_HostAndChannelScopes(t *testing.T) {
+	th, _ := newTestThrottler(Config{Default: Rule{
+		ScopeHost:    {Count: 2, Per: time.Minute},
+		ScopeChannel: {Count: 3, Per: time.Minute},
+	}})
+
+	// same host, different nicks
+	th.Check("pet", "alice", "shared.host", "#cats")
+	th.Check("pet", "alice_", "shared.host", "#cats")
+	if d := th.Check("pet", "alice__", "shared.host", "#cats"); d != Drop {
+		t.Errorf("host limit should apply across nicks, got %s", d)
+	}
+
+	// the rejected call must not have used a channel token
+	if d := th.Check("pet", "bob", "b.host", "#cats"); d != Allow {
+		t.Errorf("got %s", d)
+	}
+	if d := th.Check("pet", "carol", "c.host", "#cats"); d != Drop {
+		t.Errorf("channel limit of 3 reached, got %s", d)
+	}
+	if d := th.Check("pet", "carol", "c.host", "#dogs"); d != Allow {
+		t.Errorf("other channels are not affected, got %s", d)
+	}
+}
+
+func TestCheck_CommandOverride(t *testing.T) {
+	th, _ := newTestThrottler(Config{
+		Default:  Rule{ScopeNick: {Count: 5, Per: time.Minute}},
+		Commands: map[string]Rule{"toplove": {ScopeNick: {Count: 1, Per: time.Minute}}, "pet": {ScopeNick: {}}},
+	})
+
+	th.Check("toplove", "alice", "", "#cats")
+	if d := th.Check("toplove", "alice", "", "#cats"); d != Drop {
+		t.Errorf("override of 1/min should apply, got %s", d)
+	}
+	for i := 0; i < 20; i++ {
+		if d := th.Check("pet", "alice", "", "#cats"); d != Allow {
+			t.Fatalf("pet is unlimited by override, got %s on call %d", d, i)
+		}
+	}
+}
+
+func TestCheck_WarnOncePerBurst(t *testing.T) {
+	th, clock := newTestThrottler(Config{Default: Rule{ScopeNick: {Count: 1, Per: time.Minute}}, Notify: true})
+
+	th.Check("status", "alice", "", "#cats")
+	if d := th.Check("status", "alice", "", "#cats"); d != Warn {
+		t.Errorf("first violation should warn, got %s", d)
+	}
+	if d := th.Check("status", "alice", "", "#cats"); d != Drop {
+		t.Errorf("second violation should be silent, got %s", d)
+	}
+
+	clock.Add(time.Minute)
+	th.Check("status", "alice", "", "#cats")
+	if d := th.Check("status", "alice", "", "#cats"); d != Warn {
+		t.Errorf("a new burst should warn again, got %s", d)
+	}
+}
+
+func TestCheck_IgnoresAbusiveHost(t *testing.T) {
+	th, clock := newTestThrottler(Config{
+		Default:     Rule{ScopeNick: {Count: 1, Per: time.Minute}},
+		IgnoreAfter: 3,
+		IgnoreFor:   5 * time.Minute,
+	})
+
+	th.Check("pet", "spammer", "bad.host", "#cats")
+	th.Check("pet", "spammer", "bad.host", "#cats")
+	th.Check("pet", "spammer", "bad.host", "#cats")
+	if d := th.Check("pet", "spammer", "bad.host", "#cats"); d != Ignore {
+		t.Fatalf("host should be ignored after 3 violations, got %s", d)
+	}
+	if d := th.Check("status", "newnick", "BAD.host", "#dogs"); d != Ignore {
+		t.Errorf("ignore applies to every nick and command on the host, got %s", d)
+	}
+	if _, ok := th.Ignored()["bad.host"]; !ok {
+		t.Error("Ignored() should list the host")
+	}
+
+	clock.Add(5 * time.Minute)
+	if d := th.Check("pet", "spammer", "bad.host", "#cats"); d != Allow {
+		t.Errorf("ignore should expire, got %s", d)
+	}
+}
+
+func TestCheck_ViolationsOutsideWindowDontCount(t *testing.T) {
+	th, clock := newTestThrottler(Config{
+		Default:      Rule{ScopeNick: {Count: 1, Per: time.Hour}},
+		IgnoreAfter:  3,
+		IgnoreWindow: time.Minute,
+	})
+
+	th.Check("pet", "alice", "a.host", "#cats")
+	for i := 0; i < 5; i++ {
+		if d := th.Check("pet", "alice", "a.host", "#cats"); d == Ignore {
+			t.Fatalf("violation %d spread over time should not ignore", i)
+		}
+		clock.Add(45 * time.Second)
+	}
+}
+
+func TestUnignore(t *testing.T) {
+	th, _ := newTestThrottler(Config{Default: Rule{ScopeNick: {Count: 1, Per: time.Hour}}, IgnoreAfter: 1})
+
+	th.Check("pet", "alice", "a.host", "#cats")
+	th.Check("pet", "alice", "a.host", "#cats")
+	th.Unignore("A.HOST")
+	if d := th.Check("feed", "alice", "a.host", "#cats"); d != Allow {
+		t.Errorf("got %s after unignore", d)
+	}
+}
@@ -1,7 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/MyelinBots/catbot-go/config"
 	"github.com/MyelinBots/catbot-go/internal/bot"
@@ -16,8 +20,10 @@ func main() {
 		log.Fatalf("failed to connect to database")
 	}
 
-	// Just start the bot, no args
-	if err := bot.StartBot(); err != nil {
+	// Just start the bot, no args; SIGINT/SIGTERM shut it down cleanly
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := bot.StartBot(ctx, bot.Options{}); err != nil {
 		log.Fatalf("error starting bot: %v", err)
 	}
 }